@@ -22,6 +22,11 @@ import (
 //	@name						Authorization
 //	@description				Private server authentication
 
+//	@securitydefinitions.apikey	AdminToken
+//	@in							header
+//	@name						Authorization
+//	@description				Operator authentication with the admin token
+
 //	@title			SMS Gateway for Android™ API
 //	@version		{APP_VERSION}
 //	@description	This API provides programmatic access to sending SMS messages on Android devices. Features include sending SMS, checking message status, device management, webhook configuration, and system health checks.
@@ -21,11 +21,17 @@ type Config struct {
 	Database Database  `yaml:"database"` // database config
 	FCM      FCMConfig `yaml:"fcm"`      // firebase cloud messaging config
 	Tasks    Tasks     `yaml:"tasks"`    // tasks config
+	Messages Messages  `yaml:"messages"` // messages config
+	Links    Links     `yaml:"links"`    // short links config
+	Devices  Devices   `yaml:"devices"`  // devices config
+	JWT      JWT       `yaml:"jwt"`      // access tokens config
+	Lockout  Lockout   `yaml:"lockout"`  // authentication lockout config, limits apply per instance
 }
 
 type Gateway struct {
 	Mode         GatewayMode `yaml:"mode"          envconfig:"GATEWAY__MODE"`          // gateway mode: public or private
 	PrivateToken string      `yaml:"private_token" envconfig:"GATEWAY__PRIVATE_TOKEN"` // device registration token in private mode
+	AdminToken   string      `yaml:"admin_token"   envconfig:"GATEWAY__ADMIN_TOKEN"`   // admin API token, the API is disabled if empty
 }
 
 type HTTP struct {
@@ -54,13 +60,68 @@ type FCMConfig struct {
 }
 
 type Tasks struct {
-	Hashing HashingTask `yaml:"hashing"`
+	Hashing   HashingTask   `yaml:"hashing"`
+	Campaigns CampaignsTask `yaml:"campaigns"`
+	Devices   DevicesTask   `yaml:"devices"`
+	Schedule  ScheduleTask  `yaml:"schedule"`
 }
 
 type HashingTask struct {
 	IntervalSeconds uint16 `yaml:"interval_seconds" envconfig:"TASKS__HASHING__INTERVAL_SECONDS"` // hashing interval in seconds
 }
 
+type CampaignsTask struct {
+	IntervalSeconds uint16 `yaml:"interval_seconds" envconfig:"TASKS__CAMPAIGNS__INTERVAL_SECONDS"` // campaigns processing interval in seconds
+	BatchLimit      uint16 `yaml:"batch_limit"      envconfig:"TASKS__CAMPAIGNS__BATCH_LIMIT"`      // max messages enqueued per campaign in a single run
+}
+
+type DevicesTask struct {
+	IntervalSeconds uint16 `yaml:"interval_seconds" envconfig:"TASKS__DEVICES__INTERVAL_SECONDS"` // interval of checking devices going offline in seconds
+}
+
+type ScheduleTask struct {
+	IntervalSeconds uint16 `yaml:"interval_seconds" envconfig:"TASKS__SCHEDULE__INTERVAL_SECONDS"` // interval of notifying devices about held messages that became due in seconds
+}
+
+type Messages struct {
+	SchedulingPolicy     string `yaml:"scheduling_policy"      envconfig:"MESSAGES__SCHEDULING_POLICY"`      // pending messages order: strict, fifo or aging
+	AgingIntervalSeconds uint32 `yaml:"aging_interval_seconds" envconfig:"MESSAGES__AGING_INTERVAL_SECONDS"` // waiting time that adds one priority point (aging policy only)
+}
+
+type Links struct {
+	BaseURL string `yaml:"base_url" envconfig:"LINKS__BASE_URL"` // public URL of the gateway for short links, shortening is disabled if empty
+}
+
+type Devices struct {
+	StaleSeconds   uint32 `yaml:"stale_seconds"   envconfig:"DEVICES__STALE_SECONDS"`   // devices not seen for longer are stale
+	OfflineSeconds uint32 `yaml:"offline_seconds" envconfig:"DEVICES__OFFLINE_SECONDS"` // devices not seen for longer are offline
+}
+
+type JWT struct {
+	Keys              []string `yaml:"keys"                envconfig:"JWT__KEYS"`                // signing keys as id:secret, the first one signs new tokens, all verify; an ephemeral per-instance key is used if empty
+	AccessTTLSeconds  uint32   `yaml:"access_ttl_seconds"  envconfig:"JWT__ACCESS_TTL_SECONDS"`  // access token lifetime in seconds
+	RefreshTTLSeconds uint32   `yaml:"refresh_ttl_seconds" envconfig:"JWT__REFRESH_TTL_SECONDS"` // refresh token lifetime in seconds
+}
+
+type Lockout struct {
+	Basic BasicLockout `yaml:"basic"` // Basic credentials policy, attempts are counted per login and IP
+	Code  CodeLockout  `yaml:"code"`  // one-time user codes policy, attempts are counted per IP
+}
+
+type BasicLockout struct {
+	MaxAttempts        uint16 `yaml:"max_attempts"         envconfig:"LOCKOUT__BASIC__MAX_ATTEMPTS"`         // failed attempts in the window before the lockout, 0 to disable
+	WindowSeconds      uint32 `yaml:"window_seconds"       envconfig:"LOCKOUT__BASIC__WINDOW_SECONDS"`       // window to count failed attempts in
+	BaseLockoutSeconds uint32 `yaml:"base_lockout_seconds" envconfig:"LOCKOUT__BASIC__BASE_LOCKOUT_SECONDS"` // first lockout duration, doubled on every next lockout
+	MaxLockoutSeconds  uint32 `yaml:"max_lockout_seconds"  envconfig:"LOCKOUT__BASIC__MAX_LOCKOUT_SECONDS"`  // max lockout duration
+}
+
+type CodeLockout struct {
+	MaxAttempts        uint16 `yaml:"max_attempts"         envconfig:"LOCKOUT__CODE__MAX_ATTEMPTS"`         // failed attempts in the window before the lockout, 0 to disable
+	WindowSeconds      uint32 `yaml:"window_seconds"       envconfig:"LOCKOUT__CODE__WINDOW_SECONDS"`       // window to count failed attempts in
+	BaseLockoutSeconds uint32 `yaml:"base_lockout_seconds" envconfig:"LOCKOUT__CODE__BASE_LOCKOUT_SECONDS"` // first lockout duration, doubled on every next lockout
+	MaxLockoutSeconds  uint32 `yaml:"max_lockout_seconds"  envconfig:"LOCKOUT__CODE__MAX_LOCKOUT_SECONDS"`  // max lockout duration
+}
+
 var defaultConfig = Config{
 	Gateway: Gateway{Mode: GatewayModePublic},
 	HTTP: HTTP{
@@ -82,6 +143,42 @@ var defaultConfig = Config{
 		Hashing: HashingTask{
 			IntervalSeconds: uint16(15 * 60),
 		},
+		Campaigns: CampaignsTask{
+			IntervalSeconds: 5,
+			BatchLimit:      100,
+		},
+		Devices: DevicesTask{
+			IntervalSeconds: 60,
+		},
+		Schedule: ScheduleTask{
+			IntervalSeconds: 60,
+		},
+	},
+	Messages: Messages{
+		SchedulingPolicy:     "strict",
+		AgingIntervalSeconds: 60,
+	},
+	Devices: Devices{
+		StaleSeconds:   10 * 60,
+		OfflineSeconds: 60 * 60,
+	},
+	JWT: JWT{
+		AccessTTLSeconds:  15 * 60,
+		RefreshTTLSeconds: 30 * 24 * 60 * 60,
+	},
+	Lockout: Lockout{
+		Basic: BasicLockout{
+			MaxAttempts:        5,
+			WindowSeconds:      15 * 60,
+			BaseLockoutSeconds: 60,
+			MaxLockoutSeconds:  60 * 60,
+		},
+		Code: CodeLockout{
+			MaxAttempts:        5,
+			WindowSeconds:      5 * 60,
+			BaseLockoutSeconds: 5 * 60,
+			MaxLockoutSeconds:  24 * 60 * 60,
+		},
 	},
 }
 
@@ -117,7 +214,6 @@ func Load() (Config, error) {
 	return cfg, nil
 }
 
-
 // func Load() (Config, error) {
 // 	cfg := defaultConfig
 // 	log.Printf("CONFIG_PATH == %s", os.Getenv("CONFIG_PATH"))
@@ -5,9 +5,13 @@ import (
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
 	// "github.com/capcom6/go-infra-fx/config"
 	"github.com/capcom6/go-infra-fx/db"
 	"github.com/capcom6/go-infra-fx/http"
@@ -66,6 +70,11 @@ var Module = fx.Module(
 			Interval: time.Duration(cfg.Tasks.Hashing.IntervalSeconds) * time.Second,
 		}
 	}),
+	fx.Provide(func(cfg Config) messages.ScheduleTaskConfig {
+		return messages.ScheduleTaskConfig{
+			Interval: time.Duration(cfg.Tasks.Schedule.IntervalSeconds) * time.Second,
+		}
+	}),
 	fx.Provide(func(cfg Config) auth.Config {
 		return auth.Config{
 			Mode:         auth.Mode(cfg.Gateway.Mode),
@@ -75,16 +84,63 @@ var Module = fx.Module(
 	fx.Provide(func(cfg Config) handlers.Config {
 		return handlers.Config{
 			GatewayMode: handlers.GatewayMode(cfg.Gateway.Mode),
+			AdminToken:  cfg.Gateway.AdminToken,
 		}
 	}),
 	fx.Provide(func(cfg Config) messages.Config {
 		return messages.Config{
 			ProcessedLifetime: 30 * 24 * time.Hour, //TODO: make it configurable
+
+			SchedulingPolicy: messages.SchedulingPolicy(cfg.Messages.SchedulingPolicy),
+			AgingInterval:    time.Duration(cfg.Messages.AgingIntervalSeconds) * time.Second,
+		}
+	}),
+	fx.Provide(func(cfg Config) campaigns.Config {
+		return campaigns.Config{
+			Interval:   time.Duration(cfg.Tasks.Campaigns.IntervalSeconds) * time.Second,
+			BatchLimit: int(cfg.Tasks.Campaigns.BatchLimit),
+		}
+	}),
+	fx.Provide(func(cfg Config) links.Config {
+		return links.Config{
+			BaseURL: cfg.Links.BaseURL,
 		}
 	}),
 	fx.Provide(func(cfg Config) devices.Config {
 		return devices.Config{
 			UnusedLifetime: 365 * 24 * time.Hour, //TODO: make it configurable
+
+			StaleAfter:     time.Duration(cfg.Devices.StaleSeconds) * time.Second,
+			OfflineAfter:   time.Duration(cfg.Devices.OfflineSeconds) * time.Second,
+			StatusInterval: time.Duration(cfg.Tasks.Devices.IntervalSeconds) * time.Second,
+		}
+	}),
+	fx.Provide(func(cfg Config) (tokens.Config, error) {
+		keys, err := tokens.ParseKeys(cfg.JWT.Keys)
+		if err != nil {
+			return tokens.Config{}, err
+		}
+
+		return tokens.Config{
+			Keys:       keys,
+			AccessTTL:  time.Duration(cfg.JWT.AccessTTLSeconds) * time.Second,
+			RefreshTTL: time.Duration(cfg.JWT.RefreshTTLSeconds) * time.Second,
+		}, nil
+	}),
+	fx.Provide(func(cfg Config) lockout.Config {
+		return lockout.Config{
+			Basic: lockout.Policy{
+				MaxAttempts: int(cfg.Lockout.Basic.MaxAttempts),
+				Window:      time.Duration(cfg.Lockout.Basic.WindowSeconds) * time.Second,
+				BaseLockout: time.Duration(cfg.Lockout.Basic.BaseLockoutSeconds) * time.Second,
+				MaxLockout:  time.Duration(cfg.Lockout.Basic.MaxLockoutSeconds) * time.Second,
+			},
+			Code: lockout.Policy{
+				MaxAttempts: int(cfg.Lockout.Code.MaxAttempts),
+				Window:      time.Duration(cfg.Lockout.Code.WindowSeconds) * time.Second,
+				BaseLockout: time.Duration(cfg.Lockout.Code.BaseLockoutSeconds) * time.Second,
+				MaxLockout:  time.Duration(cfg.Lockout.Code.MaxLockoutSeconds) * time.Second,
+			},
 		}
 	}),
 )
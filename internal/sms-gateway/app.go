@@ -6,15 +6,29 @@ import (
 
 	appconfig "github.com/android-sms-gateway/server/internal/config"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/apikeys"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/autoreplies"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/cleaner"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/commands"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contentpolicy"
 	appdb "github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/health"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/metrics"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/otp"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/suppressions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/webhooks"
 	"github.com/capcom6/go-infra-fx/cli"
 	"github.com/capcom6/go-infra-fx/db"
@@ -45,6 +59,20 @@ var Module = fx.Module(
 	devices.Module,
 	metrics.Module,
 	cleaner.Module,
+	campaigns.Module,
+	contacts.Module,
+	contentpolicy.Module,
+	links.Module,
+	otp.Module,
+	suppressions.Module,
+	autoreplies.Module,
+	telemetry.Module,
+	commands.Module,
+	quotas.Module,
+	apikeys.Module,
+	tokens.Module,
+	organizations.Module,
+	lockout.Module,
 )
 
 func Run() {
@@ -71,6 +99,8 @@ type StartParams struct {
 	MessagesService *messages.Service
 	PushService     *push.Service
 	CleanerService  *cleaner.Service
+	CampaignsSvc    *campaigns.Service
+	DevicesMonitor  *devices.Monitor
 }
 
 func Start(p StartParams) error {
@@ -101,6 +131,18 @@ func Start(p StartParams) error {
 				p.CleanerService.Run(ctx)
 			}()
 
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.CampaignsSvc.Run(ctx)
+			}()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.DevicesMonitor.Run(ctx)
+			}()
+
 			p.Logger.Info("Service started")
 
 			return nil
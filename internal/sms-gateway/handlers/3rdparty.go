@@ -1,14 +1,27 @@
 package handlers
 
 import (
+	apikeysctrl "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/apikeys"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/autoreplies"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contacts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contentpolicy"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/logs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	orgsctrl "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/otp"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/settings"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/suppressions"
+	tokensctrl "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/tokens"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/webhooks"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/apikeys"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
@@ -18,14 +31,27 @@ import (
 type ThirdPartyHandlerParams struct {
 	fx.In
 
-	HealthHandler   *healthHandler
-	MessagesHandler *messages.ThirdPartyController
-	WebhooksHandler *webhooks.ThirdPartyController
-	DevicesHandler  *devices.ThirdPartyController
-	SettingsHandler *settings.ThirdPartyController
-	LogsHandler     *logs.ThirdPartyController
-
-	AuthSvc *auth.Service
+	HealthHandler       *healthHandler
+	MessagesHandler     *messages.ThirdPartyController
+	WebhooksHandler     *webhooks.ThirdPartyController
+	DevicesHandler      *devices.ThirdPartyController
+	SettingsHandler     *settings.ThirdPartyController
+	LogsHandler         *logs.ThirdPartyController
+	CampaignsHandler    *campaigns.ThirdPartyController
+	ContactsHandler     *contacts.ThirdPartyController
+	PolicyHandler       *contentpolicy.ThirdPartyController
+	OTPHandler          *otp.ThirdPartyController
+	AutorepliesHandler  *autoreplies.ThirdPartyController
+	SuppressionsHandler *suppressions.ThirdPartyController
+	APIKeysHandler      *apikeysctrl.ThirdPartyController
+	TokensHandler       *tokensctrl.ThirdPartyController
+	OrgsHandler         *orgsctrl.ThirdPartyController
+
+	AuthSvc    *auth.Service
+	APIKeysSvc *apikeys.Service
+	TokensSvc  *tokens.Service
+	OrgsSvc    *organizations.Service
+	LockoutSvc *lockout.Service
 
 	Logger    *zap.Logger
 	Validator *validator.Validate
@@ -34,14 +60,27 @@ type ThirdPartyHandlerParams struct {
 type thirdPartyHandler struct {
 	base.Handler
 
-	healthHandler   *healthHandler
-	messagesHandler *messages.ThirdPartyController
-	webhooksHandler *webhooks.ThirdPartyController
-	devicesHandler  *devices.ThirdPartyController
-	settingsHandler *settings.ThirdPartyController
-	logsHandler     *logs.ThirdPartyController
-
-	authSvc *auth.Service
+	healthHandler       *healthHandler
+	messagesHandler     *messages.ThirdPartyController
+	webhooksHandler     *webhooks.ThirdPartyController
+	devicesHandler      *devices.ThirdPartyController
+	settingsHandler     *settings.ThirdPartyController
+	logsHandler         *logs.ThirdPartyController
+	campaignsHandler    *campaigns.ThirdPartyController
+	contactsHandler     *contacts.ThirdPartyController
+	policyHandler       *contentpolicy.ThirdPartyController
+	otpHandler          *otp.ThirdPartyController
+	autorepliesHandler  *autoreplies.ThirdPartyController
+	suppressionsHandler *suppressions.ThirdPartyController
+	apikeysHandler      *apikeysctrl.ThirdPartyController
+	tokensHandler       *tokensctrl.ThirdPartyController
+	orgsHandler         *orgsctrl.ThirdPartyController
+
+	authSvc    *auth.Service
+	apikeysSvc *apikeys.Service
+	tokensSvc  *tokens.Service
+	orgsSvc    *organizations.Service
+	lockoutSvc *lockout.Service
 }
 
 func (h *thirdPartyHandler) Register(router fiber.Router) {
@@ -49,33 +88,94 @@ func (h *thirdPartyHandler) Register(router fiber.Router) {
 
 	h.healthHandler.Register(router)
 
+	// tokens are issued before the common authorization
+	h.tokensHandler.Register(router.Group("/auth"))
+
 	router.Use(
-		userauth.NewBasic(h.authSvc),
+		userauth.NewBasic(h.authSvc, h.lockoutSvc),
+		userauth.NewAPIKey(h.apikeysSvc),
+		userauth.NewJWT(h.tokensSvc, h.authSvc),
 		userauth.UserRequired(),
+		userauth.OrganizationRequired(h.orgsSvc),
 	)
 
-	h.messagesHandler.Register(router.Group("/message")) // TODO: remove after 2025-12-31
-	h.messagesHandler.Register(router.Group("/messages"))
+	messagesAccess := []fiber.Handler{
+		userauth.ScopeRequired(apikeys.ScopeMessagesRead, apikeys.ScopeMessagesSend),
+		userauth.RoleRequired(organizations.RoleViewer, organizations.RoleSender),
+	}
+	devicesAccess := []fiber.Handler{
+		userauth.ScopeRequired(apikeys.ScopeDevices, apikeys.ScopeDevices),
+		userauth.RoleRequired(organizations.RoleViewer, organizations.RoleAdmin),
+	}
+	settingsAccess := []fiber.Handler{
+		userauth.ScopeRequired(apikeys.ScopeSettings, apikeys.ScopeSettings),
+		userauth.RoleRequired(organizations.RoleViewer, organizations.RoleAdmin),
+	}
+	// contacts are managed by senders to run campaigns
+	contactsAccess := []fiber.Handler{
+		userauth.ScopeRequired(apikeys.ScopeMessagesRead, apikeys.ScopeMessagesSend),
+		userauth.RoleRequired(organizations.RoleViewer, organizations.RoleSender),
+	}
+
+	h.messagesHandler.Register(router.Group("/message", messagesAccess...)) // TODO: remove after 2025-12-31
+	h.messagesHandler.Register(router.Group("/messages", messagesAccess...))
+
+	h.devicesHandler.Register(router.Group("/device", devicesAccess...)) // TODO: remove after 2025-07-11
+	h.devicesHandler.Register(router.Group("/devices", devicesAccess...))
+
+	h.settingsHandler.Register(router.Group("/settings", settingsAccess...))
+
+	h.webhooksHandler.Register(router.Group("/webhooks",
+		userauth.ScopeRequired(apikeys.ScopeWebhooks, apikeys.ScopeWebhooks),
+		userauth.RoleRequired(organizations.RoleViewer, organizations.RoleAdmin),
+	))
+
+	h.logsHandler.Register(router.Group("/logs", devicesAccess...))
+
+	h.campaignsHandler.Register(router.Group("/campaigns", messagesAccess...))
+
+	h.contactsHandler.Register(router.Group("/contacts", contactsAccess...))
+
+	h.policyHandler.Register(router.Group("/content-policy", settingsAccess...))
+
+	h.otpHandler.Register(router.Group("/otp", messagesAccess...))
 
-	h.devicesHandler.Register(router.Group("/device")) // TODO: remove after 2025-07-11
-	h.devicesHandler.Register(router.Group("/devices"))
+	h.autorepliesHandler.Register(router.Group("/autoreplies", settingsAccess...))
 
-	h.settingsHandler.Register(router.Group("/settings"))
+	h.suppressionsHandler.Register(router.Group("/suppressions", settingsAccess...))
 
-	h.webhooksHandler.Register(router.Group("/webhooks"))
+	// keys and members can't be managed with keys
+	h.apikeysHandler.Register(router.Group("/keys",
+		userauth.ScopeRequired("", ""),
+		userauth.RoleRequired(organizations.RoleAdmin, organizations.RoleAdmin),
+	))
 
-	h.logsHandler.Register(router.Group("/logs"))
+	// roles are checked by the service
+	h.orgsHandler.Register(router.Group("/organization", userauth.ScopeRequired("", "")))
 }
 
 func newThirdPartyHandler(params ThirdPartyHandlerParams) *thirdPartyHandler {
 	return &thirdPartyHandler{
-		Handler:         base.Handler{Logger: params.Logger.Named("ThirdPartyHandler"), Validator: params.Validator},
-		healthHandler:   params.HealthHandler,
-		messagesHandler: params.MessagesHandler,
-		webhooksHandler: params.WebhooksHandler,
-		devicesHandler:  params.DevicesHandler,
-		settingsHandler: params.SettingsHandler,
-		logsHandler:     params.LogsHandler,
-		authSvc:         params.AuthSvc,
+		Handler:             base.Handler{Logger: params.Logger.Named("ThirdPartyHandler"), Validator: params.Validator},
+		healthHandler:       params.HealthHandler,
+		messagesHandler:     params.MessagesHandler,
+		webhooksHandler:     params.WebhooksHandler,
+		devicesHandler:      params.DevicesHandler,
+		settingsHandler:     params.SettingsHandler,
+		logsHandler:         params.LogsHandler,
+		campaignsHandler:    params.CampaignsHandler,
+		contactsHandler:     params.ContactsHandler,
+		policyHandler:       params.PolicyHandler,
+		otpHandler:          params.OTPHandler,
+		autorepliesHandler:  params.AutorepliesHandler,
+		suppressionsHandler: params.SuppressionsHandler,
+		apikeysHandler:      params.APIKeysHandler,
+		tokensHandler:       params.TokensHandler,
+		orgsHandler:         params.OrgsHandler,
+		authSvc:             params.AuthSvc,
+		apikeysSvc:          params.APIKeysSvc,
+		tokensSvc:           params.TokensSvc,
+		orgsSvc:             params.OrgsSvc,
+		lockoutSvc:          params.LockoutSvc,
 	}
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/subtle"
+
+	devicesctrl "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/users"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"go.uber.org/fx"
+)
+
+type adminHandler struct {
+	config Config
+
+	usersCtrl   *users.AdminController
+	devicesCtrl *devicesctrl.AdminController
+}
+
+type adminHandlerParams struct {
+	fx.In
+
+	Config Config
+
+	UsersCtrl   *users.AdminController
+	DevicesCtrl *devicesctrl.AdminController
+}
+
+func newAdminHandler(params adminHandlerParams) *adminHandler {
+	return &adminHandler{
+		config:      params.Config,
+		usersCtrl:   params.UsersCtrl,
+		devicesCtrl: params.DevicesCtrl,
+	}
+}
+
+func (h *adminHandler) Register(router fiber.Router) {
+	// register only if the admin token is configured
+	if h.config.AdminToken == "" {
+		return
+	}
+
+	router = router.Group("/admin/v1")
+
+	router.Use(keyauth.New(keyauth.Config{
+		Validator: func(c *fiber.Ctx, token string) (bool, error) {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(h.config.AdminToken)) != 1 {
+				return false, keyauth.ErrMissingOrMalformedAPIKey
+			}
+			return true, nil
+		},
+	}))
+
+	h.usersCtrl.Register(router.Group("/users"))
+	h.devicesCtrl.Register(router.Group("/devices"))
+}
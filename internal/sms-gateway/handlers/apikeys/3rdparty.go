@@ -0,0 +1,125 @@
+package apikeys
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/apikeys"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	APIKeysSvc *apikeys.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	apikeysSvc *apikeys.Service
+}
+
+//	@Summary		List API keys
+//	@Description	Returns API keys of the user, newest first. Keys themselves are not returned
+//	@Security		ApiAuth
+//	@Tags			User, API keys
+//	@Produce		json
+//	@Success		200	{object}	[]apikeys.KeyOut			"API keys"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/keys [get]
+//
+// List API keys
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	items, err := h.apikeysSvc.Select(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't select keys: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Create API key
+//	@Description	Creates API key with the scopes. The key is used as a bearer token and is returned only once. Keys can't be used to manage keys
+//	@Security		ApiAuth
+//	@Tags			User, API keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		apikeys.KeyIn				true	"API key"
+//	@Success		201		{object}	apikeys.CreatedKeyOut		"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/keys [post]
+//
+// Create API key
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := apikeys.KeyIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	key, err := h.apikeysSvc.Create(user.ID, req)
+	if errors.Is(err, apikeys.ErrValidation) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't create key: %w", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+//	@Summary		Revoke API key
+//	@Description	Deletes API key, it can't be used anymore
+//	@Security		ApiAuth
+//	@Tags			User, API keys
+//	@Produce		json
+//	@Param			id	path	string	true	"API key ID"
+//	@Success		204	"Revoked"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	smsgateway.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"API key not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/keys/{id} [delete]
+//
+// Revoke API key
+func (h *ThirdPartyController) delete(user models.User, c *fiber.Ctx) error {
+	err := h.apikeysSvc.Delete(user.ID, c.Params("id"))
+	if errors.Is(err, apikeys.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't delete key: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.list))
+	router.Post("", userauth.WithUser(h.post))
+	router.Delete("/:id", userauth.WithUser(h.delete))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("apikeys"),
+			Validator: params.Validator,
+		},
+		apikeysSvc: params.APIKeysSvc,
+	}
+}
@@ -0,0 +1,181 @@
+package autoreplies
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/autoreplies"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	AutorepliesSvc *autoreplies.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	autorepliesSvc *autoreplies.Service
+}
+
+//	@Summary		List auto-reply rules
+//	@Description	Returns auto-reply rules in the order of evaluation
+//	@Security		ApiAuth
+//	@Tags			User, Auto-replies
+//	@Produce		json
+//	@Success		200	{object}	[]autoreplies.RuleOut		"Rule list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/autoreplies [get]
+//
+// List auto-reply rules
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	items, err := h.autorepliesSvc.Select(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't select rules: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Create auto-reply rule
+//	@Description	Creates auto-reply rule. Rules are evaluated in the order of creation against messages reported by devices, the first matching rule replies via the same device and applies the suppression list action to the sender
+//	@Security		ApiAuth
+//	@Tags			User, Auto-replies
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		autoreplies.RuleIn			true	"Rule"
+//	@Success		201		{object}	autoreplies.RuleOut			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Rule with such ID already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/autoreplies [post]
+//
+// Create auto-reply rule
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := autoreplies.RuleIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	rule, err := h.autorepliesSvc.Create(user.ID, req)
+	if err != nil {
+		return h.handleError(err, "can't create rule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+//	@Summary		Get auto-reply rule
+//	@Description	Returns auto-reply rule by ID
+//	@Security		ApiAuth
+//	@Tags			User, Auto-replies
+//	@Produce		json
+//	@Param			id	path		string						true	"Rule ID"
+//	@Success		200	{object}	autoreplies.RuleOut			"Rule"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Rule not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/autoreplies/{id} [get]
+//
+// Get auto-reply rule
+func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
+	rule, err := h.autorepliesSvc.Get(user.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(err, "can't get rule")
+	}
+
+	return c.JSON(rule)
+}
+
+//	@Summary		Update auto-reply rule
+//	@Description	Replaces auto-reply rule
+//	@Security		ApiAuth
+//	@Tags			User, Auto-replies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Rule ID"
+//	@Param			request	body		autoreplies.RuleIn			true	"Rule"
+//	@Success		200		{object}	autoreplies.RuleOut			"Updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Rule not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/autoreplies/{id} [put]
+//
+// Update auto-reply rule
+func (h *ThirdPartyController) put(user models.User, c *fiber.Ctx) error {
+	req := autoreplies.RuleIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	rule, err := h.autorepliesSvc.Update(user.ID, c.Params("id"), req)
+	if err != nil {
+		return h.handleError(err, "can't update rule")
+	}
+
+	return c.JSON(rule)
+}
+
+//	@Summary		Delete auto-reply rule
+//	@Description	Deletes auto-reply rule
+//	@Security		ApiAuth
+//	@Tags			User, Auto-replies
+//	@Produce		json
+//	@Param			id	path	string	true	"Rule ID"
+//	@Success		204	"Rule deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Rule not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/autoreplies/{id} [delete]
+//
+// Delete auto-reply rule
+func (h *ThirdPartyController) delete(user models.User, c *fiber.Ctx) error {
+	if err := h.autorepliesSvc.Delete(user.ID, c.Params("id")); err != nil {
+		return h.handleError(err, "can't delete rule")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) handleError(err error, message string) error {
+	if errors.Is(err, autoreplies.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, autoreplies.ErrAlreadyExists) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.list))
+	router.Post("", userauth.WithUser(h.post))
+	router.Get("/:id", userauth.WithUser(h.get))
+	router.Put("/:id", userauth.WithUser(h.put))
+	router.Delete("/:id", userauth.WithUser(h.delete))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("autoreplies"),
+			Validator: params.Validator,
+		},
+		autorepliesSvc: params.AutorepliesSvc,
+	}
+}
@@ -0,0 +1,103 @@
+package autoreplies
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/autoreplies"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type mobileControllerParams struct {
+	fx.In
+
+	AutorepliesSvc *autoreplies.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type incomingRequest struct {
+	// Message ID on the device, repeated reports of the same message are ignored
+	ID string `json:"id" validate:"required,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Sender phone number
+	PhoneNumber string `json:"phoneNumber" validate:"required,max=128" example:"79990001234"`
+	// Message text
+	Message string `json:"message" validate:"required,max=65535" example:"STOP"`
+	// SIM card number (1-3) the message was received on
+	SimNumber *uint8 `json:"simNumber,omitempty" validate:"omitempty,min=1,max=3" example:"1"`
+	// Time the message was received
+	ReceivedAt time.Time `json:"receivedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+type incomingResponse struct {
+	// ID of the matched auto-reply rule
+	RuleID string `json:"ruleId,omitempty" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// ID of the enqueued reply message
+	ReplyID string `json:"replyId,omitempty" example:"PyDmBQZZXYmyxMwED8Fzy"`
+}
+
+// MobileController receives messages reported by devices to evaluate
+// auto-reply rules.
+type MobileController struct {
+	base.Handler
+
+	autorepliesSvc *autoreplies.Service
+}
+
+//	@Summary		Report incoming message
+//	@Description	Reports a received message. Auto-reply rules are evaluated and the reply is enqueued on the same device. Repeated reports of the message return the result of the first one. The sender is replied to at most once in 10 minutes
+//	@Security		MobileToken
+//	@Tags			Device, Auto-replies
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		incomingRequest				true	"Incoming message"
+//	@Success		202		{object}	incomingResponse			"Message processed"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/inbox [post]
+//
+// Report incoming message
+func (h *MobileController) post(device models.Device, c *fiber.Ctx) error {
+	req := incomingRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	result, err := h.autorepliesSvc.HandleIncoming(device, autoreplies.IncomingMessage{
+		ID:          req.ID,
+		PhoneNumber: req.PhoneNumber,
+		Message:     req.Message,
+		SimNumber:   req.SimNumber,
+		ReceivedAt:  req.ReceivedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("can't handle incoming message: %w", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(incomingResponse{
+		RuleID:  result.RuleID,
+		ReplyID: result.ReplyID,
+	})
+}
+
+func (h *MobileController) Register(router fiber.Router) {
+	router.Post("", deviceauth.WithDevice(h.post))
+}
+
+func NewMobileController(params mobileControllerParams) *MobileController {
+	return &MobileController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("autoreplies"),
+			Validator: params.Validator,
+		},
+		autorepliesSvc: params.AutorepliesSvc,
+	}
+}
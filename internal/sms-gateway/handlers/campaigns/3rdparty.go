@@ -0,0 +1,248 @@
+package campaigns
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const maxRecipients = 100000
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	CampaignsSvc *campaigns.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type postRequest struct {
+	// Campaign ID (if not set - will be generated)
+	ID string `json:"id,omitempty" form:"id" validate:"omitempty,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Name
+	Name string `json:"name" form:"name" validate:"required,max=128" example:"Black Friday"`
+	// Message or template with `{{variable}}` placeholders
+	Message string `json:"message" form:"message" validate:"required,max=65535" example:"Hello {{name}}!"`
+	// Recipients, can be combined with uploaded CSV file
+	Recipients []campaigns.RecipientIn `json:"recipients,omitempty" form:"-" validate:"max=100000,dive"`
+	// Contact groups to add to recipients, contact fields are available as template variables
+	GroupIDs []string `json:"groupIds,omitempty" form:"groupIds" validate:"max=100,dive,required,max=36"`
+	// Messages per minute
+	RatePerMinute uint32 `json:"ratePerMinute" form:"ratePerMinute" validate:"required,min=1,max=6000" example:"60"`
+	// Devices to spread messages across, all user's devices if empty
+	DeviceIDs []string `json:"deviceIds,omitempty" form:"deviceIds" validate:"max=100,dive,required,max=21"`
+
+	// SIM card number (1-3), if not set - default SIM will be used
+	SimNumber *uint8 `json:"simNumber,omitempty" form:"simNumber" validate:"omitempty,max=3" example:"1"`
+	// With delivery report
+	WithDeliveryReport *bool `json:"withDeliveryReport,omitempty" form:"withDeliveryReport" example:"true"`
+	// Priority, messages with values greater than `99` will bypass limits and delays
+	Priority smsgateway.MessagePriority `json:"priority,omitempty" form:"priority" validate:"omitempty,min=-128,max=127" example:"0"`
+	// Time to live of each message in seconds
+	TTL *uint64 `json:"ttl,omitempty" form:"ttl" validate:"omitempty,min=5" example:"86400"`
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	campaignsSvc *campaigns.Service
+}
+
+//	@Summary		List campaigns
+//	@Description	Returns list of campaigns without progress
+//	@Security		ApiAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Success		200	{object}	[]campaigns.CampaignOut		"Campaign list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns [get]
+//
+// List campaigns
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	items, err := h.campaignsSvc.Select(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't select campaigns: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Create campaign
+//	@Description	Creates campaign. Messages are enqueued gradually at the campaign rate and spread across the selected devices. Recipients can be provided inline as JSON, as a CSV file in the `file` field of a multipart form or as contact groups. The CSV file must have a header row with a `phone` column, other columns are available as template variables
+//	@Security		ApiAuth
+//	@Tags			User, Campaigns
+//	@Accept			json
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			request	body		postRequest					true	"Campaign"
+//	@Success		201		{object}	campaigns.CampaignOut		"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns [post]
+//
+// Create campaign
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := postRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		recipients, err := h.parseFile(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		req.Recipients = append(req.Recipients, recipients...)
+	}
+
+	if len(req.Recipients) > maxRecipients {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Too many recipients, max %d", maxRecipients))
+	}
+
+	campaign, err := h.campaignsSvc.Create(user.ID, campaigns.CampaignIn{
+		ID:            req.ID,
+		Name:          req.Name,
+		Message:       req.Message,
+		Recipients:    req.Recipients,
+		GroupIDs:      req.GroupIDs,
+		RatePerMinute: req.RatePerMinute,
+		DeviceIDs:     req.DeviceIDs,
+
+		SimNumber:          req.SimNumber,
+		WithDeliveryReport: req.WithDeliveryReport,
+		Priority:           req.Priority,
+		TTL:                req.TTL,
+	})
+	if err != nil {
+		if campaigns.IsValidationError(err) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		return fmt.Errorf("can't create campaign: %w", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(campaign)
+}
+
+//	@Summary		Get campaign
+//	@Description	Returns campaign with progress by recipient and message states
+//	@Security		ApiAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id	path		string						true	"Campaign ID"
+//	@Success		200	{object}	campaigns.CampaignOut		"Campaign"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id} [get]
+//
+// Get campaign
+func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
+	campaign, err := h.campaignsSvc.Get(user.ID, c.Params("id"))
+	if errors.Is(err, campaigns.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get campaign: %w", err)
+	}
+
+	return c.JSON(campaign)
+}
+
+//	@Summary		Change campaign state
+//	@Description	Pauses, resumes or cancels campaign. Cancellation skips recipients that were not enqueued yet
+//	@Security		ApiAuth
+//	@Tags			User, Campaigns
+//	@Produce		json
+//	@Param			id		path	string	true	"Campaign ID"
+//	@Param			action	path	string	true	"Action"	Enums(pause, resume, cancel)
+//	@Success		204		"State changed"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Campaign not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Action not allowed in the current state"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/campaigns/{id}/{action} [post]
+//
+// Change campaign state
+func (h *ThirdPartyController) postAction(user models.User, c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var err error
+	switch c.Params("action") {
+	case "pause":
+		err = h.campaignsSvc.Pause(user.ID, id)
+	case "resume":
+		err = h.campaignsSvc.Resume(user.ID, id)
+	case "cancel":
+		err = h.campaignsSvc.Cancel(user.ID, id)
+	default:
+		return fiber.ErrNotFound
+	}
+
+	if errors.Is(err, campaigns.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, campaigns.ErrInvalidState) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't change campaign state: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) parseFile(c *fiber.Ctx) ([]campaigns.RecipientIn, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, fmt.Errorf("can't parse form: %w", err)
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("can't open file: %w", err)
+	}
+	defer file.Close()
+
+	recipients, err := campaigns.ParseRecipientsCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse file: %w", err)
+	}
+
+	return recipients, nil
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.list))
+	router.Post("", userauth.WithUser(h.post))
+	router.Get(":id", userauth.WithUser(h.get))
+	router.Post(":id/:action", userauth.WithUser(h.postAction))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("campaigns"),
+			Validator: params.Validator,
+		},
+		campaignsSvc: params.CampaignsSvc,
+	}
+}
@@ -9,4 +9,6 @@ const (
 
 type Config struct {
 	GatewayMode GatewayMode
+	// AdminToken protects the admin API, the API is disabled if empty
+	AdminToken string
 }
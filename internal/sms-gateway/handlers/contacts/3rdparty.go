@@ -0,0 +1,413 @@
+package contacts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const maxImportContacts = 100000
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	ContactsSvc *contacts.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type listQuery struct {
+	// Return only contacts of the group
+	GroupID string `query:"groupId" validate:"omitempty,max=36"`
+}
+
+type importRequest struct {
+	// Groups to add imported contacts to
+	GroupIDs []string `form:"groupIds" validate:"max=100,dive,required,max=36"`
+}
+
+type importResponse struct {
+	// Number of created or updated contacts
+	Imported int `json:"imported" example:"100"`
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	contactsSvc *contacts.Service
+}
+
+//	@Summary		List contacts
+//	@Description	Returns list of contacts
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			groupId	query		string						false	"Return only contacts of the group"
+//	@Success		200		{object}	[]contacts.ContactOut		"Contact list"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts [get]
+//
+// List contacts
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	query := listQuery{}
+	if err := h.QueryParserValidator(c, &query); err != nil {
+		return err
+	}
+
+	groupIDs := []string{}
+	if query.GroupID != "" {
+		groupIDs = append(groupIDs, query.GroupID)
+	}
+
+	items, err := h.contactsSvc.SelectContacts(user.ID, groupIDs...)
+	if err != nil {
+		return fmt.Errorf("can't select contacts: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Create contact
+//	@Description	Creates contact. Phone number must be unique among user's contacts
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		contacts.ContactIn			true	"Contact"
+//	@Success		201		{object}	contacts.ContactOut			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Contact with such ID or phone number already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts [post]
+//
+// Create contact
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := contacts.ContactIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	contact, err := h.contactsSvc.CreateContact(user.ID, req)
+	if err != nil {
+		return h.handleError(err, "can't create contact")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(contact)
+}
+
+//	@Summary		Import contacts
+//	@Description	Imports contacts from a CSV file in the `file` field of a multipart form. The file must have a header row with a `phone` column, an optional `name` column, other columns are stored as custom fields. Existing contacts with the same phone number are updated
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file		formData	file						true	"CSV file"
+//	@Param			groupIds	formData	[]string					false	"Groups to add imported contacts to"
+//	@Success		200			{object}	importResponse				"Imported"
+//	@Failure		400			{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/import [post]
+//
+// Import contacts
+func (h *ThirdPartyController) postImport(user models.User, c *fiber.Ctx) error {
+	req := importRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	items, err := h.parseFile(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if len(items) > maxImportContacts {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Too many contacts, max %d", maxImportContacts))
+	}
+
+	for i := range items {
+		if err := h.ValidateStruct(&items[i]); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid contact in row %d: %s", i+2, err.Error()))
+		}
+	}
+
+	imported, err := h.contactsSvc.ImportContacts(user.ID, items, req.GroupIDs)
+	if err != nil {
+		return h.handleError(err, "can't import contacts")
+	}
+
+	return c.JSON(importResponse{Imported: imported})
+}
+
+//	@Summary		Get contact
+//	@Description	Returns contact by ID
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path		string						true	"Contact ID"
+//	@Success		200	{object}	contacts.ContactOut			"Contact"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [get]
+//
+// Get contact
+func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
+	contact, err := h.contactsSvc.GetContact(user.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(err, "can't get contact")
+	}
+
+	return c.JSON(contact)
+}
+
+//	@Summary		Update contact
+//	@Description	Replaces contact data and groups
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Contact ID"
+//	@Param			request	body		contacts.ContactIn			true	"Contact"
+//	@Success		200		{object}	contacts.ContactOut			"Updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Contact with such phone number already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [put]
+//
+// Update contact
+func (h *ThirdPartyController) put(user models.User, c *fiber.Ctx) error {
+	req := contacts.ContactIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	contact, err := h.contactsSvc.UpdateContact(user.ID, c.Params("id"), req)
+	if err != nil {
+		return h.handleError(err, "can't update contact")
+	}
+
+	return c.JSON(contact)
+}
+
+//	@Summary		Delete contact
+//	@Description	Deletes contact
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path	string	true	"Contact ID"
+//	@Success		204	"Contact deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Contact not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/{id} [delete]
+//
+// Delete contact
+func (h *ThirdPartyController) delete(user models.User, c *fiber.Ctx) error {
+	if err := h.contactsSvc.DeleteContact(user.ID, c.Params("id")); err != nil {
+		return h.handleError(err, "can't delete contact")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		List groups
+//	@Description	Returns list of contact groups with number of contacts
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Success		200	{object}	[]contacts.GroupOut			"Group list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups [get]
+//
+// List groups
+func (h *ThirdPartyController) listGroups(user models.User, c *fiber.Ctx) error {
+	items, err := h.contactsSvc.SelectGroups(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't select groups: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Create group
+//	@Description	Creates contact group
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		contacts.GroupIn			true	"Group"
+//	@Success		201		{object}	contacts.GroupOut			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Group with such ID already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups [post]
+//
+// Create group
+func (h *ThirdPartyController) postGroup(user models.User, c *fiber.Ctx) error {
+	req := contacts.GroupIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	group, err := h.contactsSvc.CreateGroup(user.ID, req)
+	if err != nil {
+		return h.handleError(err, "can't create group")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(group)
+}
+
+//	@Summary		Get group
+//	@Description	Returns contact group by ID
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path		string						true	"Group ID"
+//	@Success		200	{object}	contacts.GroupOut			"Group"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [get]
+//
+// Get group
+func (h *ThirdPartyController) getGroup(user models.User, c *fiber.Ctx) error {
+	group, err := h.contactsSvc.GetGroup(user.ID, c.Params("id"))
+	if err != nil {
+		return h.handleError(err, "can't get group")
+	}
+
+	return c.JSON(group)
+}
+
+//	@Summary		Update group
+//	@Description	Renames contact group
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		contacts.GroupIn			true	"Group"
+//	@Success		200		{object}	contacts.GroupOut			"Updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [put]
+//
+// Update group
+func (h *ThirdPartyController) putGroup(user models.User, c *fiber.Ctx) error {
+	req := contacts.GroupIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	group, err := h.contactsSvc.UpdateGroup(user.ID, c.Params("id"), req)
+	if err != nil {
+		return h.handleError(err, "can't update group")
+	}
+
+	return c.JSON(group)
+}
+
+//	@Summary		Delete group
+//	@Description	Deletes contact group. Contacts of the group are not deleted
+//	@Security		ApiAuth
+//	@Tags			User, Contacts
+//	@Produce		json
+//	@Param			id	path	string	true	"Group ID"
+//	@Success		204	"Group deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Group not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/contacts/groups/{id} [delete]
+//
+// Delete group
+func (h *ThirdPartyController) deleteGroup(user models.User, c *fiber.Ctx) error {
+	if err := h.contactsSvc.DeleteGroup(user.ID, c.Params("id")); err != nil {
+		return h.handleError(err, "can't delete group")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) handleError(err error, message string) error {
+	if errors.Is(err, contacts.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, contacts.ErrAlreadyExists) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if contacts.IsValidationError(err) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func (h *ThirdPartyController) parseFile(c *fiber.Ctx) ([]contacts.ContactIn, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, fmt.Errorf("can't parse form: %w", err)
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("can't open file: %w", err)
+	}
+	defer file.Close()
+
+	items, err := contacts.ParseContactsCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse file: %w", err)
+	}
+
+	return items, nil
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("groups", userauth.WithUser(h.listGroups))
+	router.Post("groups", userauth.WithUser(h.postGroup))
+	router.Get("groups/:id", userauth.WithUser(h.getGroup))
+	router.Put("groups/:id", userauth.WithUser(h.putGroup))
+	router.Delete("groups/:id", userauth.WithUser(h.deleteGroup))
+
+	router.Post("import", userauth.WithUser(h.postImport))
+
+	router.Get("", userauth.WithUser(h.list))
+	router.Post("", userauth.WithUser(h.post))
+	router.Get(":id", userauth.WithUser(h.get))
+	router.Put(":id", userauth.WithUser(h.put))
+	router.Delete(":id", userauth.WithUser(h.delete))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("contacts"),
+			Validator: params.Validator,
+		},
+		contactsSvc: params.ContactsSvc,
+	}
+}
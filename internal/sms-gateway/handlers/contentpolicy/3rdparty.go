@@ -0,0 +1,114 @@
+package contentpolicy
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contentpolicy"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	ContentPolicySvc *contentpolicy.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	contentPolicySvc *contentpolicy.Service
+}
+
+//	@Summary		Get content policy
+//	@Description	Returns content policy applied to outgoing messages
+//	@Security		ApiAuth
+//	@Tags			User, Content Policy
+//	@Produce		json
+//	@Success		200	{object}	contentpolicy.Policy		"Content policy"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/content-policy [get]
+//
+// Get content policy
+func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
+	policy, err := h.contentPolicySvc.Get(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't get content policy: %w", err)
+	}
+
+	return c.JSON(policy)
+}
+
+//	@Summary		Replace content policy
+//	@Description	Replaces content policy applied to outgoing messages. Messages violating the policy are rejected with `400 Bad Request`. Encrypted messages can't be checked and the `isEncrypted` flag is not verified, so they are rejected by default when the policy has any rules; setting `encrypted` to `allow` lets any sender bypass the policy by marking plain text as encrypted.
+//	@Security		ApiAuth
+//	@Tags			User, Content Policy
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		contentpolicy.Policy		true	"Content policy"
+//	@Success		200		{object}	contentpolicy.Policy		"Content policy"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/content-policy [put]
+//
+// Replace content policy
+func (h *ThirdPartyController) put(user models.User, c *fiber.Ctx) error {
+	req := contentpolicy.Policy{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	policy, err := h.contentPolicySvc.Replace(user.ID, req)
+	if contentpolicy.IsValidationError(err) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't replace content policy: %w", err)
+	}
+
+	return c.JSON(policy)
+}
+
+//	@Summary		Delete content policy
+//	@Description	Deletes content policy, so all messages are allowed
+//	@Security		ApiAuth
+//	@Tags			User, Content Policy
+//	@Success		204	{object}	nil							"Content policy deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/content-policy [delete]
+//
+// Delete content policy
+func (h *ThirdPartyController) delete(user models.User, c *fiber.Ctx) error {
+	if err := h.contentPolicySvc.Delete(user.ID); err != nil {
+		return fmt.Errorf("can't delete content policy: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.get))
+	router.Put("", userauth.WithUser(h.put))
+	router.Delete("", userauth.WithUser(h.delete))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("contentpolicy"),
+			Validator: params.Validator,
+		},
+		contentPolicySvc: params.ContentPolicySvc,
+	}
+}
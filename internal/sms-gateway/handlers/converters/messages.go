@@ -5,19 +5,23 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 )
 
-func MessageToDTO(m messages.MessageOut) smsgateway.MobileMessage {
-	return smsgateway.MobileMessage{
-		Message: smsgateway.Message{
-			ID:                 m.ID,
-			Message:            m.Message,
-			SimNumber:          m.SimNumber,
-			WithDeliveryReport: m.WithDeliveryReport,
-			IsEncrypted:        m.IsEncrypted,
-			PhoneNumbers:       m.PhoneNumbers,
-			TTL:                m.TTL,
-			ValidUntil:         m.ValidUntil,
-			Priority:           m.Priority,
+func MessageToDTO(m messages.MessageOut) messages.MobileMessage {
+	return messages.MobileMessage{
+		MobileMessage: smsgateway.MobileMessage{
+			Message: smsgateway.Message{
+				ID:                 m.ID,
+				Message:            m.Message,
+				SimNumber:          m.SimNumber,
+				WithDeliveryReport: m.WithDeliveryReport,
+				IsEncrypted:        m.IsEncrypted,
+				PhoneNumbers:       m.PhoneNumbers,
+				TTL:                m.TTL,
+				ValidUntil:         m.ValidUntil,
+				Priority:           m.Priority,
+			},
+			CreatedAt: m.CreatedAt,
 		},
-		CreatedAt: m.CreatedAt,
+		Metadata: m.Metadata,
+		Tags:     m.Tags,
 	}
 }
@@ -19,7 +19,7 @@ func TestMessageToDTO(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    messages.MessageOut
-		expected smsgateway.MobileMessage
+		expected messages.MobileMessage
 	}{
 		{
 			name: "Full message with all fields",
@@ -34,22 +34,28 @@ func TestMessageToDTO(t *testing.T) {
 					TTL:                anys.AsPointer(uint64(3600)),
 					ValidUntil:         anys.AsPointer(now.Add(24 * time.Hour)),
 					Priority:           100,
+					Metadata:           map[string]string{"orderId": "12345"},
+					Tags:               []string{"orders"},
 				},
 				CreatedAt: now,
 			},
-			expected: smsgateway.MobileMessage{
-				Message: smsgateway.Message{
-					ID:                 "msg-123",
-					Message:            "Test message content",
-					PhoneNumbers:       []string{"+1234567890", "+9876543210"},
-					IsEncrypted:        true,
-					SimNumber:          anys.AsPointer(uint8(2)),
-					WithDeliveryReport: anys.AsPointer(true),
-					TTL:                anys.AsPointer(uint64(3600)),
-					ValidUntil:         anys.AsPointer(now.Add(24 * time.Hour)),
-					Priority:           100,
+			expected: messages.MobileMessage{
+				MobileMessage: smsgateway.MobileMessage{
+					Message: smsgateway.Message{
+						ID:                 "msg-123",
+						Message:            "Test message content",
+						PhoneNumbers:       []string{"+1234567890", "+9876543210"},
+						IsEncrypted:        true,
+						SimNumber:          anys.AsPointer(uint8(2)),
+						WithDeliveryReport: anys.AsPointer(true),
+						TTL:                anys.AsPointer(uint64(3600)),
+						ValidUntil:         anys.AsPointer(now.Add(24 * time.Hour)),
+						Priority:           100,
+					},
+					CreatedAt: now,
 				},
-				CreatedAt: now,
+				Metadata: map[string]string{"orderId": "12345"},
+				Tags:     []string{"orders"},
 			},
 		},
 		{
@@ -62,13 +68,15 @@ func TestMessageToDTO(t *testing.T) {
 				},
 				CreatedAt: now,
 			},
-			expected: smsgateway.MobileMessage{
-				Message: smsgateway.Message{
-					ID:           "msg-456",
-					Message:      "Another test message",
-					PhoneNumbers: []string{"+1122334455"},
+			expected: messages.MobileMessage{
+				MobileMessage: smsgateway.MobileMessage{
+					Message: smsgateway.Message{
+						ID:           "msg-456",
+						Message:      "Another test message",
+						PhoneNumbers: []string{"+1122334455"},
+					},
+					CreatedAt: now,
 				},
-				CreatedAt: now,
 			},
 		},
 	}
@@ -4,37 +4,111 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/commands"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/webhooks"
 	"github.com/capcom6/go-helpers/slices"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type thirdPartyControllerParams struct {
 	fx.In
 
-	DevicesSvc *devices.Service
+	DevicesSvc   *devices.Service
+	TelemetrySvc *telemetry.Service
+	CommandsSvc  *commands.Service
+	QuotasSvc    *quotas.Service
+	AuthSvc      *auth.Service
+	WebhooksSvc  *webhooks.Service
+	PushSvc      *push.Service
+	OrgsSvc      *organizations.Service
 
-	Logger *zap.Logger
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+// device extends smsgateway.Device with tags and status.
+type device struct {
+	smsgateway.Device
+	// Tags, can be used to target messages to a group of devices
+	Tags []string `json:"tags,omitempty" example:"eu"`
+	// Status by the time the device was last seen
+	Status devices.Status `json:"status" enums:"online,stale,offline" example:"online"`
+	// Disabled devices are not used for message routing
+	Enabled bool `json:"enabled" example:"true"`
+	// Routing weight, devices with greater weight get proportionally more messages
+	Weight uint8 `json:"weight" example:"1"`
+	// SIM cards reported by the device
+	SIMs []devices.SIMOut `json:"sims,omitempty"`
+	// Latest telemetry reported by the device
+	Telemetry *telemetry.ReportOut `json:"telemetry,omitempty"`
+}
+
+type patchRequest struct {
+	// Name
+	Name *string `json:"name,omitempty" validate:"omitempty,max=128" example:"My Device"`
+	// Tags, replace the current ones
+	Tags *[]string `json:"tags,omitempty" validate:"omitempty,max=16,dive,required,max=32" example:"eu"`
+	// Disabled devices are not used for message routing but still can connect
+	Enabled *bool `json:"enabled,omitempty" example:"true"`
+	// Routing weight (1-100)
+	Weight *uint8 `json:"weight,omitempty" validate:"omitempty,min=1,max=100" example:"1"`
+}
+
+type tagsRequest struct {
+	// Tags, replace the current ones
+	Tags []string `json:"tags" validate:"max=16,dive,required,max=32" example:"eu"`
+}
+
+type commandRequest struct {
+	// Command type
+	Type commands.Type `json:"type" validate:"required,oneof=ping sync_settings sync_webhooks flush_queue" example:"ping"`
+}
+
+type transferRequest struct {
+	// One-time code of the target user, see `GET /mobile/v1/user/code`
+	Code string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+	// What to do with webhooks of the device: move them to the target user or remove
+	Webhooks string `json:"webhooks,omitempty" validate:"omitempty,oneof=move remove" default:"move" example:"move"`
+}
+
+type telemetryQueryParams struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=500"`
 }
 
 type ThirdPartyController struct {
 	base.Handler
 
-	devicesSvc *devices.Service
+	devicesSvc   *devices.Service
+	telemetrySvc *telemetry.Service
+	commandsSvc  *commands.Service
+	quotasSvc    *quotas.Service
+	authSvc      *auth.Service
+	webhooksSvc  *webhooks.Service
+	pushSvc      *push.Service
+	orgsSvc      *organizations.Service
 }
 
 //	@Summary		List devices
-//	@Description	Returns list of registered devices
+//	@Description	Returns list of registered devices with SIM cards and the latest telemetry reported by them. The status is `online`, `stale` or `offline` depending on the time the device was last seen
 //	@Security		ApiAuth
 //	@Tags			User, Devices
 //	@Produce		json
-//	@Success		200	{object}	[]smsgateway.Device			"Device list"
+//	@Success		200	{object}	[]device					"Device list"
 //	@Failure		400	{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
@@ -47,7 +121,25 @@ func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
 		return fmt.Errorf("can't select devices: %w", err)
 	}
 
-	response := slices.Map(devices, converters.DeviceToDTO)
+	ids := slices.Map(devices, func(d models.Device) string { return d.ID })
+
+	sims, err := h.devicesSvc.SelectSIMs(ids...)
+	if err != nil {
+		return fmt.Errorf("can't select SIMs: %w", err)
+	}
+
+	reports, err := h.telemetrySvc.Latest(ids...)
+	if err != nil {
+		return fmt.Errorf("can't select telemetry: %w", err)
+	}
+
+	response := slices.Map(devices, h.deviceToDTO)
+	for i := range response {
+		response[i].SIMs = sims[response[i].ID]
+		if report, ok := reports[response[i].ID]; ok {
+			response[i].Telemetry = &report
+		}
+	}
 
 	return c.JSON(response)
 }
@@ -80,16 +172,408 @@ func (h *ThirdPartyController) remove(user models.User, c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+//	@Summary		Update device
+//	@Description	Updates device name, tags, enabled status and routing weight. Omitted fields are not changed
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		patchRequest				true	"Device fields"
+//	@Success		200		{object}	device						"Device"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id} [patch]
+//
+// Update device
+func (h *ThirdPartyController) patch(user models.User, c *fiber.Ctx) error {
+	req := patchRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	device, err := h.devicesSvc.Update(user.ID, c.Params("id"), devices.DeviceUpdate{
+		Name:    req.Name,
+		Tags:    req.Tags,
+		Enabled: req.Enabled,
+		Weight:  req.Weight,
+	})
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't update device: %w", err)
+	}
+
+	return c.JSON(h.deviceToDTO(device))
+}
+
+//	@Summary		Rotate device token
+//	@Description	Issues a new auth token for the device and revokes the current one after the optional grace period. The device has to be signed in again with the new token
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		tokenRequest				true	"Token rotation request"
+//	@Success		200		{object}	tokenResponse				"New token"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/token [post]
+//
+// Rotate device token
+func (h *ThirdPartyController) postToken(user models.User, c *fiber.Ctx) error {
+	req := tokenRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	res, err := rotateToken(h.devicesSvc, user.ID, c.Params("id"), req.GracePeriod)
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't rotate token: %w", err)
+	}
+
+	return c.JSON(res)
+}
+
+//	@Summary		Get device telemetry
+//	@Description	Returns telemetry history of the device for the last 7 days, newest first
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			limit	query		int							false	"Limit"	default(50)	minimum(1)	maximum(500)
+//	@Success		200		{object}	[]telemetry.ReportOut		"Telemetry history"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/telemetry [get]
+//
+// Get device telemetry
+func (h *ThirdPartyController) getTelemetry(user models.User, c *fiber.Ctx) error {
+	params := telemetryQueryParams{}
+	if err := h.QueryParserValidator(c, &params); err != nil {
+		return err
+	}
+	if params.Limit == 0 {
+		params.Limit = 50
+	}
+
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	history, err := h.telemetrySvc.History(device.ID, params.Limit)
+	if err != nil {
+		return fmt.Errorf("can't get telemetry: %w", err)
+	}
+
+	return c.JSON(history)
+}
+
+//	@Summary		Transfer device
+//	@Description	Moves the device to another organization identified by a one-time code generated by its admin or owner. The device keeps its credentials, message history and reported data. Webhooks of the device are moved or removed, the device gets settings and webhooks of the target organization
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string			true	"Device ID"
+//	@Param			request	body	transferRequest	true	"Transfer request"
+//	@Success		204		"Successfully transferred"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	smsgateway.ErrorResponse	"Invalid or expired code"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		429		{object}	smsgateway.ErrorResponse	"Too many failed code attempts"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			429		{string}	Retry-After					"Seconds until the lockout ends"
+//	@Router			/3rdparty/v1/devices/{id}/transfer [post]
+//
+// Transfer device
+func (h *ThirdPartyController) postTransfer(user models.User, c *fiber.Ctx) error {
+	req := transferRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	codeUser, err := h.authSvc.AuthorizeUserByCode(req.Code, c.IP())
+	if userauth.IsLockedOut(err) {
+		return userauth.TooManyAttempts(c, err)
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusForbidden, "Invalid or expired code")
+	}
+
+	// the device is moved to the organization the code owner manages
+	membership, err := h.orgsSvc.ResolveManaged(codeUser.ID)
+	if errors.Is(err, organizations.ErrNotFound) || errors.Is(err, organizations.ErrForbidden) {
+		return fiber.NewError(fiber.StatusForbidden, "The code owner can't manage devices of the organization")
+	}
+	if err != nil {
+		return fmt.Errorf("can't resolve organization: %w", err)
+	}
+	target := models.User{ID: membership.OrganizationID}
+
+	if target.ID == user.ID {
+		return fiber.NewError(fiber.StatusBadRequest, "The device already belongs to the user")
+	}
+
+	moveWebhooks := func(tx *gorm.DB) error {
+		return h.webhooksSvc.TransferDevice(tx, device.ID, user.ID, target.ID, req.Webhooks == "remove")
+	}
+	if _, err := h.devicesSvc.Transfer(user.ID, device.ID, target.ID, moveWebhooks); err != nil {
+		return fmt.Errorf("can't transfer device: %w", err)
+	}
+
+	h.Logger.Info("Device transferred",
+		zap.String("device_id", device.ID),
+		zap.String("user_id", user.ID),
+		zap.String("target_user_id", target.ID),
+	)
+
+	for _, event := range []*push.Event{push.NewSettingsUpdatedEvent(), push.NewWebhooksUpdatedEvent()} {
+		if err := h.pushSvc.Notify(target.ID, &device.ID, event); err != nil {
+			h.Logger.Error("Can't notify device", zap.String("device_id", device.ID), zap.Error(err))
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Get sending usage
+//	@Description	Returns the number of messages sent by the device and each of its SIM cards in the current minute, hour and day (UTC) along with the sending limit from the settings. Devices and SIM cards that reached the limit are skipped when selecting a device for a message
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id	path		string						true	"Device ID"
+//	@Success		200	{object}	quotas.UsageOut				"Usage"
+//	@Failure		400	{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/usage [get]
+//
+// Get sending usage
+func (h *ThirdPartyController) getUsage(user models.User, c *fiber.Ctx) error {
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	usage, err := h.quotasSvc.Usage(device)
+	if err != nil {
+		return fmt.Errorf("can't get usage: %w", err)
+	}
+
+	return c.JSON(usage)
+}
+
+//	@Summary		Send command
+//	@Description	Sends a remote command to the device via push notification. The device acknowledges the command when it is executed, pending commands expire in an hour. Commands are not sent while pushes to the device are suspended after repeated delivery failures
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		commandRequest				true	"Command"
+//	@Success		202		{object}	commands.CommandOut			"Command"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Device has no push token or pushes to it are suspended"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/commands [post]
+//
+// Send command
+func (h *ThirdPartyController) postCommand(user models.User, c *fiber.Ctx) error {
+	req := commandRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	command, err := h.commandsSvc.Send(device, req.Type)
+	if errors.Is(err, commands.ErrNoPushToken) || errors.Is(err, commands.ErrPushBlocked) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if errors.Is(err, commands.ErrInvalidType) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't send command: %w", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(command)
+}
+
+//	@Summary		Get commands
+//	@Description	Returns the latest commands sent to the device, newest first
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Success		200		{object}	[]commands.CommandOut		"Commands"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/commands [get]
+//
+// Get commands
+func (h *ThirdPartyController) getCommands(user models.User, c *fiber.Ctx) error {
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	items, err := h.commandsSvc.Select(device.ID, 50)
+	if err != nil {
+		return fmt.Errorf("can't get commands: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Get command
+//	@Description	Returns the command state
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Produce		json
+//	@Param			id			path		string						true	"Device ID"
+//	@Param			commandId	path		string						true	"Command ID"
+//	@Success		200			{object}	commands.CommandOut			"Command"
+//	@Failure		400			{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	smsgateway.ErrorResponse	"Device or command not found"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/commands/{commandId} [get]
+//
+// Get command
+func (h *ThirdPartyController) getCommand(user models.User, c *fiber.Ctx) error {
+	device, err := h.devicesSvc.Get(user.ID, devices.WithID(c.Params("id")))
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	command, err := h.commandsSvc.Get(device.ID, c.Params("commandId"))
+	if errors.Is(err, commands.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't get command: %w", err)
+	}
+
+	return c.JSON(command)
+}
+
+//	@Summary		Replace device tags
+//	@Description	Replaces tags of the device. Messages can be sent via devices with a specific tag. Deprecated: use `PATCH /3rdparty/v1/devices/{id}` with `tags` instead
+//	@Deprecated
+//	@Security		ApiAuth
+//	@Tags			User, Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Device ID"
+//	@Param			request	body		tagsRequest					true	"Tags"
+//	@Success		200		{object}	device						"Device"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Device not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/devices/{id}/tags [put]
+//
+// Replace device tags
+func (h *ThirdPartyController) putTags(user models.User, c *fiber.Ctx) error {
+	req := tagsRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	device, err := h.devicesSvc.Update(user.ID, c.Params("id"), devices.DeviceUpdate{Tags: &req.Tags})
+	if errors.Is(err, devices.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't update device tags: %w", err)
+	}
+
+	return c.JSON(h.deviceToDTO(device))
+}
+
+func (h *ThirdPartyController) deviceToDTO(input models.Device) device {
+	return device{
+		Device: converters.DeviceToDTO(input),
+		Tags:   input.Tags,
+		Status: h.devicesSvc.Status(input),
+
+		Enabled: input.Enabled,
+		Weight:  input.Weight,
+	}
+}
+
 func (h *ThirdPartyController) Register(router fiber.Router) {
 	router.Get("", userauth.WithUser(h.get))
+	router.Patch(":id", userauth.WithUser(h.patch))
 	router.Delete(":id", userauth.WithUser(h.remove))
+	router.Put(":id/tags", userauth.WithUser(h.putTags)) // TODO: remove after 2027-03-31, replaced by PATCH :id
+	router.Post(":id/token", userauth.WithUser(h.postToken))
+	router.Get(":id/telemetry", userauth.WithUser(h.getTelemetry))
+	router.Get(":id/usage", userauth.WithUser(h.getUsage))
+	router.Post(":id/transfer", userauth.WithUser(h.postTransfer))
+	router.Post(":id/commands", userauth.WithUser(h.postCommand))
+	router.Get(":id/commands", userauth.WithUser(h.getCommands))
+	router.Get(":id/commands/:commandId", userauth.WithUser(h.getCommand))
 }
 
 func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
 	return &ThirdPartyController{
 		Handler: base.Handler{
-			Logger: params.Logger.Named("devices"),
+			Logger:    params.Logger.Named("devices"),
+			Validator: params.Validator,
 		},
-		devicesSvc: params.DevicesSvc,
+		devicesSvc:   params.DevicesSvc,
+		telemetrySvc: params.TelemetrySvc,
+		commandsSvc:  params.CommandsSvc,
+		quotasSvc:    params.QuotasSvc,
+		authSvc:      params.AuthSvc,
+		webhooksSvc:  params.WebhooksSvc,
+		pushSvc:      params.PushSvc,
+		orgsSvc:      params.OrgsSvc,
 	}
 }
@@ -0,0 +1,107 @@
+package devices
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/capcom6/go-helpers/slices"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const defaultAdminListLimit = 50
+
+type adminControllerParams struct {
+	fx.In
+
+	DevicesSvc *devices.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type adminListQuery struct {
+	UserID string `query:"userId" validate:"max=32"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=500"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+type adminDevice struct {
+	device
+	// Owner login
+	UserID string `json:"userId" example:"ABCDEF"`
+}
+
+type AdminController struct {
+	base.Handler
+
+	devicesSvc *devices.Service
+}
+
+//	@Summary		List devices of all users
+//	@Description	Returns devices of all users sorted by ID, optionally filtered by the owner
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			userId	query		string						false	"Owner login"
+//	@Param			limit	query		int							false	"Max number of devices"		default(50)	maximum(500)
+//	@Param			offset	query		int							false	"Number of devices to skip"	default(0)
+//	@Success		200		{object}	[]adminDevice				"Devices"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/devices [get]
+//
+// List devices of all users
+func (h *AdminController) list(c *fiber.Ctx) error {
+	query := adminListQuery{}
+	if err := h.QueryParserValidator(c, &query); err != nil {
+		return err
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultAdminListLimit
+	}
+
+	filters := []devices.SelectFilter{}
+	if query.UserID != "" {
+		filters = append(filters, devices.WithUserID(query.UserID))
+	}
+
+	items, err := h.devicesSvc.SelectAll(query.Limit, query.Offset, filters...)
+	if err != nil {
+		return fmt.Errorf("can't select devices: %w", err)
+	}
+
+	return c.JSON(slices.Map(items, func(input models.Device) adminDevice {
+		return adminDevice{
+			device: device{
+				Device: converters.DeviceToDTO(input),
+				Tags:   input.Tags,
+				Status: h.devicesSvc.Status(input),
+
+				Enabled: input.Enabled,
+				Weight:  input.Weight,
+			},
+			UserID: input.UserID,
+		}
+	}))
+}
+
+func (h *AdminController) Register(router fiber.Router) {
+	router.Get("", h.list)
+}
+
+func NewAdminController(params adminControllerParams) *AdminController {
+	return &AdminController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("devices"),
+			Validator: params.Validator,
+		},
+		devicesSvc: params.DevicesSvc,
+	}
+}
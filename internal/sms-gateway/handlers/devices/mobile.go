@@ -0,0 +1,214 @@
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/commands"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/telemetry"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type mobileControllerParams struct {
+	fx.In
+
+	DevicesSvc   *devices.Service
+	TelemetrySvc *telemetry.Service
+	CommandsSvc  *commands.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type tokenRequest struct {
+	// Grace period in seconds the previous token stays valid for
+	GracePeriod uint32 `json:"gracePeriod,omitempty" validate:"max=604800" example:"300"`
+}
+
+type tokenResponse struct {
+	// New auth token
+	Token string `json:"token" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Time until the previous token is valid
+	PrevTokenValidUntil *time.Time `json:"prevTokenValidUntil,omitempty" example:"2020-01-01T00:00:00Z"`
+}
+
+func rotateToken(svc *devices.Service, userID, deviceID string, gracePeriod uint32) (tokenResponse, error) {
+	device, err := svc.RotateToken(userID, deviceID, time.Duration(gracePeriod)*time.Second)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	return tokenResponse{
+		Token:               device.AuthToken,
+		PrevTokenValidUntil: device.PrevAuthTokenValidUntil,
+	}, nil
+}
+
+type commandAckRequest struct {
+	// Error message if the command failed
+	Error string `json:"error,omitempty" validate:"max=256" example:"Settings are invalid"`
+}
+
+type simsRequest struct {
+	// SIM cards inserted into the device
+	SIMs []devices.SIMIn `json:"sims" validate:"max=3,dive"`
+}
+
+// MobileController receives the SIM inventory, telemetry and command
+// acknowledgements reported by devices and rotates their tokens.
+type MobileController struct {
+	base.Handler
+
+	devicesSvc   *devices.Service
+	telemetrySvc *telemetry.Service
+	commandsSvc  *commands.Service
+}
+
+//	@Summary		Report SIM cards
+//	@Description	Replaces SIM cards inventory of the device. Messages can be sent from a specific phone number of a reported SIM card
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	simsRequest	true	"SIM cards"
+//	@Success		204		"Successfully updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/device/sims [put]
+//
+// Report SIM cards
+func (h *MobileController) putSIMs(device models.Device, c *fiber.Ctx) error {
+	req := simsRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.devicesSvc.ReplaceSIMs(device.ID, req.SIMs); err != nil {
+		if errors.Is(err, devices.ErrInvalidSIM) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fmt.Errorf("can't replace SIMs: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Report telemetry
+//	@Description	Stores the current battery, signal and network state of the device. Omitted fields are unknown
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	telemetry.ReportIn	true	"Telemetry"
+//	@Success		204		"Successfully reported"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/device/telemetry [post]
+//
+// Report telemetry
+func (h *MobileController) postTelemetry(device models.Device, c *fiber.Ctx) error {
+	req := telemetry.ReportIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.telemetrySvc.Report(device, req); err != nil {
+		return fmt.Errorf("can't report telemetry: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Acknowledge command
+//	@Description	Reports the result of a remote command received via push. A command can be acknowledged only once within an hour after it was sent
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string				true	"Command ID"
+//	@Param			request	body	commandAckRequest	true	"Result"
+//	@Success		204		"Successfully acknowledged"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Command not found"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"Command is not pending"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/device/commands/{id}/ack [post]
+//
+// Acknowledge command
+func (h *MobileController) postCommandAck(device models.Device, c *fiber.Ctx) error {
+	req := commandAckRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	err := h.commandsSvc.Acknowledge(device.ID, c.Params("id"), req.Error)
+	if errors.Is(err, commands.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, commands.ErrNotPending) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't acknowledge command: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Rotate auth token
+//	@Description	Issues a new auth token for the device. The current token stays valid during the optional grace period
+//	@Security		MobileToken
+//	@Tags			Device
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		tokenRequest				true	"Token rotation request"
+//	@Success		200		{object}	tokenResponse				"New token"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/mobile/v1/device/token [post]
+//
+// Rotate auth token
+func (h *MobileController) postToken(device models.Device, c *fiber.Ctx) error {
+	req := tokenRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	res, err := rotateToken(h.devicesSvc, device.UserID, device.ID, req.GracePeriod)
+	if err != nil {
+		return fmt.Errorf("can't rotate token: %w", err)
+	}
+
+	return c.JSON(res)
+}
+
+func (h *MobileController) Register(router fiber.Router) {
+	router.Put("/sims", deviceauth.WithDevice(h.putSIMs))
+	router.Post("/token", deviceauth.WithDevice(h.postToken))
+	router.Post("/telemetry", deviceauth.WithDevice(h.postTelemetry))
+	router.Post("/commands/:id/ack", deviceauth.WithDevice(h.postCommandAck))
+}
+
+func NewMobileController(params mobileControllerParams) *MobileController {
+	return &MobileController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("devices"),
+			Validator: params.Validator,
+		},
+		devicesSvc:   params.DevicesSvc,
+		telemetrySvc: params.TelemetrySvc,
+		commandsSvc:  params.CommandsSvc,
+	}
+}
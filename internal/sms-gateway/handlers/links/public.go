@@ -0,0 +1,58 @@
+package links
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type publicControllerParams struct {
+	fx.In
+
+	LinksSvc *links.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+// PublicController serves short links sent to recipients, so it is not
+// protected by authentication.
+type PublicController struct {
+	base.Handler
+
+	linksSvc *links.Service
+}
+
+// Redirect to the short link target and count the click
+func (h *PublicController) get(c *fiber.Ctx) error {
+	url, err := h.linksSvc.Click(c.Params("code"))
+	if errors.Is(err, links.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Link not found")
+	}
+	if err != nil {
+		return fmt.Errorf("can't get link: %w", err)
+	}
+
+	// not permanent, so every click reaches the gateway
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+func (h *PublicController) Register(router fiber.Router) {
+	router.Get("/:code<len(10)>", h.get)
+}
+
+func NewPublicController(params publicControllerParams) *PublicController {
+	return &PublicController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("links"),
+			Validator: params.Validator,
+		},
+		linksSvc: params.LinksSvc,
+	}
+}
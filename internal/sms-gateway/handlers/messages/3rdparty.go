@@ -3,14 +3,19 @@ package messages
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
-	"github.com/capcom6/go-helpers/slices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
+	"github.com/android-sms-gateway/server/pkg/templates"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
@@ -19,6 +24,10 @@ import (
 
 const (
 	route3rdPartyGetMessage = "3rdparty.get.message"
+
+	maxPhoneNumbers = 100
+
+	defaultListLimit = 50
 )
 
 type thirdPartyControllerParams struct {
@@ -26,26 +35,130 @@ type thirdPartyControllerParams struct {
 
 	MessagesSvc *messages.Service
 	DevicesSvc  *devices.Service
+	ContactsSvc *contacts.Service
+	QuotasSvc   *quotas.Service
 
 	Validator *validator.Validate
 	Logger    *zap.Logger
 }
 
+type postRequest struct {
+	// ID (if not set - will be generated)
+	ID string `json:"id,omitempty" validate:"omitempty,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Content
+	Message string `json:"message" validate:"required,max=65535" example:"Hello {{name}}!"`
+	// Recipients (phone numbers)
+	PhoneNumbers []string `json:"phoneNumbers" validate:"required_without=GroupIDs,omitempty,min=1,max=100,dive,required,min=1,max=128" example:"79990001234"`
+	// Recipients (contact groups)
+	GroupIDs []string `json:"groupIds,omitempty" validate:"max=100,dive,required,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Is encrypted
+	IsEncrypted bool `json:"isEncrypted,omitempty" example:"true"`
+
+	// Send via a random device with the tag, if not set - any device will be used
+	DeviceTag string `json:"deviceTag,omitempty" validate:"omitempty,max=32" example:"eu"`
+	// Send from the phone number of a SIM card reported by devices (conflicts with `deviceTag` and `simNumber`)
+	SenderNumber string `json:"senderNumber,omitempty" validate:"omitempty,max=32" example:"+79990001234"`
+	// SIM card number (1-3), if not set - default SIM will be used
+	SimNumber *uint8 `json:"simNumber,omitempty" validate:"omitempty,max=3" example:"1"`
+	// With delivery report
+	WithDeliveryReport *bool `json:"withDeliveryReport,omitempty" example:"true"`
+	// Priority, messages with values greater than `99` will bypass limits and delays
+	Priority smsgateway.MessagePriority `json:"priority,omitempty" validate:"omitempty,min=-128,max=127" example:"0" default:"0"`
+
+	// Time to live in seconds (conflicts with `validUntil`)
+	TTL *uint64 `json:"ttl,omitempty" validate:"omitempty,min=5" example:"86400"`
+	// Valid until (conflicts with `ttl`)
+	ValidUntil *time.Time `json:"validUntil,omitempty" example:"2020-01-01T00:00:00Z"`
+
+	// Custom metadata, returned with the message state
+	Metadata map[string]string `json:"metadata,omitempty" validate:"max=32,dive,keys,required,max=64,endkeys,max=1024" example:"orderId:12345"`
+	// Tags, can be used to filter messages
+	Tags []string `json:"tags,omitempty" validate:"max=32,dive,required,max=64" example:"orders"`
+
+	// Sending window in recipients' local time, overrides the default window from settings
+	SendingWindow *messages.SendingWindow `json:"sendingWindow,omitempty"`
+	// Transactional messages are sent regardless of sending windows (conflicts with `sendingWindow`)
+	IsTransactional bool `json:"isTransactional,omitempty" example:"false"`
+
+	// Replace URLs with short links tracking clicks per recipient (conflicts with `isEncrypted`)
+	ShortenLinks bool `json:"shortenLinks,omitempty" example:"false"`
+}
+
+func (r postRequest) Validate() error {
+	if r.IsTransactional && r.SendingWindow != nil {
+		return fmt.Errorf("%w: isTransactional and sendingWindow", smsgateway.ErrConflictFields)
+	}
+	if r.ShortenLinks && r.IsEncrypted {
+		return fmt.Errorf("%w: shortenLinks and isEncrypted", smsgateway.ErrConflictFields)
+	}
+	if r.SenderNumber != "" && (r.DeviceTag != "" || r.SimNumber != nil) {
+		return fmt.Errorf("%w: senderNumber and deviceTag or simNumber", smsgateway.ErrConflictFields)
+	}
+
+	return smsgateway.Message{TTL: r.TTL, ValidUntil: r.ValidUntil}.Validate()
+}
+
+type listQuery struct {
+	DeviceID string   `query:"deviceId" validate:"omitempty,max=21"`
+	State    string   `query:"state" validate:"omitempty,oneof=Pending Processed Sent Delivered Failed"`
+	Tags     []string `query:"tag" validate:"max=10,dive,required,max=64"`
+	Metadata []string `query:"metadata" validate:"max=10,dive,required,contains=:"`
+	From     string   `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To       string   `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit    int      `query:"limit" validate:"omitempty,min=1,max=500"`
+	Offset   int      `query:"offset" validate:"omitempty,min=0"`
+}
+
+func (q listQuery) toFilter() messages.MessagesSelectFilter {
+	filter := messages.MessagesSelectFilter{
+		DeviceID: q.DeviceID,
+		State:    models.ProcessingState(q.State),
+		Tags:     q.Tags,
+	}
+
+	if len(q.Metadata) > 0 {
+		filter.Metadata = make(map[string]string, len(q.Metadata))
+		for _, v := range q.Metadata {
+			key, value, _ := strings.Cut(v, ":")
+			filter.Metadata[key] = value
+		}
+	}
+
+	// formats are checked by the validator
+	if q.From != "" {
+		from, _ := time.Parse(time.RFC3339, q.From)
+		filter.Since = &from
+	}
+	if q.To != "" {
+		to, _ := time.Parse(time.RFC3339, q.To)
+		filter.Until = &to
+	}
+
+	return filter
+}
+
+type retryRequest struct {
+	// Device ID to send the new message via (defaults to the original device)
+	DeviceID string `json:"deviceId,omitempty" validate:"omitempty,max=21" example:"PyDmBQZZXYmyxMwED8Fzy"`
+}
+
 type ThirdPartyController struct {
 	base.Handler
 
 	messagesSvc *messages.Service
 	devicesSvc  *devices.Service
+	contactsSvc *contacts.Service
+	quotasSvc   *quotas.Service
 }
 
 //	@Summary		Enqueue message
-//	@Description	Enqueues message for sending. If multiple devices are registered, it will be sent via a random one
+//	@Description	Enqueues message for sending. If multiple devices are registered, it will be sent via a random enabled one according to device weights, optionally limited to devices with a tag. When a sender number is provided, the message is sent via the device and SIM card reported with this number. Recipients can be provided as phone numbers and as contact groups. If the message contains `{{variable}}` placeholders and contact groups are used, it is rendered for each recipient with contact fields, `name` and `phone` variables. Messages are held until the sending window opens in the recipients' local time, estimated by their phone numbers, unless marked as transactional. URLs can be replaced with short links served by the gateway, click stats are returned in the message state. When the rendered texts differ, links are shortened or there are more than 100 recipients, several messages are enqueued and an array of states is returned. If enqueueing of one of them fails, the messages enqueued before are kept and their states are returned in the `data` field of the error
 //	@Security		ApiAuth
 //	@Tags			User, Messages
 //	@Accept			json
 //	@Produce		json
 //	@Param			skipPhoneValidation	query		bool						false	"Skip phone validation"
-//	@Param			request				body		smsgateway.Message			true	"Send message request"
+//	@Param			request				body		postRequest					true	"Send message request"
 //	@Success		202					{object}	smsgateway.MessageState		"Message enqueued"
 //	@Failure		400					{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
@@ -56,28 +169,13 @@ type ThirdPartyController struct {
 //
 // Enqueue message
 func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
-	req := smsgateway.Message{}
+	req := postRequest{}
 	if err := h.BodyParserValidator(c, &req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
 	skipPhoneValidation := c.QueryBool("skipPhoneValidation", false)
 
-	devices, err := h.devicesSvc.Select(user.ID)
-	if err != nil {
-		h.Logger.Error("Failed to select devices", zap.Error(err), zap.String("user_id", user.ID))
-		return fiber.NewError(fiber.StatusInternalServerError, "Can't select devices. Please contact support")
-	}
-
-	if len(devices) < 1 {
-		return fiber.NewError(fiber.StatusBadRequest, "No devices registered")
-	}
-
-	device, err := slices.Random(devices)
-	if err != nil {
-		return fmt.Errorf("can't get random device: %w", err)
-	}
-
 	msg := messages.MessageIn{
 		ID:           req.ID,
 		Message:      req.Message,
@@ -89,20 +187,47 @@ func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
 		TTL:                req.TTL,
 		ValidUntil:         req.ValidUntil,
 		Priority:           req.Priority,
+
+		Metadata: req.Metadata,
+		Tags:     req.Tags,
+
+		SendingWindow:   req.SendingWindow,
+		IsTransactional: req.IsTransactional,
+		ShortenLinks:    req.ShortenLinks,
 	}
-	state, err := h.messagesSvc.Enqueue(device, msg, messages.EnqueueOptions{SkipPhoneValidation: skipPhoneValidation})
+
+	device, simNumber, err := h.selectDevice(user, req)
 	if err != nil {
-		var errValidation messages.ErrValidation
-		if isBadRequest := errors.As(err, &errValidation); isBadRequest {
-			return fiber.NewError(fiber.StatusBadRequest, errValidation.Error())
-		}
-		if isConflict := errors.Is(err, messages.ErrMessageAlreadyExists); isConflict {
-			return fiber.NewError(fiber.StatusConflict, err.Error())
+		return err
+	}
+	if simNumber != nil {
+		msg.SimNumber = simNumber
+	}
+
+	msgs, err := h.expandGroups(user, msg, req.GroupIDs)
+	if err != nil {
+		return err
+	}
+
+	states := make([]smsgateway.MessageState, 0, len(msgs))
+	for _, msg := range msgs {
+		state, err := h.messagesSvc.Enqueue(device, msg, messages.EnqueueOptions{SkipPhoneValidation: skipPhoneValidation})
+		if err != nil {
+			if len(states) > 0 {
+				return h.partiallyEnqueued(c, err, states, len(msgs))
+			}
+			return h.enqueueError(err)
 		}
 
-		return fmt.Errorf("can't enqueue message: %w", err)
+		states = append(states, state)
+	}
+
+	if len(states) > 1 {
+		return c.Status(fiber.StatusAccepted).JSON(states)
 	}
 
+	state := states[0]
+
 	location, err := c.GetRouteURL(route3rdPartyGetMessage, fiber.Map{
 		"id": state.ID,
 	})
@@ -115,13 +240,224 @@ func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
 	return c.Status(fiber.StatusAccepted).JSON(state)
 }
 
+func (h *ThirdPartyController) enqueueError(err error) error {
+	var errValidation messages.ErrValidation
+	if isBadRequest := errors.As(err, &errValidation); isBadRequest {
+		return fiber.NewError(fiber.StatusBadRequest, errValidation.Error())
+	}
+	if isConflict := errors.Is(err, messages.ErrMessageAlreadyExists); isConflict {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+
+	return fmt.Errorf("can't enqueue message: %w", err)
+}
+
+// partiallyEnqueued responds with the error of a split message along with
+// states of the parts enqueued before the error, they are not rolled back.
+func (h *ThirdPartyController) partiallyEnqueued(c *fiber.Ctx, err error, states []smsgateway.MessageState, total int) error {
+	code := fiber.StatusInternalServerError
+	message := "Can't enqueue message"
+
+	var errFiber *fiber.Error
+	if errors.As(h.enqueueError(err), &errFiber) {
+		code = errFiber.Code
+		message = errFiber.Message
+	} else {
+		h.Logger.Error("Can't enqueue message", zap.Error(err))
+	}
+
+	return c.Status(code).JSON(smsgateway.ErrorResponse{
+		Message: fmt.Sprintf("%s: %d of %d messages are enqueued", message, len(states), total),
+		Data:    states,
+	})
+}
+
+// selectDevice returns the device to send the message via. The SIM card number
+// is returned if the message is sent from a specific sender number. Devices
+// and SIM cards that reached the sending limit are skipped.
+func (h *ThirdPartyController) selectDevice(user models.User, req postRequest) (models.Device, *uint8, error) {
+	if req.SenderNumber != "" {
+		device, slot, err := h.devicesSvc.GetBySenderNumber(user.ID, req.SenderNumber)
+		if errors.Is(err, devices.ErrNotFound) {
+			return device, nil, fiber.NewError(fiber.StatusBadRequest, "No SIM cards with number "+req.SenderNumber)
+		}
+		if err != nil {
+			return device, nil, fmt.Errorf("can't get device by sender number: %w", err)
+		}
+
+		available, err := h.quotasSvc.SIMAvailable(device, slot)
+		if err != nil {
+			return device, nil, fmt.Errorf("can't check sending limit: %w", err)
+		}
+		if !available {
+			return device, nil, fiber.NewError(fiber.StatusTooManyRequests, "Sending limit reached for number "+req.SenderNumber)
+		}
+
+		return device, &slot, nil
+	}
+
+	filter := []devices.SelectFilter{devices.WithEnabled()}
+	if req.DeviceTag != "" {
+		filter = append(filter, devices.WithTag(req.DeviceTag))
+	}
+
+	items, err := h.devicesSvc.Select(user.ID, filter...)
+	if err != nil {
+		h.Logger.Error("Failed to select devices", zap.Error(err), zap.String("user_id", user.ID))
+		return models.Device{}, nil, fiber.NewError(fiber.StatusInternalServerError, "Can't select devices. Please contact support")
+	}
+
+	if len(items) < 1 {
+		if req.DeviceTag != "" {
+			return models.Device{}, nil, fiber.NewError(fiber.StatusBadRequest, "No devices with tag "+req.DeviceTag)
+		}
+		return models.Device{}, nil, fiber.NewError(fiber.StatusBadRequest, "No enabled devices registered")
+	}
+
+	if items, err = h.quotasSvc.Available(user.ID, items); err != nil {
+		return models.Device{}, nil, fmt.Errorf("can't check sending limit: %w", err)
+	}
+	if len(items) < 1 {
+		return models.Device{}, nil, fiber.NewError(fiber.StatusTooManyRequests, "Sending limit reached on all devices")
+	}
+
+	device, err := devices.Route(items)
+	if err != nil {
+		return device, nil, fmt.Errorf("can't route message: %w", err)
+	}
+
+	return device, nil, nil
+}
+
+// expandGroups adds phone numbers of the groups' contacts to the message. If
+// the message is a template, it is rendered for each recipient and recipients
+// with the same text are sent in one message. Messages with short links are
+// sent to each recipient separately to track clicks per recipient. Messages
+// with more than maxPhoneNumbers recipients are split.
+func (h *ThirdPartyController) expandGroups(user models.User, msg messages.MessageIn, groupIDs []string) ([]messages.MessageIn, error) {
+	personal := msg.ShortenLinks && len(msg.PhoneNumbers)+len(groupIDs) > 1 && links.HasURLs(msg.Message)
+	if len(groupIDs) == 0 && !personal {
+		return []messages.MessageIn{msg}, nil
+	}
+
+	items := []contacts.ContactOut{}
+	if len(groupIDs) > 0 {
+		var err error
+		if items, err = h.contactsSvc.Expand(user.ID, groupIDs); err != nil {
+			if contacts.IsValidationError(err) {
+				return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+
+			return nil, fmt.Errorf("can't expand groups: %w", err)
+		}
+	}
+
+	render := !msg.IsEncrypted && templates.HasPlaceholders(msg.Message)
+
+	keys := []string{}
+	texts := map[string]string{}
+	recipients := map[string][]string{}
+	seen := map[string]struct{}{}
+	add := func(phone string, vars map[string]string) {
+		if _, ok := seen[phone]; ok {
+			return
+		}
+		seen[phone] = struct{}{}
+
+		text := msg.Message
+		if render {
+			text = templates.Render(text, vars)
+		}
+
+		key := text
+		if personal {
+			key = phone
+		}
+		if _, ok := recipients[key]; !ok {
+			keys = append(keys, key)
+			texts[key] = text
+		}
+		recipients[key] = append(recipients[key], phone)
+	}
+
+	for _, phone := range msg.PhoneNumbers {
+		add(phone, map[string]string{"phone": phone})
+	}
+	for _, contact := range items {
+		add(contact.PhoneNumber, contact.Variables())
+	}
+
+	if len(seen) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "No recipients in the groups")
+	}
+
+	msgs := []messages.MessageIn{}
+	for _, key := range keys {
+		phones := recipients[key]
+		for len(phones) > 0 {
+			n := min(len(phones), maxPhoneNumbers)
+
+			m := msg
+			m.Message = texts[key]
+			m.PhoneNumbers = phones[:n]
+			msgs = append(msgs, m)
+
+			phones = phones[n:]
+		}
+	}
+
+	if len(msgs) > 1 && msg.ID != "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Message ID can't be set when the message is split into several messages")
+	}
+
+	return msgs, nil
+}
+
+//	@Summary		List messages
+//	@Description	Returns states of messages, newest first. Recipients' state history is not included
+//	@Security		ApiAuth
+//	@Tags			User, Messages
+//	@Produce		json
+//	@Param			deviceId	query		string						false	"Device ID"
+//	@Param			state		query		string						false	"Message state"	Enums(Pending, Processed, Sent, Delivered, Failed)
+//	@Param			tag			query		[]string					false	"Messages with all the tags"
+//	@Param			metadata	query		[]string					false	"Messages with all the metadata values in `key:value` format"
+//	@Param			from		query		string						false	"Messages created at or after"	Format(date-time)
+//	@Param			to			query		string						false	"Messages created before"		Format(date-time)
+//	@Param			limit		query		int							false	"Max number of messages"		default(50)	maximum(500)
+//	@Param			offset		query		int							false	"Number of messages to skip"	default(0)
+//	@Success		200			{object}	[]messages.MessageStateOut	"Message states"
+//	@Failure		400			{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/messages [get]
+//
+// List messages
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	query := listQuery{}
+	if err := h.QueryParserValidator(c, &query); err != nil {
+		return err
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultListLimit
+	}
+
+	items, err := h.messagesSvc.Select(user, query.toFilter(), query.Limit, query.Offset)
+	if err != nil {
+		return fmt.Errorf("can't select messages: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
 //	@Summary		Get message state
 //	@Description	Returns message state by ID
 //	@Security		ApiAuth
 //	@Tags			User, Messages
 //	@Produce		json
 //	@Param			id	path		string						true	"Message ID"
-//	@Success		200	{object}	smsgateway.MessageState		"Message state"
+//	@Success		200	{object}	messages.MessageStateOut	"Message state"
 //	@Failure		400	{object}	smsgateway.ErrorResponse	"Invalid request"
 //	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
 //	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
@@ -143,6 +479,71 @@ func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
 	return c.JSON(state)
 }
 
+//	@Summary		Retry message
+//	@Description	Enqueues a new message for the failed recipients of the message. The new message is linked to the original one and is sent via the original device unless `deviceId` is provided. The sending window of the original message is applied to the new one
+//	@Security		ApiAuth
+//	@Tags			User, Messages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Message ID"
+//	@Param			request	body		retryRequest				false	"Retry request"
+//	@Success		202		{object}	messages.MessageStateOut	"Message enqueued"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Message not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			202		{string}	Location					"Get message state URL"
+//	@Router			/3rdparty/v1/messages/{id}/retry [post]
+//
+// Retry message
+func (h *ThirdPartyController) postRetry(user models.User, c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	req := retryRequest{}
+	if len(c.Body()) > 0 {
+		if err := h.BodyParserValidator(c, &req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	var device *models.Device
+	if req.DeviceID != "" {
+		d, err := h.devicesSvc.Get(user.ID, devices.WithID(req.DeviceID))
+		if err != nil {
+			if errors.Is(err, devices.ErrNotFound) {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid device ID")
+			}
+
+			return err
+		}
+		device = &d
+	}
+
+	state, err := h.messagesSvc.Retry(user, id, device)
+	if err != nil {
+		var errValidation messages.ErrValidation
+		if isBadRequest := errors.As(err, &errValidation); isBadRequest {
+			return fiber.NewError(fiber.StatusBadRequest, errValidation.Error())
+		}
+		if errors.Is(err, messages.ErrMessageNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+
+		return fmt.Errorf("can't retry message: %w", err)
+	}
+
+	location, err := c.GetRouteURL(route3rdPartyGetMessage, fiber.Map{
+		"id": state.ID,
+	})
+	if err != nil {
+		h.Logger.Warn("Failed to get route URL", zap.String("route", route3rdPartyGetMessage), zap.Error(err))
+	} else {
+		c.Location(location)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(state)
+}
+
 //	@Summary		Request inbox messages export
 //	@Description	Initiates process of inbox messages export via webhooks. For each message the `sms:received` webhook will be triggered. The webhooks will be triggered without specific order.
 //	@Security		ApiAuth
@@ -180,8 +581,10 @@ func (h *ThirdPartyController) postInboxExport(user models.User, c *fiber.Ctx) e
 }
 
 func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.list))
 	router.Post("", userauth.WithUser(h.post))
 	router.Get(":id", userauth.WithUser(h.get))
+	router.Post(":id/retry", userauth.WithUser(h.postRetry))
 
 	router.Post("inbox/export", userauth.WithUser(h.postInboxExport))
 }
@@ -194,5 +597,7 @@ func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyContr
 		},
 		messagesSvc: params.MessagesSvc,
 		devicesSvc:  params.DevicesSvc,
+		contactsSvc: params.ContactsSvc,
+		quotasSvc:   params.QuotasSvc,
 	}
 }
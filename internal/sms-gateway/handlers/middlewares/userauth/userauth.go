@@ -2,22 +2,41 @@ package userauth
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/apikeys"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
 )
 
-const localsUser = "user"
+// HeaderOrganization selects the organization the request is made on behalf
+// of, the user's default organization is used if not set.
+const HeaderOrganization = "X-Organization-ID"
+
+const (
+	localsUser       = "user"
+	localsAPIKey     = "apikey"
+	localsMembership = "membership"
+)
 
 // NewBasic returns a middleware that will check if the request contains a valid
 // "Authorization" header in the form of "Basic <base64 encoded username:password>".
 // If the header is valid, the middleware will authorize the user and store the
 // user in the request's Locals under the key LocalsUser. If the header is invalid,
 // the middleware will call c.Next() and continue with the request.
-func NewBasic(authSvc *auth.Service) fiber.Handler {
+// Failed attempts are tracked per login and client IP, locked out requests are
+// rejected with 429 Too Many Requests.
+func NewBasic(authSvc *auth.Service, lockoutSvc *lockout.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		auth := c.Get(fiber.HeaderAuthorization)
 
@@ -45,11 +64,24 @@ func NewBasic(authSvc *auth.Service) fiber.Handler {
 		username := creds[:index]
 		password := creds[index+1:]
 
+		loginKey := lockout.LoginKey(username)
+		ipKey := lockout.IPKey(c.IP())
+		if err := lockoutSvc.Check(lockout.KindBasic, loginKey, ipKey); err != nil {
+			return TooManyAttempts(c, err)
+		}
+
 		user, err := authSvc.AuthorizeUser(username, password)
 		if err != nil {
+			if err := lockoutSvc.Fail(lockout.KindBasic, loginKey, ipKey); err != nil {
+				return TooManyAttempts(c, err)
+			}
 			return fiber.ErrUnauthorized
 		}
 
+		// the IP counter is not reset, otherwise a single known account allows
+		// to guess others endlessly
+		lockoutSvc.Succeed(lockout.KindBasic, loginKey)
+
 		c.Locals(localsUser, user)
 
 		return c.Next()
@@ -61,6 +93,8 @@ func NewBasic(authSvc *auth.Service) fiber.Handler {
 // If the header is valid, the middleware will authorize the user and store the
 // user in the request's Locals under the key LocalsUser. If the header is invalid,
 // the middleware will call c.Next() and continue with the request.
+// Failed attempts are tracked per client IP, locked out requests are rejected
+// with 429 Too Many Requests.
 func NewCode(authSvc *auth.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		auth := c.Get(fiber.HeaderAuthorization)
@@ -72,17 +106,166 @@ func NewCode(authSvc *auth.Service) fiber.Handler {
 		// Get the code
 		code := auth[5:]
 
-		user, err := authSvc.AuthorizeUserByCode(code)
+		user, err := authSvc.AuthorizeUserByCode(code, c.IP())
+		if IsLockedOut(err) {
+			return TooManyAttempts(c, err)
+		}
+		if err != nil {
+			return fiber.ErrUnauthorized
+		}
+
+		c.Locals(localsUser, user)
+
+		return c.Next()
+	}
+}
+
+// IsLockedOut reports whether the error is returned because the client is
+// locked out after too many failed attempts.
+func IsLockedOut(err error) bool {
+	var errLocked lockout.LockedError
+	return errors.As(err, &errLocked)
+}
+
+// TooManyAttempts returns 429 Too Many Requests with the Retry-After header
+// set to the end of the lockout.
+func TooManyAttempts(c *fiber.Ctx, err error) error {
+	var errLocked lockout.LockedError
+	if errors.As(err, &errLocked) {
+		retryAfter := math.Ceil(time.Until(errLocked.Until).Seconds())
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(max(retryAfter, 1))))
+	}
+
+	return fiber.NewError(fiber.StatusTooManyRequests, "Too many failed attempts, try again later")
+}
+
+// NewAPIKey returns a middleware that will check if the request contains a valid
+// "Authorization" header in the form of "Bearer <API key>". If the header is
+// valid, the middleware will authorize the user, store the user in the request's
+// Locals under the key LocalsUser and the key scopes for ScopeRequired. Bearer
+// tokens that are not API keys are passed to the next handler.
+func NewAPIKey(apikeysSvc *apikeys.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get(fiber.HeaderAuthorization)
+
+		if len(auth) <= 7 || !strings.EqualFold(auth[:7], "bearer ") || !apikeys.IsAPIKey(auth[7:]) {
+			return c.Next()
+		}
+
+		user, principal, err := apikeysSvc.Authorize(auth[7:], c.IP())
+		if err != nil {
+			return fiber.ErrUnauthorized
+		}
+
+		c.Locals(localsUser, user)
+		c.Locals(localsAPIKey, principal)
+
+		return c.Next()
+	}
+}
+
+// NewJWT returns a middleware that will check if the request contains a valid
+// "Authorization" header in the form of "Bearer <access token>". If the header
+// is valid and its subject is an existing enabled user, the middleware will
+// store the user in the request's Locals under the key LocalsUser. API keys are
+// passed to the next handler.
+func NewJWT(tokensSvc *tokens.Service, authSvc *auth.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+
+		if len(header) <= 7 || !strings.EqualFold(header[:7], "bearer ") || apikeys.IsAPIKey(header[7:]) {
+			return c.Next()
+		}
+
+		userID, err := tokensSvc.Authorize(header[7:])
 		if err != nil {
 			return fiber.ErrUnauthorized
 		}
 
+		user, err := authSvc.AuthorizeUserByID(userID)
+		if errors.Is(err, auth.ErrUserNotFound) || errors.Is(err, auth.ErrUserDisabled) {
+			return fiber.ErrUnauthorized
+		}
+		if err != nil {
+			return fmt.Errorf("can't get user: %w", err)
+		}
+
 		c.Locals(localsUser, user)
 
 		return c.Next()
 	}
 }
 
+// ScopeRequired is a middleware that ensures the API key the request is
+// authorized with has the scope. Safe requests require the read scope, other
+// ones the write scope. An empty scope denies access with API keys. Requests
+// authorized by other means are not restricted.
+func ScopeRequired(read, write apikeys.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := c.Locals(localsAPIKey).(apikeys.Principal)
+		if !ok {
+			return c.Next()
+		}
+
+		scope := write
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			scope = read
+		}
+
+		if scope == "" || !principal.HasScope(scope) {
+			return fiber.NewError(fiber.StatusForbidden, "API key has no access to the resource")
+		}
+
+		return c.Next()
+	}
+}
+
+// OrganizationRequired is a middleware that resolves the organization the
+// authorized user acts in. The user in the request's Locals is replaced with
+// the organization account, so resources are shared between members, and the
+// membership is stored for RoleRequired and GetMembership. Users that are not
+// members of the organization are denied.
+func OrganizationRequired(orgsSvc *organizations.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		membership, err := orgsSvc.Resolve(GetUser(c).ID, c.Get(HeaderOrganization))
+		if errors.Is(err, organizations.ErrNotFound) {
+			return fiber.NewError(fiber.StatusForbidden, "Not a member of the organization")
+		}
+		if err != nil {
+			return err
+		}
+
+		c.Locals(localsUser, models.User{ID: membership.OrganizationID})
+		c.Locals(localsMembership, membership)
+
+		return c.Next()
+	}
+}
+
+// RoleRequired is a middleware that ensures the member has at least the role.
+// Safe requests require the read role, other ones the write role.
+func RoleRequired(read, write organizations.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role := write
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			role = read
+		}
+
+		if !GetMembership(c).Role.Allows(role) {
+			return fiber.NewError(fiber.StatusForbidden, "Insufficient role in the organization")
+		}
+
+		return c.Next()
+	}
+}
+
+// GetMembership returns the membership stored by OrganizationRequired.
+//
+// It panics if the membership is not present.
+func GetMembership(c *fiber.Ctx) organizations.Membership {
+	return c.Locals(localsMembership).(organizations.Membership)
+}
+
 // HasUser checks if a user is present in the Locals of the given context.
 // It returns true if the Locals contain a user under the key LocalsUser,
 // otherwise returns false.
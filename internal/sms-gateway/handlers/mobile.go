@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/autoreplies"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/converters"
+	devicesctrl "github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/deviceauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/settings"
@@ -15,7 +17,10 @@ import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
 	"github.com/capcom6/go-helpers/anys"
 	"github.com/capcom6/go-helpers/slices"
 	"github.com/go-playground/validator/v10"
@@ -32,9 +37,14 @@ type mobileHandler struct {
 	authSvc     *auth.Service
 	devicesSvc  *devices.Service
 	messagesSvc *messages.Service
+	tokensSvc   *tokens.Service
+	orgsSvc     *organizations.Service
+	lockoutSvc  *lockout.Service
 
-	webhooksCtrl *webhooks.MobileController
-	settingsCtrl *settings.MobileController
+	webhooksCtrl    *webhooks.MobileController
+	settingsCtrl    *settings.MobileController
+	autorepliesCtrl *autoreplies.MobileController
+	devicesCtrl     *devicesctrl.MobileController
 
 	idGen func() string
 }
@@ -61,7 +71,7 @@ func (h *mobileHandler) getDevice(device models.Device, c *fiber.Ctx) error {
 }
 
 //	@Summary		Register device
-//	@Description	Registers new device for new or existing user. Returns user credentials only for new users
+//	@Description	Registers new device for new or existing user. Devices of existing users are registered in the organization selected with the `X-Organization-ID` header, admin role is required. Without the header, devices are registered in the organization stored under the user's account or, for other users, in the default one if the user is its admin. Returns user credentials only for new users
 //	@Security		ApiAuth
 //	@Security		UserCode
 //	@Security		ServerKey
@@ -72,8 +82,10 @@ func (h *mobileHandler) getDevice(device models.Device, c *fiber.Ctx) error {
 //	@Success		201		{object}	smsgateway.MobileRegisterResponse	"Device registered"
 //	@Failure		400		{object}	smsgateway.ErrorResponse			"Invalid request"
 //	@Failure		401		{object}	smsgateway.ErrorResponse			"Unauthorized (private mode only)"
+//	@Failure		403		{object}	smsgateway.ErrorResponse			"Insufficient role in the organization"
 //	@Failure		429		{object}	smsgateway.ErrorResponse			"Too many requests"
 //	@Failure		500		{object}	smsgateway.ErrorResponse			"Internal server error"
+//	@Header			429		{string}	Retry-After							"Seconds until the lockout ends"
 //	@Router			/mobile/v1/device [post]
 //
 // Register device
@@ -91,8 +103,29 @@ func (h *mobileHandler) postDevice(c *fiber.Ctx) (err error) {
 	)
 
 	if userauth.HasUser(c) {
-		user = userauth.GetUser(c)
-		login = user.ID
+		login = userauth.GetUser(c).ID
+
+		// devices belong to the organization the user manages
+		var membership organizations.Membership
+		if orgID := c.Get(userauth.HeaderOrganization); orgID != "" {
+			membership, err = h.orgsSvc.Resolve(login, orgID)
+			if err == nil && !membership.Role.Allows(organizations.RoleAdmin) {
+				err = organizations.ErrForbidden
+			}
+		} else {
+			membership, err = h.orgsSvc.ResolveManaged(login)
+		}
+		if errors.Is(err, organizations.ErrNotFound) {
+			return fiber.NewError(fiber.StatusForbidden, "Not a member of the organization")
+		}
+		if errors.Is(err, organizations.ErrForbidden) {
+			return fiber.NewError(fiber.StatusForbidden, "Insufficient role in the organization")
+		}
+		if err != nil {
+			return fmt.Errorf("can't resolve organization: %w", err)
+		}
+
+		user = models.User{ID: membership.OrganizationID}
 	} else {
 		id := h.idGen()
 		login = strings.ToUpper(id[:6])
@@ -102,6 +135,10 @@ func (h *mobileHandler) postDevice(c *fiber.Ctx) (err error) {
 		if err != nil {
 			return fmt.Errorf("can't create user: %w", err)
 		}
+
+		if err = h.orgsSvc.CreatePersonal(user.ID); err != nil {
+			return err
+		}
 	}
 
 	device, err := h.authSvc.RegisterDevice(user, req.Name, req.PushToken)
@@ -155,8 +192,8 @@ func (h *mobileHandler) patchDevice(device models.Device, c *fiber.Ctx) error {
 //	@Tags			Device, Messages
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	smsgateway.MobileGetMessagesResponse	"List of pending messages"
-//	@Failure		500	{object}	smsgateway.ErrorResponse				"Internal server error"
+//	@Success		200	{object}	[]messages.MobileMessage	"List of pending messages"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
 //	@Router			/mobile/v1/message [get]
 //
 // Get messages for sending
@@ -167,11 +204,9 @@ func (h *mobileHandler) getMessage(device models.Device, c *fiber.Ctx) error {
 	}
 
 	return c.JSON(
-		smsgateway.MobileGetMessagesResponse(
-			slices.Map(
-				msgs,
-				converters.MessageToDTO,
-			),
+		slices.Map(
+			msgs,
+			converters.MessageToDTO,
 		),
 	)
 }
@@ -200,7 +235,7 @@ func (h *mobileHandler) patchMessage(device models.Device, c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusBadRequest, err.Error())
 		}
 
-		err := h.messagesSvc.UpdateState(device.ID, v)
+		err := h.messagesSvc.UpdateState(device, v)
 		if err != nil && !errors.Is(err, messages.ErrMessageNotFound) {
 			h.Logger.Error("Can't update message status", zap.Error(err))
 		}
@@ -216,7 +251,9 @@ func (h *mobileHandler) patchMessage(device models.Device, c *fiber.Ctx) error {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	smsgateway.MobileUserCodeResponse	"User code"
+//	@Failure		429	{object}	smsgateway.ErrorResponse			"Too many failed attempts"
 //	@Failure		500	{object}	smsgateway.ErrorResponse			"Internal server error"
+//	@Header			429	{string}	Retry-After							"Seconds until the lockout ends"
 //	@Router			/mobile/v1/user/code [get]
 //
 // Get user code
@@ -233,7 +270,7 @@ func (h *mobileHandler) getUserCode(user models.User, c *fiber.Ctx) error {
 }
 
 //	@Summary		Change password
-//	@Description	Changes the user's password
+//	@Description	Changes the user's password and revokes the user's refresh tokens
 //	@Security		MobileToken
 //	@Tags			Device
 //	@Accept			json
@@ -258,6 +295,10 @@ func (h *mobileHandler) changePassword(device models.Device, c *fiber.Ctx) error
 		return fiber.NewError(fiber.StatusUnauthorized, "Invalid current password")
 	}
 
+	if err := h.tokensSvc.RevokeAll(device.UserID); err != nil {
+		h.Logger.Error("failed to revoke refresh tokens", zap.Error(err))
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -265,7 +306,7 @@ func (h *mobileHandler) Register(router fiber.Router) {
 	router = router.Group("/mobile/v1")
 
 	router.Post("/device",
-		userauth.NewBasic(h.authSvc),
+		userauth.NewBasic(h.authSvc, h.lockoutSvc),
 		userauth.NewCode(h.authSvc),
 		keyauth.New(keyauth.Config{
 			Next: func(c *fiber.Ctx) bool {
@@ -283,7 +324,7 @@ func (h *mobileHandler) Register(router fiber.Router) {
 	)
 
 	router.Get("/user/code",
-		userauth.NewBasic(h.authSvc),
+		userauth.NewBasic(h.authSvc, h.lockoutSvc),
 		userauth.UserRequired(),
 		userauth.WithUser(h.getUserCode),
 	)
@@ -307,6 +348,10 @@ func (h *mobileHandler) Register(router fiber.Router) {
 	h.webhooksCtrl.Register(router.Group("/webhooks"))
 
 	h.settingsCtrl.Register(router.Group("/settings"))
+
+	h.autorepliesCtrl.Register(router.Group("/inbox"))
+
+	h.devicesCtrl.Register(router.Group("/device"))
 }
 
 type mobileHandlerParams struct {
@@ -318,21 +363,31 @@ type mobileHandlerParams struct {
 	AuthSvc     *auth.Service
 	DevicesSvc  *devices.Service
 	MessagesSvc *messages.Service
-
-	WebhooksCtrl *webhooks.MobileController
-	SettingsCtrl *settings.MobileController
+	TokensSvc   *tokens.Service
+	OrgsSvc     *organizations.Service
+	LockoutSvc  *lockout.Service
+
+	WebhooksCtrl    *webhooks.MobileController
+	SettingsCtrl    *settings.MobileController
+	AutorepliesCtrl *autoreplies.MobileController
+	DevicesCtrl     *devicesctrl.MobileController
 }
 
 func newMobileHandler(params mobileHandlerParams) *mobileHandler {
 	idGen, _ := nanoid.Standard(21)
 
 	return &mobileHandler{
-		Handler:      base.Handler{Logger: params.Logger, Validator: params.Validator},
-		authSvc:      params.AuthSvc,
-		devicesSvc:   params.DevicesSvc,
-		messagesSvc:  params.MessagesSvc,
-		webhooksCtrl: params.WebhooksCtrl,
-		settingsCtrl: params.SettingsCtrl,
-		idGen:        idGen,
+		Handler:         base.Handler{Logger: params.Logger, Validator: params.Validator},
+		authSvc:         params.AuthSvc,
+		devicesSvc:      params.DevicesSvc,
+		messagesSvc:     params.MessagesSvc,
+		tokensSvc:       params.TokensSvc,
+		orgsSvc:         params.OrgsSvc,
+		lockoutSvc:      params.LockoutSvc,
+		webhooksCtrl:    params.WebhooksCtrl,
+		settingsCtrl:    params.SettingsCtrl,
+		autorepliesCtrl: params.AutorepliesCtrl,
+		devicesCtrl:     params.DevicesCtrl,
+		idGen:           idGen,
 	}
 }
@@ -1,10 +1,21 @@
 package handlers
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/apikeys"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/autoreplies"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/campaigns"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contacts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/contentpolicy"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/links"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/logs"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/otp"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/settings"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/suppressions"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/tokens"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/users"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/webhooks"
 	"github.com/capcom6/go-infra-fx/http"
 	"go.uber.org/fx"
@@ -21,6 +32,7 @@ var Module = fx.Module(
 		http.AsApiHandler(newThirdPartyHandler),
 		http.AsApiHandler(newMobileHandler),
 		http.AsApiHandler(newUpstreamHandler),
+		http.AsApiHandler(newAdminHandler),
 	),
 	fx.Provide(
 		newHealthHandler,
@@ -28,9 +40,23 @@ var Module = fx.Module(
 		webhooks.NewThirdPartyController,
 		webhooks.NewMobileController,
 		devices.NewThirdPartyController,
+		devices.NewMobileController,
+		devices.NewAdminController,
 		settings.NewThirdPartyController,
 		settings.NewMobileController,
 		logs.NewThirdPartyController,
+		campaigns.NewThirdPartyController,
+		contacts.NewThirdPartyController,
+		contentpolicy.NewThirdPartyController,
+		links.NewPublicController,
+		otp.NewThirdPartyController,
+		autoreplies.NewThirdPartyController,
+		autoreplies.NewMobileController,
+		suppressions.NewThirdPartyController,
+		apikeys.NewThirdPartyController,
+		tokens.NewThirdPartyController,
+		organizations.NewThirdPartyController,
+		users.NewAdminController,
 		fx.Private,
 	),
 )
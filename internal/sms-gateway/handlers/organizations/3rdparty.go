@@ -0,0 +1,247 @@
+package organizations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	OrganizationsSvc *organizations.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	orgsSvc *organizations.Service
+}
+
+//	@Summary		Get organization
+//	@Description	Returns the organization the request is made on behalf of, selected with the `X-Organization-ID` header or the user's default one
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Produce		json
+//	@Param			X-Organization-ID	header		string						false	"Organization ID"
+//	@Success		200					{object}	organizations.OrganizationOut	"Organization"
+//	@Failure		401					{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse		"Not a member of the organization"
+//	@Failure		500					{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/organization [get]
+//
+// Get organization
+func (h *ThirdPartyController) get(c *fiber.Ctx) error {
+	org, err := h.orgsSvc.Get(userauth.GetMembership(c))
+	if err != nil {
+		return h.handleError(err, "can't get organization")
+	}
+
+	return c.JSON(org)
+}
+
+//	@Summary		Rename organization
+//	@Description	Changes the name of the organization, admin role is required
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Organization-ID	header	string						false	"Organization ID"
+//	@Param			request				body	organizations.OrganizationIn	true	"Organization"
+//	@Success		204					"Updated"
+//	@Failure		400					{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse	"Insufficient role"
+//	@Failure		500					{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/organization [patch]
+//
+// Rename organization
+func (h *ThirdPartyController) patch(c *fiber.Ctx) error {
+	req := organizations.OrganizationIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.orgsSvc.Rename(userauth.GetMembership(c), req); err != nil {
+		return h.handleError(err, "can't rename organization")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		List memberships
+//	@Description	Returns organizations the user is a member of along with the user's roles
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Produce		json
+//	@Success		200	{object}	[]organizations.OrganizationOut	"Organizations"
+//	@Failure		401	{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Router			/3rdparty/v1/organization/memberships [get]
+//
+// List memberships
+func (h *ThirdPartyController) listMemberships(c *fiber.Ctx) error {
+	items, err := h.orgsSvc.SelectMemberships(userauth.GetMembership(c).UserID)
+	if err != nil {
+		return fmt.Errorf("can't select memberships: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		List members
+//	@Description	Returns members of the organization, the oldest first
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Produce		json
+//	@Param			X-Organization-ID	header		string						false	"Organization ID"
+//	@Success		200					{object}	[]organizations.MemberOut	"Members"
+//	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse	"Not a member of the organization"
+//	@Failure		500					{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/organization/members [get]
+//
+// List members
+func (h *ThirdPartyController) listMembers(c *fiber.Ctx) error {
+	items, err := h.orgsSvc.SelectMembers(userauth.GetMembership(c).OrganizationID)
+	if err != nil {
+		return fmt.Errorf("can't select members: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Add member
+//	@Description	Adds a user with the role to the organization. An existing user is added by the one-time code generated by that user, otherwise a new user is created and its password is returned only once. Admin role is required, only owners can add owners
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Organization-ID	header		string							false	"Organization ID"
+//	@Param			request				body		organizations.NewMemberIn		true	"Member"
+//	@Success		201					{object}	organizations.CreatedMemberOut	"Created"
+//	@Failure		400					{object}	smsgateway.ErrorResponse		"Invalid request"
+//	@Failure		401					{object}	smsgateway.ErrorResponse		"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse		"Insufficient role or invalid code"
+//	@Failure		409					{object}	smsgateway.ErrorResponse		"Already a member"
+//	@Failure		429					{object}	smsgateway.ErrorResponse		"Too many failed code attempts"
+//	@Failure		500					{object}	smsgateway.ErrorResponse		"Internal server error"
+//	@Header			429					{string}	Retry-After						"Seconds until the lockout ends"
+//	@Router			/3rdparty/v1/organization/members [post]
+//
+// Add member
+func (h *ThirdPartyController) postMember(c *fiber.Ctx) error {
+	req := organizations.NewMemberIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	member, err := h.orgsSvc.Invite(userauth.GetMembership(c), req, c.IP())
+	if userauth.IsLockedOut(err) {
+		return userauth.TooManyAttempts(c, err)
+	}
+	if err != nil {
+		return h.handleError(err, "can't add member")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+//	@Summary		Change member role
+//	@Description	Changes the role of the member. Admin role is required, only owners can manage owners. The last owner can't be demoted
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Organization-ID	header	string					false	"Organization ID"
+//	@Param			userId				path	string					true	"Member user ID"
+//	@Param			request				body	organizations.MemberIn	true	"Member"
+//	@Success		204					"Updated"
+//	@Failure		400					{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse	"Insufficient role"
+//	@Failure		404					{object}	smsgateway.ErrorResponse	"Member not found"
+//	@Failure		409					{object}	smsgateway.ErrorResponse	"The last owner"
+//	@Failure		500					{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/organization/members/{userId} [patch]
+//
+// Change member role
+func (h *ThirdPartyController) patchMember(c *fiber.Ctx) error {
+	req := organizations.MemberIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.orgsSvc.UpdateRole(userauth.GetMembership(c), c.Params("userId"), req); err != nil {
+		return h.handleError(err, "can't update member")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Remove member
+//	@Description	Removes the member from the organization, the user itself is kept. Admin role is required to remove others, only owners can remove owners. The last owner can't be removed
+//	@Security		ApiAuth
+//	@Tags			User, Organization
+//	@Produce		json
+//	@Param			X-Organization-ID	header	string	false	"Organization ID"
+//	@Param			userId				path	string	true	"Member user ID"
+//	@Success		204					"Removed"
+//	@Failure		401					{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		403					{object}	smsgateway.ErrorResponse	"Insufficient role"
+//	@Failure		404					{object}	smsgateway.ErrorResponse	"Member not found"
+//	@Failure		409					{object}	smsgateway.ErrorResponse	"The last owner"
+//	@Failure		500					{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/organization/members/{userId} [delete]
+//
+// Remove member
+func (h *ThirdPartyController) deleteMember(c *fiber.Ctx) error {
+	if err := h.orgsSvc.Remove(userauth.GetMembership(c), c.Params("userId")); err != nil {
+		return h.handleError(err, "can't remove member")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) handleError(err error, message string) error {
+	switch {
+	case errors.Is(err, organizations.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, organizations.ErrForbidden), errors.Is(err, organizations.ErrInvalidCode):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, organizations.ErrLastOwner), errors.Is(err, organizations.ErrAlreadyMember):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", h.get)
+	router.Patch("", h.patch)
+	router.Get("/memberships", h.listMemberships)
+	router.Get("/members", h.listMembers)
+	router.Post("/members", h.postMember)
+	router.Patch("/members/:userId", h.patchMember)
+	router.Delete("/members/:userId", h.deleteMember)
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("organizations"),
+			Validator: params.Validator,
+		},
+		orgsSvc: params.OrganizationsSvc,
+	}
+}
@@ -0,0 +1,225 @@
+package otp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/otp"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	OTPSvc     *otp.Service
+	DevicesSvc *devices.Service
+	QuotasSvc  *quotas.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type sendRequest struct {
+	// Recipient phone number
+	PhoneNumber string `json:"phoneNumber" validate:"required,max=128" example:"79990001234"`
+	// Message template with `{{code}}` placeholder
+	Template string `json:"template,omitempty" validate:"max=640" example:"Your code is {{code}}" default:"Your code is {{code}}"`
+	// Number of digits
+	Length int `json:"length,omitempty" validate:"omitempty,min=4,max=10" example:"6" default:"6"`
+	// Code lifetime in seconds
+	TTL uint32 `json:"ttl,omitempty" validate:"omitempty,min=30,max=86400" example:"300" default:"300"`
+	// Device ID (if not set - random device will be used)
+	DeviceID string `json:"deviceId,omitempty" validate:"omitempty,max=21" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// SIM card number (1-3), if not set - default SIM will be used
+	SimNumber *uint8 `json:"simNumber,omitempty" validate:"omitempty,min=1,max=3" example:"1"`
+}
+
+type sendResponse struct {
+	// Code ID to verify the code with
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// ID of the message with the code
+	MessageID string `json:"messageId" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Code expiration time
+	ValidUntil time.Time `json:"validUntil" example:"2020-01-01T00:05:00Z"`
+}
+
+type verifyRequest struct {
+	// Code ID
+	ID string `json:"id" validate:"required,max=21" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Code received by the recipient
+	Code string `json:"code" validate:"required,numeric,max=10" example:"123456"`
+}
+
+type verifyResponse struct {
+	// Is the code valid
+	Verified bool `json:"verified" example:"false"`
+	// Attempts left before the code is invalidated and the recipient is locked out
+	AttemptsLeft int `json:"attemptsLeft,omitempty" example:"4"`
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	otpSvc     *otp.Service
+	devicesSvc *devices.Service
+	quotasSvc  *quotas.Service
+}
+
+//	@Summary		Send one-time code
+//	@Description	Generates a numeric code and sends it to the recipient as a transactional message. Only a hash of the code is stored until it is verified or expires
+//	@Security		ApiAuth
+//	@Tags			User, OTP
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		sendRequest					true	"Send code request"
+//	@Success		202		{object}	sendResponse				"Code sent"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		429		{object}	smsgateway.ErrorResponse	"Recipient is locked out"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			429		{string}	Retry-After					"Seconds until the lockout ends"
+//	@Router			/3rdparty/v1/otp [post]
+//
+// Send one-time code
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := sendRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	device, err := h.selectDevice(user, req.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	out, err := h.otpSvc.Send(device, otp.SendIn{
+		PhoneNumber: req.PhoneNumber,
+		Template:    req.Template,
+		Length:      req.Length,
+		TTL:         time.Duration(req.TTL) * time.Second,
+		SimNumber:   req.SimNumber,
+	})
+	if err != nil {
+		return h.handleError(c, err, "can't send code")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(sendResponse{
+		ID:         out.ID,
+		MessageID:  out.MessageID,
+		ValidUntil: out.ValidUntil,
+	})
+}
+
+//	@Summary		Verify one-time code
+//	@Description	Verifies the code. A verified code can't be used again. After 5 wrong codes the code is invalidated and the recipient is locked out for 15 minutes
+//	@Security		ApiAuth
+//	@Tags			User, OTP
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyRequest				true	"Verify code request"
+//	@Success		200		{object}	verifyResponse				"Verification result"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"Code not found or expired"
+//	@Failure		429		{object}	smsgateway.ErrorResponse	"Recipient is locked out"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			429		{string}	Retry-After					"Seconds until the lockout ends"
+//	@Router			/3rdparty/v1/otp/verify [post]
+//
+// Verify one-time code
+func (h *ThirdPartyController) postVerify(user models.User, c *fiber.Ctx) error {
+	req := verifyRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	out, err := h.otpSvc.Verify(user.ID, req.ID, req.Code)
+	if err != nil {
+		return h.handleError(c, err, "can't verify code")
+	}
+
+	return c.JSON(verifyResponse{
+		Verified:     out.Verified,
+		AttemptsLeft: out.AttemptsLeft,
+	})
+}
+
+func (h *ThirdPartyController) selectDevice(user models.User, deviceID string) (models.Device, error) {
+	if deviceID != "" {
+		device, err := h.devicesSvc.Get(user.ID, devices.WithID(deviceID))
+		if errors.Is(err, devices.ErrNotFound) {
+			return device, fiber.NewError(fiber.StatusBadRequest, "Invalid device ID")
+		}
+		if err != nil {
+			return device, fmt.Errorf("can't get device: %w", err)
+		}
+
+		return device, nil
+	}
+
+	items, err := h.devicesSvc.Select(user.ID, devices.WithEnabled())
+	if err != nil {
+		return models.Device{}, fmt.Errorf("can't select devices: %w", err)
+	}
+
+	if len(items) < 1 {
+		return models.Device{}, fiber.NewError(fiber.StatusBadRequest, "No enabled devices registered")
+	}
+
+	if items, err = h.quotasSvc.Available(user.ID, items); err != nil {
+		return models.Device{}, fmt.Errorf("can't check sending limit: %w", err)
+	}
+	if len(items) < 1 {
+		return models.Device{}, fiber.NewError(fiber.StatusTooManyRequests, "Sending limit reached on all devices")
+	}
+
+	return devices.Route(items)
+}
+
+func (h *ThirdPartyController) handleError(c *fiber.Ctx, err error, message string) error {
+	var errLocked otp.LockedError
+	if errors.As(err, &errLocked) {
+		retryAfter := math.Ceil(time.Until(errLocked.Until).Seconds())
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(max(retryAfter, 1))))
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	}
+	if errors.Is(err, otp.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	var errValidation messages.ErrValidation
+	if errors.As(err, &errValidation) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Post("", userauth.WithUser(h.post))
+	router.Post("/verify", userauth.WithUser(h.postVerify))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("otp"),
+			Validator: params.Validator,
+		},
+		otpSvc:     params.OTPSvc,
+		devicesSvc: params.DevicesSvc,
+		quotasSvc:  params.QuotasSvc,
+	}
+}
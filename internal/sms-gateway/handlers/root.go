@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/links"
 	"github.com/android-sms-gateway/server/pkg/swagger"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
@@ -10,6 +11,7 @@ import (
 
 type rootHandler struct {
 	healthHandler *healthHandler
+	linksHandler  *links.PublicController
 }
 
 func (h *rootHandler) Register(app *fiber.App) {
@@ -22,6 +24,7 @@ func (h *rootHandler) Register(app *fiber.App) {
 	})
 
 	h.healthHandler.Register(app)
+	h.linksHandler.Register(app.Group("/l"))
 	app.Use("/api", filesystem.New(filesystem.Config{
 		Root:       http.FS(swagger.Docs),
 		PathPrefix: "docs",
@@ -32,8 +35,9 @@ func (h *rootHandler) Register(app *fiber.App) {
 	})
 }
 
-func newRootHandler(healthHandler *healthHandler) *rootHandler {
+func newRootHandler(healthHandler *healthHandler, linksHandler *links.PublicController) *rootHandler {
 	return &rootHandler{
 		healthHandler: healthHandler,
+		linksHandler:  linksHandler,
 	}
 }
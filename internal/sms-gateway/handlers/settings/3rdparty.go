@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
@@ -80,7 +81,7 @@ func (h *ThirdPartyController) put(user models.User, c *fiber.Ctx) error {
 	updated, err := h.settingsSvc.ReplaceSettings(user.ID, settings)
 
 	if err != nil {
-		return fmt.Errorf("can't update settings: %w", err)
+		return h.updateError(err)
 	}
 
 	return c.JSON(updated)
@@ -113,12 +114,20 @@ func (h *ThirdPartyController) patch(user models.User, c *fiber.Ctx) error {
 
 	updated, err := h.settingsSvc.UpdateSettings(user.ID, settings)
 	if err != nil {
-		return fmt.Errorf("can't update settings: %w", err)
+		return h.updateError(err)
 	}
 
 	return c.JSON(updated)
 }
 
+func (h *ThirdPartyController) updateError(err error) error {
+	if errors.Is(err, settings.ErrInvalidSettings) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return fmt.Errorf("can't update settings: %w", err)
+}
+
 func (h *ThirdPartyController) Register(app fiber.Router) {
 	app.Get("", userauth.WithUser(h.get))
 	app.Patch("", userauth.WithUser(h.patch))
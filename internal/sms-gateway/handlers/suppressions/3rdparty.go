@@ -0,0 +1,125 @@
+package suppressions
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/suppressions"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	SuppressionsSvc *suppressions.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	suppressionsSvc *suppressions.Service
+}
+
+//	@Summary		List suppression list
+//	@Description	Returns phone numbers messages are not sent to, newest first
+//	@Security		ApiAuth
+//	@Tags			User, Suppression list
+//	@Produce		json
+//	@Success		200	{object}	[]suppressions.EntryOut		"Suppression list"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/suppressions [get]
+//
+// List suppression list
+func (h *ThirdPartyController) list(user models.User, c *fiber.Ctx) error {
+	items, err := h.suppressionsSvc.Select(user.ID)
+	if err != nil {
+		return fmt.Errorf("can't select suppression list: %w", err)
+	}
+
+	return c.JSON(items)
+}
+
+//	@Summary		Add to suppression list
+//	@Description	Adds phone number to the suppression list. Recipients in the list are failed when messages are enqueued
+//	@Security		ApiAuth
+//	@Tags			User, Suppression list
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		suppressions.EntryIn		true	"Entry"
+//	@Success		201		{object}	suppressions.EntryOut		"Added"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/suppressions [post]
+//
+// Add to suppression list
+func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
+	req := suppressions.EntryIn{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	entry, err := h.suppressionsSvc.Add(user.ID, req)
+	if err != nil {
+		return fmt.Errorf("can't add to suppression list: %w", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+//	@Summary		Remove from suppression list
+//	@Description	Removes phone number from the suppression list
+//	@Security		ApiAuth
+//	@Tags			User, Suppression list
+//	@Produce		json
+//	@Param			phoneNumber	path	string	true	"Phone number"
+//	@Success		204			"Removed"
+//	@Failure		401			{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	smsgateway.ErrorResponse	"Phone number not found"
+//	@Failure		500			{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/suppressions/{phoneNumber} [delete]
+//
+// Remove from suppression list
+func (h *ThirdPartyController) delete(user models.User, c *fiber.Ctx) error {
+	phoneNumber, err := url.PathUnescape(c.Params("phoneNumber"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid phone number")
+	}
+
+	err = h.suppressionsSvc.Remove(user.ID, phoneNumber)
+	if errors.Is(err, suppressions.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Phone number not found")
+	}
+	if err != nil {
+		return fmt.Errorf("can't remove from suppression list: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Get("", userauth.WithUser(h.list))
+	router.Post("", userauth.WithUser(h.post))
+	router.Delete("/:phoneNumber", userauth.WithUser(h.delete))
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("suppressions"),
+			Validator: params.Validator,
+		},
+		suppressionsSvc: params.SuppressionsSvc,
+	}
+}
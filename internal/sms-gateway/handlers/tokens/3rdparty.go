@@ -0,0 +1,140 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/middlewares/userauth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	grantTypePassword     = "password"
+	grantTypeRefreshToken = "refresh_token"
+)
+
+type thirdPartyControllerParams struct {
+	fx.In
+
+	AuthSvc    *auth.Service
+	TokensSvc  *tokens.Service
+	LockoutSvc *lockout.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type tokenRequest struct {
+	// Grant type: `password` to authorize with Basic credentials, `refresh_token` to exchange the refresh token
+	GrantType string `json:"grantType" validate:"required,oneof=password refresh_token" example:"password"`
+	// Refresh token, required for `refresh_token` grant
+	RefreshToken string `json:"refreshToken,omitempty" validate:"required_if=GrantType refresh_token,max=64" example:"PyDmBQZZXYmyxMwED8FzyPyDmBQZZXYmyxMwED8Fzy"`
+}
+
+type revokeRequest struct {
+	// Refresh token to revoke
+	RefreshToken string `json:"refreshToken" validate:"required,max=64" example:"PyDmBQZZXYmyxMwED8FzyPyDmBQZZXYmyxMwED8Fzy"`
+}
+
+type ThirdPartyController struct {
+	base.Handler
+
+	authSvc    *auth.Service
+	tokensSvc  *tokens.Service
+	lockoutSvc *lockout.Service
+}
+
+//	@Summary		Issue access token
+//	@Description	Issues a short-lived access token and a refresh token. The `password` grant requires Basic credentials, the `refresh_token` grant exchanges the refresh token, which can be used only once. The access token is used as a bearer token
+//	@Security		ApiAuth
+//	@Tags			User, Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		tokenRequest				true	"Token request"
+//	@Success		200		{object}	tokens.TokenPair			"Tokens"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Invalid credentials or refresh token"
+//	@Failure		429		{object}	smsgateway.ErrorResponse	"Too many failed attempts"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Header			429		{string}	Retry-After					"Seconds until the lockout ends"
+//	@Router			/3rdparty/v1/auth/token [post]
+//
+// Issue access token
+func (h *ThirdPartyController) postToken(c *fiber.Ctx) error {
+	req := tokenRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	var (
+		pair tokens.TokenPair
+		err  error
+	)
+	switch req.GrantType {
+	case grantTypePassword:
+		if !userauth.HasUser(c) {
+			return fiber.ErrUnauthorized
+		}
+		pair, err = h.tokensSvc.Issue(userauth.GetUser(c).ID)
+	case grantTypeRefreshToken:
+		pair, err = h.tokensSvc.Refresh(req.RefreshToken)
+	}
+
+	if errors.Is(err, tokens.ErrInvalidToken) {
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("can't issue tokens: %w", err)
+	}
+
+	return c.JSON(pair)
+}
+
+//	@Summary		Revoke refresh token
+//	@Description	Revokes the refresh token, it can't be used anymore. Access tokens issued with it are valid until they expire
+//	@Tags			User, Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	revokeRequest	true	"Revoke request"
+//	@Success		204		"Revoked"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/3rdparty/v1/auth/revoke [post]
+//
+// Revoke refresh token
+func (h *ThirdPartyController) postRevoke(c *fiber.Ctx) error {
+	req := revokeRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.tokensSvc.Revoke(req.RefreshToken); err != nil {
+		return fmt.Errorf("can't revoke token: %w", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ThirdPartyController) Register(router fiber.Router) {
+	router.Post("/token", userauth.NewBasic(h.authSvc, h.lockoutSvc), h.postToken)
+	router.Post("/revoke", h.postRevoke)
+}
+
+func NewThirdPartyController(params thirdPartyControllerParams) *ThirdPartyController {
+	return &ThirdPartyController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("tokens"),
+			Validator: params.Validator,
+		},
+		authSvc:    params.AuthSvc,
+		tokensSvc:  params.TokensSvc,
+		lockoutSvc: params.LockoutSvc,
+	}
+}
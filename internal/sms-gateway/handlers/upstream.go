@@ -64,7 +64,12 @@ func (h *upstreamHandler) postPush(c *fiber.Ctx) error {
 	}
 
 	for _, v := range req {
-		if err := h.ValidateStruct(v); err != nil {
+		// command events are not known to the client library validation
+		if push.IsCommandEvent(v.Event) {
+			if err := h.Validator.StructExcept(v, "Event"); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+		} else if err := h.ValidateStruct(v); err != nil {
 			return err
 		}
 
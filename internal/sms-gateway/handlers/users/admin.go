@@ -0,0 +1,377 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/organizations"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/tokens"
+	"github.com/capcom6/go-helpers/slices"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jaevor/go-nanoid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const defaultListLimit = 50
+
+type adminControllerParams struct {
+	fx.In
+
+	AuthSvc     *auth.Service
+	OrgsSvc     *organizations.Service
+	TokensSvc   *tokens.Service
+	MessagesSvc *messages.Service
+
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+type listQuery struct {
+	// Login prefix
+	Query  string `query:"query" validate:"max=32"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=500"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+type userResponse struct {
+	// Login
+	ID string `json:"id" example:"ABCDEF"`
+	// Is the user disabled
+	Disabled bool `json:"disabled" example:"false"`
+	// Time the user was disabled
+	DisabledAt *time.Time `json:"disabledAt,omitempty" example:"2020-01-01T00:00:00Z"`
+	// Created at
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+}
+
+type createRequest struct {
+	// Login, generated if empty
+	Login string `json:"login,omitempty" validate:"omitempty,min=3,max=32,alphanum" example:"ABCDEF"`
+	// Password, generated if empty
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=72" example:"abcdefghijklmn"`
+}
+
+type credentialsResponse struct {
+	// Login
+	Login string `json:"login" example:"ABCDEF"`
+	// Password
+	Password string `json:"password" example:"abcdefghijklmn"`
+}
+
+type updateRequest struct {
+	// Disable or enable the user
+	Disabled *bool `json:"disabled" validate:"required" example:"true"`
+}
+
+type passwordRequest struct {
+	// New password, generated if empty
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=72" example:"abcdefghijklmn"`
+}
+
+type statsResponse struct {
+	// Stored messages of the user's devices
+	Messages messages.CountsOut `json:"messages"`
+}
+
+type AdminController struct {
+	base.Handler
+
+	authSvc     *auth.Service
+	orgsSvc     *organizations.Service
+	tokensSvc   *tokens.Service
+	messagesSvc *messages.Service
+
+	idGen func() string
+}
+
+//	@Summary		List users
+//	@Description	Returns users sorted by login, optionally filtered by the login prefix
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			query	query		string						false	"Login prefix"
+//	@Param			limit	query		int							false	"Max number of users"	default(50)	maximum(500)
+//	@Param			offset	query		int							false	"Number of users to skip"	default(0)
+//	@Success		200		{object}	[]userResponse				"Users"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users [get]
+//
+// List users
+func (h *AdminController) list(c *fiber.Ctx) error {
+	query := listQuery{}
+	if err := h.QueryParserValidator(c, &query); err != nil {
+		return err
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultListLimit
+	}
+
+	items, err := h.authSvc.SelectUsers(query.Query, query.Limit, query.Offset)
+	if err != nil {
+		return fmt.Errorf("can't select users: %w", err)
+	}
+
+	return c.JSON(slices.Map(items, userToDTO))
+}
+
+//	@Summary		Create user
+//	@Description	Creates a user with a single-member organization. The login and the password are generated if not provided
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createRequest				true	"User"
+//	@Success		201		{object}	credentialsResponse			"Created"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	smsgateway.ErrorResponse	"User already exists"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users [post]
+//
+// Create user
+func (h *AdminController) post(c *fiber.Ctx) error {
+	req := createRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	id := h.idGen()
+	login := req.Login
+	if login == "" {
+		login = strings.ToUpper(id[:6])
+	}
+	password := req.Password
+	if password == "" {
+		password = strings.ToLower(id[7:])
+	}
+
+	_, err := h.authSvc.GetUser(login)
+	if err == nil {
+		return fiber.NewError(fiber.StatusConflict, "User already exists")
+	}
+	if !errors.Is(err, auth.ErrUserNotFound) {
+		return fmt.Errorf("can't get user: %w", err)
+	}
+
+	if _, err := h.authSvc.RegisterUser(login, password); err != nil {
+		return fmt.Errorf("can't create user: %w", err)
+	}
+	if err := h.orgsSvc.CreatePersonal(login); err != nil {
+		return err
+	}
+
+	h.Logger.Info("User created", zap.String("user_id", login))
+
+	return c.Status(fiber.StatusCreated).JSON(credentialsResponse{
+		Login:    login,
+		Password: password,
+	})
+}
+
+//	@Summary		Get user
+//	@Description	Returns the user
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			id	path		string						true	"User login"
+//	@Success		200	{object}	userResponse				"User"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"User not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users/{id} [get]
+//
+// Get user
+func (h *AdminController) get(c *fiber.Ctx) error {
+	user, err := h.authSvc.GetUser(c.Params("id"))
+	if err != nil {
+		return h.handleError(err, "can't get user")
+	}
+
+	return c.JSON(userToDTO(user))
+}
+
+//	@Summary		Disable or enable user
+//	@Description	Disabled users can't authorize with credentials, one-time codes, API keys and refresh tokens. Issued access tokens are valid until they expire. Devices of the user are not affected
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string			true	"User login"
+//	@Param			request	body	updateRequest	true	"Update"
+//	@Success		204		"Updated"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"User not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users/{id} [patch]
+//
+// Disable or enable user
+func (h *AdminController) patch(c *fiber.Ctx) error {
+	req := updateRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Params("id")
+	if err := h.authSvc.SetDisabled(userID, *req.Disabled); err != nil {
+		return h.handleError(err, "can't update user")
+	}
+
+	if *req.Disabled {
+		if err := h.tokensSvc.RevokeAll(userID); err != nil {
+			return fmt.Errorf("can't revoke tokens: %w", err)
+		}
+	}
+
+	h.Logger.Info("User updated", zap.String("user_id", userID), zap.Bool("disabled", *req.Disabled))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Reset password
+//	@Description	Sets the new password of the user and revokes the user's refresh tokens. The password is generated if not provided
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User login"
+//	@Param			request	body		passwordRequest				true	"Password"
+//	@Success		200		{object}	credentialsResponse			"New credentials"
+//	@Failure		400		{object}	smsgateway.ErrorResponse	"Invalid request"
+//	@Failure		401		{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	smsgateway.ErrorResponse	"User not found"
+//	@Failure		500		{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users/{id}/password [post]
+//
+// Reset password
+func (h *AdminController) postPassword(c *fiber.Ctx) error {
+	req := passwordRequest{}
+	if err := h.BodyParserValidator(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Params("id")
+	password := req.Password
+	if password == "" {
+		password = strings.ToLower(h.idGen()[7:])
+	}
+
+	if err := h.authSvc.ResetPassword(userID, password); err != nil {
+		return h.handleError(err, "can't reset password")
+	}
+
+	if err := h.tokensSvc.RevokeAll(userID); err != nil {
+		return fmt.Errorf("can't revoke tokens: %w", err)
+	}
+
+	h.Logger.Info("Password reset", zap.String("user_id", userID))
+
+	return c.JSON(credentialsResponse{
+		Login:    userID,
+		Password: password,
+	})
+}
+
+//	@Summary		Delete user
+//	@Description	Deletes the user with devices, messages and all other data
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			id	path	string	true	"User login"
+//	@Success		204	"Deleted"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"User not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users/{id} [delete]
+//
+// Delete user
+func (h *AdminController) delete(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if err := h.authSvc.DeleteUser(userID); err != nil {
+		return h.handleError(err, "can't delete user")
+	}
+
+	h.Logger.Info("User deleted", zap.String("user_id", userID))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+//	@Summary		Get user stats
+//	@Description	Returns the number of stored messages of the user's devices by state. Processed messages are removed after the retention period
+//	@Security		AdminToken
+//	@Tags			Admin
+//	@Produce		json
+//	@Param			id	path		string						true	"User login"
+//	@Success		200	{object}	statsResponse				"Stats"
+//	@Failure		401	{object}	smsgateway.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	smsgateway.ErrorResponse	"User not found"
+//	@Failure		500	{object}	smsgateway.ErrorResponse	"Internal server error"
+//	@Router			/admin/v1/users/{id}/stats [get]
+//
+// Get user stats
+func (h *AdminController) getStats(c *fiber.Ctx) error {
+	user, err := h.authSvc.GetUser(c.Params("id"))
+	if err != nil {
+		return h.handleError(err, "can't get user")
+	}
+
+	counts, err := h.messagesSvc.Count(user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(statsResponse{Messages: counts})
+}
+
+func (h *AdminController) handleError(err error, message string) error {
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func (h *AdminController) Register(router fiber.Router) {
+	router.Get("", h.list)
+	router.Post("", h.post)
+	router.Get("/:id", h.get)
+	router.Patch("/:id", h.patch)
+	router.Delete("/:id", h.delete)
+	router.Post("/:id/password", h.postPassword)
+	router.Get("/:id/stats", h.getStats)
+}
+
+func NewAdminController(params adminControllerParams) *AdminController {
+	idGen, _ := nanoid.Standard(21)
+
+	return &AdminController{
+		Handler: base.Handler{
+			Logger:    params.Logger.Named("users"),
+			Validator: params.Validator,
+		},
+		authSvc:     params.AuthSvc,
+		orgsSvc:     params.OrgsSvc,
+		tokensSvc:   params.TokensSvc,
+		messagesSvc: params.MessagesSvc,
+		idGen:       idGen,
+	}
+}
+
+func userToDTO(user models.User) userResponse {
+	return userResponse{
+		ID:         user.ID,
+		Disabled:   user.DisabledAt != nil,
+		DisabledAt: user.DisabledAt,
+		CreatedAt:  user.CreatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package webhooks
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/handlers/base"
@@ -23,6 +24,24 @@ type thirdPartyControllerParams struct {
 	Logger    *zap.Logger
 }
 
+// webhookRequest accepts events delivered by the server in addition to
+// smsgateway.Webhook events.
+type webhookRequest struct {
+	smsgateway.Webhook
+}
+
+func (r webhookRequest) Validate() error {
+	if !webhooks.IsValidEvent(r.Event) {
+		return fmt.Errorf("%w: invalid event type", smsgateway.ErrValidationFailed)
+	}
+
+	if !strings.HasPrefix(strings.ToLower(r.URL), "https://") {
+		return fmt.Errorf("%w: url must start with https://", smsgateway.ErrValidationFailed)
+	}
+
+	return nil
+}
+
 type ThirdPartyController struct {
 	base.Handler
 
@@ -50,7 +69,7 @@ func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
 }
 
 //	@Summary		Register webhook
-//	@Description	Registers webhook. If webhook with same ID already exists, it will be replaced
+//	@Description	Registers webhook. If webhook with same ID already exists, it will be replaced. Besides device events, `device:offline` and `device:online` events are sent by the server when a device stops or resumes connecting, `message:state` events are sent by the server on message state updates reported by devices and include the message metadata and tags. Events sent by the server are delivered only to public addresses, redirects are not followed
 //	@Security		ApiAuth
 //	@Tags			User, Webhooks
 //	@Accept			json
@@ -64,12 +83,13 @@ func (h *ThirdPartyController) get(user models.User, c *fiber.Ctx) error {
 //
 // Register webhook
 func (h *ThirdPartyController) post(user models.User, c *fiber.Ctx) error {
-	dto := smsgateway.Webhook{}
+	req := webhookRequest{}
 
-	if err := h.BodyParserValidator(c, &dto); err != nil {
+	if err := h.BodyParserValidator(c, &req); err != nil {
 		return err
 	}
 
+	dto := req.Webhook
 	if err := h.webhooksSvc.Replace(user.ID, dto); err != nil {
 		if webhooks.IsValidationError(err) {
 			return fiber.NewError(fiber.StatusBadRequest, err.Error())
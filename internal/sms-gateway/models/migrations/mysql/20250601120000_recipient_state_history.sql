@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `message_recipient_states` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `recipient_id` BIGINT UNSIGNED NOT NULL,
+    `state` enum(
+        'Pending',
+        'Sent',
+        'Processed',
+        'Delivered',
+        'Failed'
+    ) NOT NULL,
+    `error` varchar(256),
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_message_recipient_states_recipient_id_state` (`recipient_id`, `state`),
+    CONSTRAINT `fk_message_recipients_states` FOREIGN KEY (`recipient_id`) REFERENCES `message_recipients`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `message_recipient_states`;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `retry_of_id` BIGINT UNSIGNED NULL,
+    ADD INDEX `idx_messages_retry_of` (`retry_of_id`),
+    ADD CONSTRAINT `fk_messages_retries` FOREIGN KEY (`retry_of_id`) REFERENCES `messages`(`id`) ON DELETE SET NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages` DROP FOREIGN KEY `fk_messages_retries`,
+    DROP INDEX `idx_messages_retry_of`,
+    DROP `retry_of_id`;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `campaigns` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `ext_id` varchar(36) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `message` text NOT NULL,
+    `state` enum('Active', 'Paused', 'Cancelled', 'Completed') NOT NULL DEFAULT 'Active',
+    `rate_per_minute` int unsigned NOT NULL,
+    `device_ids` json NOT NULL,
+    `sim_number` tinyint(1) unsigned NULL,
+    `with_delivery_report` tinyint(1) unsigned NULL,
+    `priority` tinyint NOT NULL DEFAULT 0,
+    `ttl` int unsigned NULL,
+    `last_run_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_campaigns_user_extid` (`user_id`, `ext_id`),
+    INDEX `idx_campaigns_state` (`state`),
+    CONSTRAINT `fk_campaigns_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `campaign_recipients` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `campaign_id` BIGINT UNSIGNED NOT NULL,
+    `phone_number` varchar(128) NOT NULL,
+    `variables` json NULL,
+    `state` enum('Pending', 'Enqueued', 'Failed', 'Skipped') NOT NULL DEFAULT 'Pending',
+    `device_id` char(21) NULL,
+    `message_id` varchar(36) NULL,
+    `error` varchar(256) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_campaign_recipients_campaign_state` (`campaign_id`, `state`),
+    CONSTRAINT `fk_campaigns_recipients` FOREIGN KEY (`campaign_id`) REFERENCES `campaigns`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `campaign_recipients`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `campaigns`;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `contacts` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `ext_id` varchar(36) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `phone_number` varchar(128) NOT NULL,
+    `name` varchar(256) NOT NULL DEFAULT '',
+    `fields` json NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_contacts_user_extid` (`user_id`, `ext_id`),
+    UNIQUE INDEX `unq_contacts_user_phone` (`user_id`, `phone_number`),
+    CONSTRAINT `fk_contacts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `contact_groups` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `ext_id` varchar(36) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_contact_groups_user_extid` (`user_id`, `ext_id`),
+    CONSTRAINT `fk_contact_groups_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `contact_group_members` (
+    `contact_id` BIGINT UNSIGNED NOT NULL,
+    `group_id` BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (`contact_id`, `group_id`),
+    CONSTRAINT `fk_contact_group_members_contact` FOREIGN KEY (`contact_id`) REFERENCES `contacts`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_contact_group_members_group` FOREIGN KEY (`group_id`) REFERENCES `contact_groups`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `contact_group_members`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `contact_groups`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `contacts`;
+-- +goose StatementEnd
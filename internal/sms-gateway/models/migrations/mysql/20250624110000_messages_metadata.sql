@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `metadata` json NULL,
+    ADD `tags` json NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages` DROP `tags`,
+    DROP `metadata`;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `links` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `code` char(10) NOT NULL,
+    `message_id` BIGINT UNSIGNED NOT NULL,
+    `recipient_id` BIGINT UNSIGNED NULL,
+    `url` varchar(2048) NOT NULL,
+    `clicks` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    `last_click_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_links_code` (`code`),
+    INDEX `idx_links_message` (`message_id`),
+    CONSTRAINT `fk_links_message` FOREIGN KEY (`message_id`) REFERENCES `messages`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_links_recipient` FOREIGN KEY (`recipient_id`) REFERENCES `message_recipients`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `links`;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `suppression_list` (
+    `user_id` varchar(32) NOT NULL,
+    `phone_number` varchar(128) NOT NULL,
+    `reason` varchar(256) NOT NULL DEFAULT '',
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`user_id`, `phone_number`),
+    CONSTRAINT `fk_suppression_list_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `autoreply_rules` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT,
+    `ext_id` varchar(36) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `keyword` varchar(64) NOT NULL DEFAULT '',
+    `pattern` varchar(512) NOT NULL DEFAULT '',
+    `reply` text NOT NULL,
+    `action` varchar(16) NOT NULL DEFAULT '',
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `unq_autoreply_rules_user_extid` (`user_id`, `ext_id`),
+    CONSTRAINT `fk_autoreply_rules_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `autoreply_rules`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `suppression_list`;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_sims` (
+    `device_id` char(21) NOT NULL,
+    `slot` tinyint(1) unsigned NOT NULL,
+    `carrier` varchar(64) NOT NULL DEFAULT '',
+    `msisdn` varchar(32) NOT NULL DEFAULT '',
+    `country` char(2) NOT NULL DEFAULT '',
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`device_id`, `slot`),
+    INDEX `idx_device_sims_msisdn` (`msisdn`),
+    CONSTRAINT `fk_device_sims_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_sims`;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `enabled` tinyint(1) unsigned NOT NULL DEFAULT 1,
+    ADD `weight` tinyint unsigned NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `devices` DROP `weight`,
+    DROP `enabled`;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `devices`
+ADD `prev_auth_token` char(21) NULL,
+    ADD `prev_auth_token_valid_until` datetime(3) NULL,
+    ADD UNIQUE INDEX `idx_devices_prev_auth_token` (`prev_auth_token`);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `devices` DROP INDEX `idx_devices_prev_auth_token`,
+    DROP `prev_auth_token_valid_until`,
+    DROP `prev_auth_token`;
+-- +goose StatementEnd
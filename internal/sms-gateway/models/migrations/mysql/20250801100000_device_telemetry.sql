@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_telemetry` (
+    `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+    `device_id` char(21) NOT NULL,
+    `battery_level` tinyint unsigned NULL,
+    `is_charging` tinyint(1) unsigned NULL,
+    `signal_strength` smallint NULL,
+    `network_type` varchar(16) NOT NULL DEFAULT '',
+    `app_version` varchar(32) NOT NULL DEFAULT '',
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    INDEX `idx_device_telemetry_device` (`device_id`),
+    INDEX `idx_device_telemetry_created_at` (`created_at`),
+    CONSTRAINT `fk_device_telemetry_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_telemetry`;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_commands` (
+    `id` char(21) NOT NULL,
+    `device_id` char(21) NOT NULL,
+    `type` varchar(32) NOT NULL,
+    `state` enum('Pending', 'Acknowledged', 'Failed') NOT NULL DEFAULT 'Pending',
+    `error` varchar(256) NULL,
+    `acknowledged_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    INDEX `idx_device_commands_device` (`device_id`),
+    CONSTRAINT `fk_device_commands_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_commands`;
+-- +goose StatementEnd
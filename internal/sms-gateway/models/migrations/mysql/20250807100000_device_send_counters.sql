@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `device_send_counters` (
+    `device_id` char(21) NOT NULL,
+    `sim_number` tinyint(1) unsigned NOT NULL,
+    `period` varchar(16) NOT NULL,
+    `period_start` datetime NOT NULL,
+    `count` int unsigned NOT NULL DEFAULT 0,
+    PRIMARY KEY (`device_id`, `sim_number`, `period`, `period_start`),
+    INDEX `idx_device_send_counters_period_start` (`period_start`),
+    CONSTRAINT `fk_device_send_counters_device` FOREIGN KEY (`device_id`) REFERENCES `devices`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `device_send_counters`;
+-- +goose StatementEnd
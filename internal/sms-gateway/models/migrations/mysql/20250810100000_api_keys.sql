@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `api_keys` (
+    `id` char(21) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `prefix` varchar(12) NOT NULL,
+    `key_hash` char(64) NOT NULL,
+    `scopes` json NOT NULL,
+    `allowed_ips` json NULL,
+    `expires_at` datetime(3) NULL,
+    `last_used_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_api_keys_key_hash` (`key_hash`),
+    INDEX `idx_api_keys_user` (`user_id`),
+    CONSTRAINT `fk_api_keys_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `api_keys`;
+-- +goose StatementEnd
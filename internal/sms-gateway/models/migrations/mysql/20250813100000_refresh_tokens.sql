@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `refresh_tokens` (
+    `id` char(21) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `token_hash` char(64) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `revoked_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_refresh_tokens_token_hash` (`token_hash`),
+    INDEX `idx_refresh_tokens_user` (`user_id`),
+    INDEX `idx_refresh_tokens_expires_at` (`expires_at`),
+    CONSTRAINT `fk_refresh_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `refresh_tokens`;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `organizations` (
+    `id` varchar(32) NOT NULL,
+    `name` varchar(128) NOT NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_organizations_account` FOREIGN KEY (`id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `organization_members` (
+    `organization_id` varchar(32) NOT NULL,
+    `user_id` varchar(32) NOT NULL,
+    `role` enum('owner', 'admin', 'sender', 'viewer') NOT NULL,
+    `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (`organization_id`, `user_id`),
+    INDEX `idx_organization_members_user` (`user_id`),
+    CONSTRAINT `fk_organization_members_organization` FOREIGN KEY (`organization_id`) REFERENCES `organizations`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_organization_members_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO `organizations` (`id`, `name`)
+SELECT `id`, `id` FROM `users`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO `organization_members` (`organization_id`, `user_id`, `role`)
+SELECT `id`, `id`, 'owner' FROM `users`;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `organization_members`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `organizations`;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `users`
+ADD `disabled_at` datetime(3) NULL;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `users` DROP `disabled_at`;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `messages`
+ADD `window_start` char(5) NULL,
+ADD `window_end` char(5) NULL,
+ADD `is_transactional` tinyint(1) unsigned NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+---
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `messages` DROP `is_transactional`,
+DROP `window_end`,
+DROP `window_start`;
+-- +goose StatementEnd
@@ -29,14 +29,24 @@ type User struct {
 	PasswordHash string   `gorm:"not null;type:varchar(72)"`
 	Devices      []Device `gorm:"-,foreignKey:UserID;constraint:OnDelete:CASCADE"`
 
+	// Disabled users can't authorize
+	DisabledAt *time.Time `gorm:"type:datetime(3)"`
+
 	SoftDeletableModel
 }
 
 type Device struct {
-	ID        string  `gorm:"primaryKey;type:char(21)"`
-	Name      *string `gorm:"type:varchar(128)"`
-	AuthToken string  `gorm:"not null;uniqueIndex;type:char(21)"`
-	PushToken *string `gorm:"type:varchar(256)"`
+	ID        string   `gorm:"primaryKey;type:char(21)"`
+	Name      *string  `gorm:"type:varchar(128)"`
+	AuthToken string   `gorm:"not null;uniqueIndex;type:char(21)"`
+	PushToken *string  `gorm:"type:varchar(256)"`
+	Tags      []string `gorm:"type:json;serializer:json"`
+	Enabled   bool     `gorm:"not null;type:tinyint(1) unsigned;default:1"`
+	Weight    uint8    `gorm:"not null;type:tinyint unsigned;default:1"`
+
+	// Previous auth token valid during the grace period after rotation
+	PrevAuthToken           *string    `gorm:"uniqueIndex;type:char(21)"`
+	PrevAuthTokenValidUntil *time.Time `gorm:"type:datetime(3)"`
 
 	LastSeen time.Time `gorm:"not null;autocreatetime:false;default:CURRENT_TIMESTAMP(3)"`
 
@@ -60,6 +70,7 @@ type Message struct {
 	Message            string          `gorm:"not null;type:text"`
 	State              ProcessingState `gorm:"not null;type:enum('Pending','Sent','Processed','Delivered','Failed');default:Pending;index:idx_messages_device_state"`
 	ValidUntil         *time.Time      `gorm:"type:datetime"`
+	ScheduleAt         *time.Time      `gorm:"type:datetime(3)"`
 	SimNumber          *uint8          `gorm:"type:tinyint(1) unsigned"`
 	WithDeliveryReport bool            `gorm:"not null;type:tinyint(1) unsigned"`
 	Priority           int8            `gorm:"not null;type:tinyint;default:0"`
@@ -67,9 +78,22 @@ type Message struct {
 	IsHashed    bool `gorm:"not null;type:tinyint(1) unsigned;default:0"`
 	IsEncrypted bool `gorm:"not null;type:tinyint(1) unsigned;default:0"`
 
+	RetryOfID *uint64 `gorm:"type:BIGINT UNSIGNED;index:idx_messages_retry_of"`
+
+	// sending window overriding the user's one, kept to hold retries the same
+	// way as the original message
+	WindowStart     *string `gorm:"type:char(5)"`
+	WindowEnd       *string `gorm:"type:char(5)"`
+	IsTransactional bool    `gorm:"not null;type:tinyint(1) unsigned;default:0"`
+
+	Metadata map[string]string `gorm:"type:json;serializer:json"`
+	Tags     []string          `gorm:"type:json;serializer:json"`
+
 	Device     Device             `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
 	Recipients []MessageRecipient `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
 	States     []MessageState     `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+	RetryOf    *Message           `gorm:"foreignKey:RetryOfID"`
+	Retries    []Message          `gorm:"foreignKey:RetryOfID;constraint:OnDelete:SET NULL"`
 
 	SoftDeletableModel
 }
@@ -80,6 +104,16 @@ type MessageRecipient struct {
 	PhoneNumber string          `gorm:"uniqueIndex:unq_message_recipients_message_id_phone_number,priority:2;type:varchar(128)"`
 	State       ProcessingState `gorm:"not null;type:enum('Pending','Sent','Processed','Delivered','Failed');default:Pending"`
 	Error       *string         `gorm:"type:varchar(256)"`
+
+	States []MessageRecipientState `gorm:"foreignKey:RecipientID;constraint:OnDelete:CASCADE"`
+}
+
+type MessageRecipientState struct {
+	ID          uint64          `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	RecipientID uint64          `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:unq_message_recipient_states_recipient_id_state,priority:1"`
+	State       ProcessingState `gorm:"not null;type:enum('Pending','Sent','Processed','Delivered','Failed');uniqueIndex:unq_message_recipient_states_recipient_id_state,priority:2"`
+	Error       *string         `gorm:"type:varchar(256)"`
+	UpdatedAt   time.Time       `gorm:"<-:create;not null;autoupdatetime:false"`
 }
 
 type MessageState struct {
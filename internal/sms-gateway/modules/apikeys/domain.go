@@ -0,0 +1,101 @@
+package apikeys
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeMessagesSend Scope = "messages:send"
+	ScopeMessagesRead Scope = "messages:read"
+	ScopeDevices      Scope = "devices"
+	ScopeWebhooks     Scope = "webhooks"
+	ScopeSettings     Scope = "settings"
+)
+
+// keyPrefix tells API keys apart from other bearer tokens.
+const keyPrefix = "sgk_"
+
+type KeyIn struct {
+	// Name
+	Name string `json:"name" validate:"required,max=128" example:"CRM integration"`
+	// Scopes
+	Scopes []Scope `json:"scopes" validate:"required,min=1,dive,oneof=messages:send messages:read devices webhooks settings" example:"messages:send"`
+	// IP addresses or CIDR ranges the key can be used from, any if empty
+	AllowedIPs []string `json:"allowedIps,omitempty" validate:"max=32,dive,cidr|ip" example:"192.0.2.0/24"`
+	// Expiration time, never expires if empty
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+type KeyOut struct {
+	// ID
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Name
+	Name string `json:"name" example:"CRM integration"`
+	// First characters of the key
+	Prefix string `json:"prefix" example:"sgk_Ab3x"`
+	// Scopes
+	Scopes []Scope `json:"scopes" example:"messages:send"`
+	// IP addresses or CIDR ranges the key can be used from
+	AllowedIPs []string `json:"allowedIps,omitempty" example:"192.0.2.0/24"`
+	// Expiration time
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+	// Last used time
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" example:"2020-01-01T00:00:00Z"`
+	// Created at
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+}
+
+type CreatedKeyOut struct {
+	KeyOut
+
+	// The key, it is shown only once
+	Key string `json:"key" example:"sgk_Ab3xPyDmBQZZXYmyxMwED8FzyPyDmBQZZ"`
+}
+
+// Principal is the result of the key authorization.
+type Principal struct {
+	UserID string
+	Scopes []Scope
+}
+
+// HasScope checks if the key grants the scope.
+func (p Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// IsAPIKey reports whether the bearer token looks like an API key.
+func IsAPIKey(token string) bool {
+	return len(token) > len(keyPrefix) && token[:len(keyPrefix)] == keyPrefix
+}
+
+// ipAllowed checks the address against the list of IP addresses and CIDR
+// ranges. Any address is allowed if the list is empty.
+func ipAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, item := range allowed {
+		if prefix, err := netip.ParsePrefix(item); err == nil {
+			if prefix.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if a, err := netip.ParseAddr(item); err == nil && a.Unmap() == addr {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package apikeys
+
+import "testing"
+
+func TestIPAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		ip      string
+		want    bool
+	}{
+		{"empty list", nil, "203.0.113.1", true},
+		{"exact IP", []string{"203.0.113.1"}, "203.0.113.1", true},
+		{"CIDR", []string{"192.0.2.0/24"}, "192.0.2.15", true},
+		{"outside CIDR", []string{"192.0.2.0/24"}, "192.0.3.1", false},
+		{"IPv4-mapped IPv6", []string{"192.0.2.0/24"}, "::ffff:192.0.2.1", true},
+		{"IPv6 CIDR", []string{"2001:db8::/32"}, "2001:db8::1", true},
+		{"invalid IP", []string{"192.0.2.0/24"}, "invalid", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ipAllowed(tt.allowed, tt.ip); got != tt.want {
+				t.Errorf("ipAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
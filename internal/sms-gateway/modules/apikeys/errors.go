@@ -0,0 +1,12 @@
+package apikeys
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("api key not found")
+	ErrInvalidKey   = errors.New("invalid api key")
+	ErrExpired      = errors.New("api key expired")
+	ErrIPNotAllowed = errors.New("ip address not allowed")
+	ErrUserDisabled = errors.New("user disabled")
+	ErrValidation   = errors.New("validation error")
+)
@@ -0,0 +1,39 @@
+package apikeys
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	ID     string `gorm:"primaryKey;type:char(21)"`
+	UserID string `gorm:"not null;type:varchar(32);index:idx_api_keys_user"`
+	Name   string `gorm:"not null;type:varchar(128)"`
+	// First characters of the key to tell keys apart
+	Prefix  string `gorm:"not null;type:varchar(12)"`
+	KeyHash string `gorm:"not null;type:char(64);uniqueIndex"`
+
+	Scopes     []Scope  `gorm:"not null;type:json;serializer:json"`
+	AllowedIPs []string `gorm:"type:json;serializer:json"`
+
+	ExpiresAt  *time.Time `gorm:"type:datetime(3)"`
+	LastUsedAt *time.Time `gorm:"type:datetime(3)"`
+
+	User models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&APIKey{}); err != nil {
+		return fmt.Errorf("api_keys migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package apikeys
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Insert(key *APIKey) error {
+	return r.db.Omit("User").Create(key).Error
+}
+
+func (r *repository) Select(userID string) ([]APIKey, error) {
+	keys := []APIKey{}
+
+	return keys, r.db.
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).
+		Error
+}
+
+// GetByHash returns the key with its user.
+func (r *repository) GetByHash(hash string) (APIKey, error) {
+	key := APIKey{}
+
+	err := r.db.Joins("User").Where("key_hash = ?", hash).Take(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return key, ErrNotFound
+	}
+
+	return key, err
+}
+
+// Touch sets the last used time of the key if it is older than since.
+func (r *repository) Touch(id string, now, since time.Time) error {
+	return r.db.
+		Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, since).
+		UpdateColumn("last_used_at", now).
+		Error
+}
+
+func (r *repository) Delete(userID, id string) error {
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&APIKey{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,160 @@
+package apikeys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/jaevor/go-nanoid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	maxKeysPerUser = 50
+	// the last used time is updated not more often than this
+	touchInterval = time.Minute
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Keys *repository
+
+	IDGen db.IDGen
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	keys *repository
+
+	idGen  db.IDGen
+	keyGen func() string
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) (*Service, error) {
+	keyGen, err := nanoid.Standard(32)
+	if err != nil {
+		return nil, fmt.Errorf("can't create key generator: %w", err)
+	}
+
+	return &Service{
+		keys: params.Keys,
+
+		idGen:  params.IDGen,
+		keyGen: keyGen,
+
+		logger: params.Logger.Named("service"),
+	}, nil
+}
+
+// Create issues a new key. Only a hash of the key is stored, so the key is
+// returned only once.
+func (s *Service) Create(userID string, in KeyIn) (CreatedKeyOut, error) {
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return CreatedKeyOut{}, fmt.Errorf("%w: expiration time must be in the future", ErrValidation)
+	}
+
+	existing, err := s.keys.Select(userID)
+	if err != nil {
+		return CreatedKeyOut{}, fmt.Errorf("can't select keys: %w", err)
+	}
+	if len(existing) >= maxKeysPerUser {
+		return CreatedKeyOut{}, fmt.Errorf("%w: too many keys, max %d", ErrValidation, maxKeysPerUser)
+	}
+
+	plain := keyPrefix + s.keyGen()
+	key := APIKey{
+		ID:         s.idGen(),
+		UserID:     userID,
+		Name:       in.Name,
+		Prefix:     plain[:len(keyPrefix)+4],
+		KeyHash:    hashKey(plain),
+		Scopes:     in.Scopes,
+		AllowedIPs: in.AllowedIPs,
+		ExpiresAt:  in.ExpiresAt,
+	}
+
+	if err := s.keys.Insert(&key); err != nil {
+		return CreatedKeyOut{}, fmt.Errorf("can't insert key: %w", err)
+	}
+
+	key.CreatedAt = time.Now()
+
+	return CreatedKeyOut{KeyOut: keyToDTO(key), Key: plain}, nil
+}
+
+func (s *Service) Select(userID string) ([]KeyOut, error) {
+	keys, err := s.keys.Select(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't select keys: %w", err)
+	}
+
+	result := make([]KeyOut, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, keyToDTO(key))
+	}
+
+	return result, nil
+}
+
+// Delete revokes the key.
+func (s *Service) Delete(userID, id string) error {
+	return s.keys.Delete(userID, id)
+}
+
+// Authorize checks the key and returns its user along with the granted
+// scopes. The last used time of the key is updated.
+func (s *Service) Authorize(plain, ip string) (models.User, Principal, error) {
+	if !IsAPIKey(plain) {
+		return models.User{}, Principal{}, ErrInvalidKey
+	}
+
+	key, err := s.keys.GetByHash(hashKey(plain))
+	if err != nil {
+		return models.User{}, Principal{}, err
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return models.User{}, Principal{}, ErrExpired
+	}
+
+	if !ipAllowed(key.AllowedIPs, ip) {
+		return models.User{}, Principal{}, ErrIPNotAllowed
+	}
+
+	if key.User.DisabledAt != nil {
+		return models.User{}, Principal{}, ErrUserDisabled
+	}
+
+	if err := s.keys.Touch(key.ID, now, now.Add(-touchInterval)); err != nil {
+		s.logger.Error("can't update last used time", zap.String("key_id", key.ID), zap.Error(err))
+	}
+
+	return key.User, Principal{UserID: key.UserID, Scopes: key.Scopes}, nil
+}
+
+func hashKey(plain string) string {
+	hash := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(hash[:])
+}
+
+func keyToDTO(key APIKey) KeyOut {
+	return KeyOut{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
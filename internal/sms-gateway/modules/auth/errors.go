@@ -0,0 +1,8 @@
+package auth
+
+import "errors"
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserDisabled = errors.New("user disabled")
+)
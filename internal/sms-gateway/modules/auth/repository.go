@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"strings"
+	"time"
+
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 	"gorm.io/gorm"
 )
@@ -35,3 +38,46 @@ func (r *repository) Insert(user *models.User) error {
 func (r *repository) UpdatePassword(userID string, passwordHash string) error {
 	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error
 }
+
+// Select returns users with the login starting with the query, sorted by login.
+func (r *repository) Select(query string, limit, offset int) ([]models.User, error) {
+	users := []models.User{}
+
+	db := r.db.Order("id").Limit(limit).Offset(offset)
+	if query != "" {
+		db = db.Where("id LIKE ?", escapeLike(query)+"%")
+	}
+
+	return users, db.Find(&users).Error
+}
+
+func (r *repository) SetDisabled(userID string, disabledAt *time.Time) error {
+	res := r.db.Model(&models.User{}).Where("id = ?", userID).Update("disabled_at", disabledAt)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the user with all the data.
+func (r *repository) Delete(userID string) error {
+	res := r.db.Where("id = ?", userID).Delete(&models.User{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
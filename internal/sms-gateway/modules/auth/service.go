@@ -6,18 +6,25 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
 	"github.com/android-sms-gateway/server/pkg/crypto"
+	"github.com/capcom6/go-helpers/anys"
 	"github.com/capcom6/go-helpers/cache"
 	"github.com/jaevor/go-nanoid"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// activeUserTTL limits how long a user authorized by ID is cached.
+const activeUserTTL = time.Minute
+
 type Config struct {
 	Mode         Mode
 	PrivateToken string
@@ -30,6 +37,7 @@ type Params struct {
 
 	Users      *repository
 	DevicesSvc *devices.Service
+	LockoutSvc *lockout.Service
 
 	Logger *zap.Logger
 }
@@ -42,6 +50,7 @@ type Service struct {
 	usersCache *cache.Cache[models.User]
 
 	devicesSvc *devices.Service
+	lockoutSvc *lockout.Service
 
 	logger *zap.Logger
 
@@ -55,6 +64,7 @@ func New(params Params) *Service {
 		config:     params.Config,
 		users:      params.Users,
 		devicesSvc: params.DevicesSvc,
+		lockoutSvc: params.LockoutSvc,
 		logger:     params.Logger.Named("Service"),
 		idgen:      idgen,
 
@@ -168,6 +178,10 @@ func (s *Service) AuthorizeUser(username, password string) (models.User, error)
 		return models.User{}, err
 	}
 
+	if user.DisabledAt != nil {
+		return models.User{}, ErrUserDisabled
+	}
+
 	if err := s.usersCache.Set(cacheKey, user); err != nil {
 		s.logger.Error("can't cache user", zap.Error(err))
 	}
@@ -175,8 +189,27 @@ func (s *Service) AuthorizeUser(username, password string) (models.User, error)
 	return user, nil
 }
 
-// AuthorizeUserByCode authorizes a user by one-time code.
-func (s *Service) AuthorizeUserByCode(code string) (models.User, error) {
+// AuthorizeUserByCode authorizes a user by one-time code. Failed attempts are
+// tracked per client IP, lockout.LockedError is returned while the client is
+// locked out.
+func (s *Service) AuthorizeUserByCode(code, ip string) (models.User, error) {
+	ipKey := lockout.IPKey(ip)
+	if err := s.lockoutSvc.Check(lockout.KindCode, ipKey); err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.authorizeUserByCode(code)
+	if err != nil {
+		if lockErr := s.lockoutSvc.Fail(lockout.KindCode, ipKey); lockErr != nil {
+			return models.User{}, lockErr
+		}
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *Service) authorizeUserByCode(code string) (models.User, error) {
 	userID, err := s.codesCache.GetAndDelete(code)
 	if err != nil {
 		return models.User{}, err
@@ -187,6 +220,37 @@ func (s *Service) AuthorizeUserByCode(code string) (models.User, error) {
 		return models.User{}, err
 	}
 
+	if user.DisabledAt != nil {
+		return models.User{}, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
+// AuthorizeUserByID returns the user authorized by other means, e.g. an access
+// token, if the user still exists and is not disabled. Users are cached for
+// activeUserTTL, so changes made by other instances apply with a delay.
+func (s *Service) AuthorizeUserByID(userID string) (models.User, error) {
+	cacheKey := "id:" + userID
+
+	user, err := s.usersCache.Get(cacheKey)
+	if err == nil {
+		return user, nil
+	}
+
+	user, err = s.GetUser(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if user.DisabledAt != nil {
+		return models.User{}, ErrUserDisabled
+	}
+
+	if err := s.usersCache.Set(cacheKey, user, cache.WithTTL(activeUserTTL)); err != nil {
+		s.logger.Error("can't cache user", zap.Error(err))
+	}
+
 	return user, nil
 }
 
@@ -219,6 +283,75 @@ func (s *Service) ChangePassword(userID string, currentPassword string, newPassw
 	return nil
 }
 
+// SelectUsers returns users with the login starting with the query.
+func (s *Service) SelectUsers(query string, limit, offset int) ([]models.User, error) {
+	return s.users.Select(query, limit, offset)
+}
+
+// GetUser returns the user by ID.
+func (s *Service) GetUser(userID string) (models.User, error) {
+	user, err := s.users.GetByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, ErrUserNotFound
+	}
+
+	return user, err
+}
+
+// SetDisabled disables or enables the user. Disabled users can't authorize
+// with credentials and one-time codes.
+func (s *Service) SetDisabled(userID string, disabled bool) error {
+	var disabledAt *time.Time
+	if disabled {
+		disabledAt = anys.AsPointer(time.Now())
+	}
+
+	if err := s.users.SetDisabled(userID, disabledAt); err != nil {
+		return err
+	}
+
+	s.dropUsersCache()
+
+	return nil
+}
+
+// ResetPassword sets the new password without checking the current one.
+func (s *Service) ResetPassword(userID, password string) error {
+	hash, err := crypto.MakeBCryptHash(password)
+	if err != nil {
+		return fmt.Errorf("can't hash password: %w", err)
+	}
+
+	if _, err := s.GetUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.users.UpdatePassword(userID, hash); err != nil {
+		return fmt.Errorf("can't update password: %w", err)
+	}
+
+	s.dropUsersCache()
+
+	return nil
+}
+
+// DeleteUser removes the user with devices, messages and other data.
+func (s *Service) DeleteUser(userID string) error {
+	if err := s.users.Delete(userID); err != nil {
+		return err
+	}
+
+	s.dropUsersCache()
+
+	return nil
+}
+
+// dropUsersCache removes all the cached users as the cache is keyed by
+// credentials and users authorized by ID can't be told apart by credentials.
+func (s *Service) dropUsersCache() {
+	s.usersCache.Drain()
+}
+
 // Run starts a ticker that triggers the clean function every hour.
 // It runs indefinitely until the provided context is canceled.
 func (s *Service) Run(ctx context.Context) {
@@ -0,0 +1,15 @@
+package autoreplies
+
+func ruleToDTO(rule Rule) RuleOut {
+	return RuleOut{
+		RuleIn: RuleIn{
+			ID:      rule.ExtID,
+			Keyword: rule.Keyword,
+			Pattern: rule.Pattern,
+			Reply:   rule.Reply,
+			Action:  rule.Action,
+		},
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+}
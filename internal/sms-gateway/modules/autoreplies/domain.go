@@ -0,0 +1,90 @@
+package autoreplies
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionNone Action = ""
+	// ActionSuppress adds the sender to the suppression list
+	ActionSuppress Action = "suppress"
+	// ActionUnsuppress removes the sender from the suppression list
+	ActionUnsuppress Action = "unsuppress"
+)
+
+type RuleIn struct {
+	// ID (if not set - will be generated)
+	ID string `json:"id,omitempty" validate:"omitempty,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Case-insensitive keyword matching the whole message or its first words (conflicts with `pattern`)
+	Keyword string `json:"keyword,omitempty" validate:"required_without=Pattern,excluded_with=Pattern,max=64" example:"STOP"`
+	// Regular expression matching the message (conflicts with `keyword`)
+	Pattern string `json:"pattern,omitempty" validate:"max=512" example:"(?i)^\\s*unsubscribe"`
+	// Reply text, no reply is sent if empty
+	Reply string `json:"reply,omitempty" validate:"required_without=Action,max=1600" example:"You are unsubscribed"`
+	// Suppression list action applied to the sender
+	Action Action `json:"action,omitempty" validate:"omitempty,oneof=suppress unsuppress" example:"suppress"`
+}
+
+func (r RuleIn) Validate() error {
+	if r.Pattern == "" {
+		return nil
+	}
+
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return errors.New("invalid pattern: " + err.Error())
+	}
+
+	return nil
+}
+
+type RuleOut struct {
+	RuleIn
+
+	// Created at
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+	// Updated at
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+// IncomingMessage is a message received by a device
+type IncomingMessage struct {
+	// ID of the message on the device, repeated reports are ignored
+	ID          string
+	PhoneNumber string
+	Message     string
+	SimNumber   *uint8
+	ReceivedAt  time.Time
+}
+
+// IncomingResult is the result of rules evaluation
+type IncomingResult struct {
+	// ID of the matched rule
+	RuleID string
+	// ID of the reply message, empty if the sender was replied to recently
+	ReplyID string
+}
+
+// matches reports whether the rule matches the message text.
+func (r Rule) matches(text string) bool {
+	text = strings.TrimSpace(text)
+
+	if r.Keyword != "" {
+		if len(text) < len(r.Keyword) || !strings.EqualFold(text[:len(r.Keyword)], r.Keyword) {
+			return false
+		}
+		// the keyword must be followed by a word boundary
+		return len(text) == len(r.Keyword) || strings.ContainsAny(text[len(r.Keyword):len(r.Keyword)+1], " \t\r\n.,!?")
+	}
+
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(text)
+}
@@ -0,0 +1,37 @@
+package autoreplies
+
+import "testing"
+
+func TestRule_matches(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		text string
+		want bool
+	}{
+		{name: "keyword", rule: Rule{Keyword: "STOP"}, text: " stop\n", want: true},
+		{name: "keyword first word", rule: Rule{Keyword: "STOP"}, text: "Stop, please", want: true},
+		{name: "keyword prefix", rule: Rule{Keyword: "STOP"}, text: "STOPPED", want: false},
+		{name: "keyword in text", rule: Rule{Keyword: "STOP"}, text: "please stop", want: false},
+		{name: "phrase", rule: Rule{Keyword: "opt out"}, text: "OPT OUT", want: true},
+		{name: "pattern", rule: Rule{Pattern: `(?i)unsubscribe`}, text: "Please UNSUBSCRIBE me", want: true},
+		{name: "pattern mismatch", rule: Rule{Pattern: `^\d+$`}, text: "12a", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.text); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleIn_Validate(t *testing.T) {
+	if err := (RuleIn{Pattern: "("}).Validate(); err == nil {
+		t.Error("Validate() of invalid pattern error = nil")
+	}
+	if err := (RuleIn{Keyword: "STOP"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
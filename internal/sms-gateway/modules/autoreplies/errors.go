@@ -0,0 +1,12 @@
+package autoreplies
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound      = gorm.ErrRecordNotFound
+	ErrAlreadyExists = errors.New("rule with such ID already exists")
+)
@@ -0,0 +1,56 @@
+package autoreplies
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type Rule struct {
+	ID      uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	ExtID   string `gorm:"not null;type:varchar(36);uniqueIndex:unq_autoreply_rules_user_extid,priority:2"`
+	UserID  string `gorm:"not null;type:varchar(32);uniqueIndex:unq_autoreply_rules_user_extid,priority:1"`
+	Keyword string `gorm:"not null;type:varchar(64);default:''"`
+	Pattern string `gorm:"not null;type:varchar(512);default:''"`
+	Reply   string `gorm:"not null;type:text"`
+	Action  Action `gorm:"not null;type:varchar(16);default:''"`
+
+	User models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Rule) TableName() string {
+	return "autoreply_rules"
+}
+
+// Incoming is a message reported by a device, kept to ignore repeated reports
+// and to limit replies to the sender.
+type Incoming struct {
+	ID          uint64    `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	DeviceID    string    `gorm:"not null;type:char(21);uniqueIndex:unq_autoreply_incoming_device_message,priority:1"`
+	MessageID   string    `gorm:"not null;type:varchar(36);uniqueIndex:unq_autoreply_incoming_device_message,priority:2"`
+	UserID      string    `gorm:"not null;type:varchar(32);index:idx_autoreply_incoming_sender,priority:1"`
+	PhoneNumber string    `gorm:"not null;type:varchar(128);index:idx_autoreply_incoming_sender,priority:2"`
+	RuleID      string    `gorm:"not null;type:varchar(36);default:''"`
+	ReplyID     string    `gorm:"not null;type:varchar(36);default:''"`
+	CreatedAt   time.Time `gorm:"->;not null;autocreatetime:false;default:CURRENT_TIMESTAMP(3);index:idx_autoreply_incoming_sender,priority:3"`
+
+	Device models.Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func (Incoming) TableName() string {
+	return "autoreply_incoming"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Rule{}); err != nil {
+		return fmt.Errorf("autoreply_rules migration failed: %w", err)
+	}
+	if err := db.AutoMigrate(&Incoming{}); err != nil {
+		return fmt.Errorf("autoreply_incoming migration failed: %w", err)
+	}
+	return nil
+}
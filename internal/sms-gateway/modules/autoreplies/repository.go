@@ -0,0 +1,95 @@
+package autoreplies
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// Select returns the user's rules in the order of evaluation.
+func (r *repository) Select(userID string) ([]Rule, error) {
+	rules := []Rule{}
+
+	return rules, r.db.Where("user_id = ?", userID).Order("id").Find(&rules).Error
+}
+
+func (r *repository) Get(userID, id string) (Rule, error) {
+	rule := Rule{}
+
+	return rule, r.db.Where("user_id = ? AND ext_id = ?", userID, id).Take(&rule).Error
+}
+
+func (r *repository) Insert(rule *Rule) error {
+	return translateError(r.db.Omit("User").Create(rule).Error)
+}
+
+func (r *repository) Update(rule *Rule) error {
+	return r.db.Model(rule).Select("Keyword", "Pattern", "Reply", "Action").Updates(rule).Error
+}
+
+func (r *repository) Delete(id uint64) error {
+	return r.db.Delete(&Rule{}, id).Error
+}
+
+// InsertIncoming stores the reported message, ErrAlreadyExists is returned if
+// the device has already reported it.
+func (r *repository) InsertIncoming(incoming *Incoming) error {
+	return translateError(r.db.Omit("Device").Create(incoming).Error)
+}
+
+func (r *repository) GetIncoming(deviceID, messageID string) (Incoming, error) {
+	incoming := Incoming{}
+
+	return incoming, r.db.Where("device_id = ? AND message_id = ?", deviceID, messageID).Take(&incoming).Error
+}
+
+func (r *repository) UpdateIncoming(incoming *Incoming) error {
+	return r.db.Model(incoming).Select("RuleID", "ReplyID").Updates(incoming).Error
+}
+
+func (r *repository) DeleteIncoming(id uint64) error {
+	return r.db.Delete(&Incoming{}, id).Error
+}
+
+// RepliedSince reports whether a reply has been sent to the user's sender
+// since the given time.
+func (r *repository) RepliedSince(userID, phoneNumber string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&Incoming{}).
+		Where("user_id = ? AND phone_number = ? AND created_at >= ? AND reply_id <> ''", userID, phoneNumber, since).
+		Limit(1).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (r *repository) removeIncomingOlder(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("created_at < ?", until).
+		Delete(&Incoming{})
+
+	return res.RowsAffected, res.Error
+}
+
+func translateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return ErrAlreadyExists
+	}
+
+	return err
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,266 @@
+package autoreplies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/suppressions"
+	"github.com/capcom6/go-helpers/slices"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// replyCooldown is the minimal interval between replies to the same sender
+	replyCooldown = 10 * time.Minute
+	// incomingLifetime is how long reported messages are kept to detect repeats
+	incomingLifetime = 7 * 24 * time.Hour
+)
+
+type ServiceParams struct {
+	fx.In
+
+	IDGen db.IDGen
+
+	Rules *repository
+
+	MessagesSvc     *messages.Service
+	SuppressionsSvc *suppressions.Service
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	idgen db.IDGen
+
+	rules *repository
+
+	messagesSvc     *messages.Service
+	suppressionsSvc *suppressions.Service
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		idgen:           params.IDGen,
+		rules:           params.Rules,
+		messagesSvc:     params.MessagesSvc,
+		suppressionsSvc: params.SuppressionsSvc,
+		logger:          params.Logger.Named("service"),
+	}
+}
+
+func (s *Service) Select(userID string) ([]RuleOut, error) {
+	rules, err := s.rules.Select(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't select rules: %w", err)
+	}
+
+	return slices.Map(rules, ruleToDTO), nil
+}
+
+func (s *Service) Get(userID, id string) (RuleOut, error) {
+	rule, err := s.rules.Get(userID, id)
+	if err != nil {
+		return RuleOut{}, err
+	}
+
+	return ruleToDTO(rule), nil
+}
+
+func (s *Service) Create(userID string, rule RuleIn) (RuleOut, error) {
+	if rule.ID == "" {
+		rule.ID = s.idgen()
+	}
+
+	model := Rule{
+		ExtID:   rule.ID,
+		UserID:  userID,
+		Keyword: rule.Keyword,
+		Pattern: rule.Pattern,
+		Reply:   rule.Reply,
+		Action:  rule.Action,
+	}
+
+	if err := s.rules.Insert(&model); err != nil {
+		if errors.Is(err, ErrAlreadyExists) {
+			return RuleOut{}, err
+		}
+		return RuleOut{}, fmt.Errorf("can't create rule: %w", err)
+	}
+
+	return s.Get(userID, model.ExtID)
+}
+
+func (s *Service) Update(userID, id string, rule RuleIn) (RuleOut, error) {
+	model, err := s.rules.Get(userID, id)
+	if err != nil {
+		return RuleOut{}, err
+	}
+
+	model.Keyword = rule.Keyword
+	model.Pattern = rule.Pattern
+	model.Reply = rule.Reply
+	model.Action = rule.Action
+
+	if err := s.rules.Update(&model); err != nil {
+		return RuleOut{}, fmt.Errorf("can't update rule: %w", err)
+	}
+
+	return s.Get(userID, id)
+}
+
+func (s *Service) Delete(userID, id string) error {
+	rule, err := s.rules.Get(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.rules.Delete(rule.ID)
+}
+
+// HandleIncoming evaluates the user's rules against the message received by
+// the device. The first matching rule is applied: the reply is enqueued on the
+// same device and the suppression list action is applied to the sender.
+// Messages already reported by the device return the result of the first
+// report. The sender is replied to at most once per cooldown.
+func (s *Service) HandleIncoming(device models.Device, message IncomingMessage) (IncomingResult, error) {
+	incoming := Incoming{
+		DeviceID:    device.ID,
+		MessageID:   message.ID,
+		UserID:      device.UserID,
+		PhoneNumber: message.PhoneNumber,
+	}
+	if err := s.rules.InsertIncoming(&incoming); err != nil {
+		if !errors.Is(err, ErrAlreadyExists) {
+			return IncomingResult{}, fmt.Errorf("can't store incoming message: %w", err)
+		}
+
+		existing, err := s.rules.GetIncoming(device.ID, message.ID)
+		if err != nil {
+			return IncomingResult{}, fmt.Errorf("can't get incoming message: %w", err)
+		}
+
+		return IncomingResult{RuleID: existing.RuleID, ReplyID: existing.ReplyID}, nil
+	}
+
+	result, err := s.handleIncoming(device, message)
+	if err != nil {
+		// let the device retry
+		if err := s.rules.DeleteIncoming(incoming.ID); err != nil {
+			s.logger.Error("Can't delete incoming message", zap.Uint64("id", incoming.ID), zap.Error(err))
+		}
+		return result, err
+	}
+
+	incoming.RuleID = result.RuleID
+	incoming.ReplyID = result.ReplyID
+	if err := s.rules.UpdateIncoming(&incoming); err != nil {
+		return result, fmt.Errorf("can't update incoming message: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) handleIncoming(device models.Device, message IncomingMessage) (IncomingResult, error) {
+	rules, err := s.rules.Select(device.UserID)
+	if err != nil {
+		return IncomingResult{}, fmt.Errorf("can't select rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if !rule.matches(message.Message) {
+			continue
+		}
+
+		result := IncomingResult{RuleID: rule.ExtID}
+
+		if rule.Reply != "" {
+			replyID, err := s.reply(device, message, rule)
+			if err != nil {
+				return result, err
+			}
+			result.ReplyID = replyID
+		}
+
+		if err := s.applyAction(device.UserID, message.PhoneNumber, rule); err != nil {
+			return result, err
+		}
+
+		return result, nil
+	}
+
+	return IncomingResult{}, nil
+}
+
+// reply enqueues the rule reply to the sender unless the sender has been
+// replied to during the cooldown. The ID of the reply is returned.
+func (s *Service) reply(device models.Device, message IncomingMessage, rule Rule) (string, error) {
+	replied, err := s.rules.RepliedSince(device.UserID, message.PhoneNumber, time.Now().Add(-replyCooldown))
+	if err != nil {
+		return "", fmt.Errorf("can't check replies: %w", err)
+	}
+	if replied {
+		s.logger.Info("Sender was replied to recently", zap.String("rule_id", rule.ExtID))
+		return "", nil
+	}
+
+	state, err := s.messagesSvc.Enqueue(
+		device,
+		messages.MessageIn{
+			Message:         rule.Reply,
+			PhoneNumbers:    []string{message.PhoneNumber},
+			SimNumber:       message.SimNumber,
+			IsTransactional: true,
+			Tags:            []string{"autoreply"},
+		},
+		// the sender is expecting the reply, e.g. to STOP
+		messages.EnqueueOptions{SkipSuppressionList: true},
+	)
+
+	var errValidation messages.ErrValidation
+	switch {
+	case errors.As(err, &errValidation):
+		// e.g. alphanumeric senders can't be replied to
+		s.logger.Warn("Can't reply", zap.String("rule_id", rule.ExtID), zap.Error(err))
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("can't enqueue reply: %w", err)
+	}
+
+	return state.ID, nil
+}
+
+func (s *Service) Clean(ctx context.Context) error {
+	n, err := s.rules.removeIncomingOlder(ctx, time.Now().Add(-incomingLifetime))
+
+	s.logger.Info("Cleaned incoming messages", zap.Int64("count", n))
+	return err
+}
+
+func (s *Service) applyAction(userID, phoneNumber string, rule Rule) error {
+	switch rule.Action {
+	case ActionSuppress:
+		reason := rule.Keyword
+		if reason == "" {
+			reason = "autoreply"
+		}
+
+		_, err := s.suppressionsSvc.Add(userID, suppressions.EntryIn{PhoneNumber: phoneNumber, Reason: reason})
+		if err != nil {
+			return fmt.Errorf("can't suppress sender: %w", err)
+		}
+	case ActionUnsuppress:
+		if err := s.suppressionsSvc.Remove(userID, phoneNumber); err != nil && !errors.Is(err, suppressions.ErrNotFound) {
+			return fmt.Errorf("can't unsuppress sender: %w", err)
+		}
+	}
+
+	return nil
+}
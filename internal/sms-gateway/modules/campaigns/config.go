@@ -0,0 +1,10 @@
+package campaigns
+
+import "time"
+
+type Config struct {
+	// Interval between runs of the enqueueing task
+	Interval time.Duration
+	// Maximum number of messages enqueued per campaign in a single run
+	BatchLimit int
+}
@@ -0,0 +1,14 @@
+package campaigns
+
+func campaignToDTO(model Campaign) CampaignOut {
+	return CampaignOut{
+		ID:            model.ExtID,
+		Name:          model.Name,
+		Message:       model.Message,
+		State:         model.State,
+		RatePerMinute: model.RatePerMinute,
+		DeviceIDs:     model.DeviceIDs,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package campaigns
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var phoneColumns = map[string]struct{}{
+	"phone":        {},
+	"phonenumber":  {},
+	"phone_number": {},
+}
+
+// ParseRecipientsCSV reads recipients from CSV with a header row. The phone
+// number is taken from the `phone`, `phoneNumber` or `phone_number` column,
+// all other columns are used as template variables.
+func ParseRecipientsCSV(r io.Reader) ([]RecipientIn, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("empty csv")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read header: %w", err)
+	}
+
+	phoneIndex := -1
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+		if _, ok := phoneColumns[strings.ToLower(column)]; ok && phoneIndex == -1 {
+			phoneIndex = i
+		}
+	}
+	if phoneIndex == -1 {
+		return nil, fmt.Errorf("phone column not found")
+	}
+
+	recipients := []RecipientIn{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read row %d: %w", len(recipients)+2, err)
+		}
+
+		recipient := RecipientIn{
+			PhoneNumber: strings.TrimSpace(record[phoneIndex]),
+			Variables:   make(map[string]string, len(record)-1),
+		}
+		if recipient.PhoneNumber == "" {
+			return nil, fmt.Errorf("empty phone number in row %d", len(recipients)+2)
+		}
+
+		for i, value := range record {
+			if i == phoneIndex || header[i] == "" {
+				continue
+			}
+			recipient.Variables[header[i]] = value
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,79 @@
+package campaigns_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/campaigns"
+)
+
+func TestParseRecipientsCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []campaigns.RecipientIn
+		expectError bool
+	}{
+		{
+			name:  "Phone only",
+			input: "phone\n+79990001234\n+79990001235\n",
+			expected: []campaigns.RecipientIn{
+				{PhoneNumber: "+79990001234", Variables: map[string]string{}},
+				{PhoneNumber: "+79990001235", Variables: map[string]string{}},
+			},
+		},
+		{
+			name:  "With variables",
+			input: "name,phoneNumber,code\nJohn,+79990001234,1234\n",
+			expected: []campaigns.RecipientIn{
+				{PhoneNumber: "+79990001234", Variables: map[string]string{"name": "John", "code": "1234"}},
+			},
+		},
+		{
+			name:     "Header only",
+			input:    "phone_number\n",
+			expected: []campaigns.RecipientIn{},
+		},
+		{
+			name:        "Empty input",
+			input:       "",
+			expectError: true,
+		},
+		{
+			name:        "Missing phone column",
+			input:       "name\nJohn\n",
+			expectError: true,
+		},
+		{
+			name:        "Empty phone number",
+			input:       "phone,name\n,John\n",
+			expectError: true,
+		},
+		{
+			name:        "Wrong number of fields",
+			input:       "phone,name\n+79990001234\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := campaigns.ParseRecipientsCSV(strings.NewReader(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ParseRecipientsCSV() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package campaigns
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// CampaignIn is a request to create a campaign
+type CampaignIn struct {
+	ID            string
+	Name          string
+	Message       string
+	Recipients    []RecipientIn
+	GroupIDs      []string
+	RatePerMinute uint32
+	DeviceIDs     []string
+
+	SimNumber          *uint8
+	WithDeliveryReport *bool
+	Priority           smsgateway.MessagePriority
+	TTL                *uint64
+}
+
+type RecipientIn struct {
+	// Phone number
+	PhoneNumber string `json:"phoneNumber" validate:"required,min=1,max=128" example:"79990001234"`
+	// Template variables
+	Variables map[string]string `json:"variables,omitempty" example:"name:John"`
+}
+
+// CampaignOut is a campaign with its progress
+type CampaignOut struct {
+	// Campaign ID
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Name
+	Name string `json:"name" example:"Black Friday"`
+	// Message or template
+	Message string `json:"message" example:"Hello {{name}}!"`
+	// State
+	State State `json:"state" example:"Active"`
+	// Messages per minute
+	RatePerMinute uint32 `json:"ratePerMinute" example:"60"`
+	// Devices to spread messages across, all user's devices if empty
+	DeviceIDs []string `json:"deviceIds"`
+
+	// Progress, only for a single campaign
+	Progress *Progress `json:"progress,omitempty"`
+
+	// Created at (read only)
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+	// Updated at (read only)
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+// Progress is an aggregate state of campaign recipients and messages
+type Progress struct {
+	// Total number of recipients
+	Total int64 `json:"total" example:"1000"`
+	// Number of recipients by campaign state
+	Recipients map[RecipientState]int64 `json:"recipients"`
+	// Number of enqueued messages by message state
+	Messages map[smsgateway.ProcessingState]int64 `json:"messages"`
+}
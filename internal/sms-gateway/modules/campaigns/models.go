@@ -0,0 +1,75 @@
+package campaigns
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type State string
+
+const (
+	StateActive    State = "Active"
+	StatePaused    State = "Paused"
+	StateCancelled State = "Cancelled"
+	StateCompleted State = "Completed"
+)
+
+type RecipientState string
+
+const (
+	RecipientStatePending  RecipientState = "Pending"
+	RecipientStateEnqueued RecipientState = "Enqueued"
+	RecipientStateFailed   RecipientState = "Failed"
+	RecipientStateSkipped  RecipientState = "Skipped"
+)
+
+type Campaign struct {
+	ID     uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	ExtID  string `gorm:"not null;type:varchar(36);uniqueIndex:unq_campaigns_user_extid,priority:2"`
+	UserID string `gorm:"<-:create;not null;type:varchar(32);uniqueIndex:unq_campaigns_user_extid,priority:1"`
+
+	Name    string `gorm:"not null;type:varchar(128)"`
+	Message string `gorm:"not null;type:text"`
+	State   State  `gorm:"not null;type:enum('Active','Paused','Cancelled','Completed');default:Active;index:idx_campaigns_state"`
+
+	RatePerMinute uint32   `gorm:"not null;type:int unsigned"`
+	DeviceIDs     []string `gorm:"not null;type:json;serializer:json"`
+
+	SimNumber          *uint8  `gorm:"type:tinyint(1) unsigned"`
+	WithDeliveryReport *bool   `gorm:"type:tinyint(1) unsigned"`
+	Priority           int8    `gorm:"not null;type:tinyint;default:0"`
+	TTL                *uint64 `gorm:"type:int unsigned"`
+
+	LastRunAt time.Time `gorm:"not null;type:datetime(3)"`
+
+	User       models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Recipients []Recipient `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+type Recipient struct {
+	ID          uint64            `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	CampaignID  uint64            `gorm:"not null;type:BIGINT UNSIGNED;index:idx_campaign_recipients_campaign_state,priority:1"`
+	PhoneNumber string            `gorm:"not null;type:varchar(128)"`
+	Variables   map[string]string `gorm:"type:json;serializer:json"`
+
+	State     RecipientState `gorm:"not null;type:enum('Pending','Enqueued','Failed','Skipped');default:Pending;index:idx_campaign_recipients_campaign_state,priority:2"`
+	DeviceID  *string        `gorm:"type:char(21)"`
+	MessageID *string        `gorm:"type:varchar(36)"`
+	Error     *string        `gorm:"type:varchar(256)"`
+}
+
+func (Recipient) TableName() string {
+	return "campaign_recipients"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Campaign{}, &Recipient{}); err != nil {
+		return fmt.Errorf("campaigns migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package campaigns
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"gorm.io/gorm"
+)
+
+const processingLockName = "8d0d5d7e-5f7c-4d1c-9a43-7c3a0f3e6b21"
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Select(filters ...SelectFilter) ([]Campaign, error) {
+	campaigns := []Campaign{}
+	if err := newFilter(filters...).apply(r.db).Order("id DESC").Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+func (r *repository) Get(filters ...SelectFilter) (Campaign, error) {
+	campaign := Campaign{}
+
+	return campaign, newFilter(filters...).apply(r.db).Take(&campaign).Error
+}
+
+// Insert creates the campaign together with its recipients.
+func (r *repository) Insert(campaign *Campaign) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		recipients := campaign.Recipients
+		campaign.Recipients = nil
+
+		if err := tx.Omit("User").Create(campaign).Error; err != nil {
+			return err
+		}
+
+		for i := range recipients {
+			recipients[i].CampaignID = campaign.ID
+		}
+
+		if err := tx.CreateInBatches(recipients, 1000).Error; err != nil {
+			return err
+		}
+
+		campaign.Recipients = recipients
+		return nil
+	})
+}
+
+// UpdateState changes the campaign state if it is currently in one of the
+// given states. It returns ErrInvalidState if nothing was updated.
+func (r *repository) UpdateState(id uint64, state State, from ...State) error {
+	updates := map[string]any{"state": state}
+	if state == StateActive {
+		updates["last_run_at"] = time.Now()
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Campaign{}).
+			Where("id = ? AND state IN ?", id, from).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidState
+		}
+
+		if state != StateCancelled {
+			return nil
+		}
+
+		return tx.Model(&Recipient{}).
+			Where("campaign_id = ? AND state = ?", id, RecipientStatePending).
+			Update("state", RecipientStateSkipped).
+			Error
+	})
+}
+
+func (r *repository) UpdateLastRunAt(id uint64, lastRunAt time.Time) error {
+	return r.db.Model(&Campaign{}).Where("id = ?", id).Update("last_run_at", lastRunAt).Error
+}
+
+// SelectPendingRecipients returns up to limit recipients of the campaign that
+// have not been enqueued yet.
+func (r *repository) SelectPendingRecipients(campaignID uint64, limit int) ([]Recipient, error) {
+	recipients := []Recipient{}
+
+	return recipients, r.db.
+		Where("campaign_id = ? AND state = ?", campaignID, RecipientStatePending).
+		Order("id").
+		Limit(limit).
+		Find(&recipients).
+		Error
+}
+
+func (r *repository) UpdateRecipient(recipient *Recipient) error {
+	return r.db.Model(recipient).
+		Select("State", "DeviceID", "MessageID", "Error").
+		Updates(recipient).
+		Error
+}
+
+// Progress counts campaign recipients by their state and enqueued messages by
+// the state of the message.
+func (r *repository) Progress(campaignID uint64) (Progress, error) {
+	rows := []struct {
+		State        RecipientState
+		MessageState *smsgateway.ProcessingState
+		Count        int64
+	}{}
+
+	err := r.db.
+		Table("campaign_recipients r").
+		Select("r.state AS state, m.state AS message_state, COUNT(*) AS count").
+		Joins("LEFT JOIN messages m ON r.state = ? AND m.device_id = r.device_id AND m.ext_id = r.message_id", RecipientStateEnqueued).
+		Where("r.campaign_id = ?", campaignID).
+		Group("r.state, m.state").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return Progress{}, err
+	}
+
+	progress := Progress{
+		Recipients: map[RecipientState]int64{},
+		Messages:   map[smsgateway.ProcessingState]int64{},
+	}
+	for _, row := range rows {
+		progress.Total += row.Count
+		progress.Recipients[row.State] += row.Count
+		if row.MessageState != nil {
+			progress.Messages[*row.MessageState] += row.Count
+		}
+	}
+
+	return progress, nil
+}
+
+// WithLock runs fn while holding the campaigns processing lock, so only one
+// instance processes campaigns at a time. If the lock is held by another
+// instance, fn is not called.
+func (r *repository) WithLock(fn func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		hasLock := sql.NullBool{}
+		lockRow := tx.Raw("SELECT GET_LOCK(?, 0)", processingLockName).Row()
+		if err := lockRow.Scan(&hasLock); err != nil {
+			return err
+		}
+
+		if !hasLock.Valid || !hasLock.Bool {
+			return errLocked
+		}
+		defer tx.Exec("SELECT RELEASE_LOCK(?)", processingLockName)
+
+		return fn()
+	})
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,54 @@
+package campaigns
+
+import "gorm.io/gorm"
+
+type SelectFilter func(*selectFilter)
+
+func WithExtID(extID string) SelectFilter {
+	return func(f *selectFilter) {
+		f.extID = &extID
+	}
+}
+
+func WithUserID(userID string) SelectFilter {
+	return func(f *selectFilter) {
+		f.userID = &userID
+	}
+}
+
+func WithState(state State) SelectFilter {
+	return func(f *selectFilter) {
+		f.state = &state
+	}
+}
+
+type selectFilter struct {
+	extID  *string
+	userID *string
+	state  *State
+}
+
+func newFilter(filters ...SelectFilter) *selectFilter {
+	f := &selectFilter{}
+	f.merge(filters...)
+	return f
+}
+
+func (f *selectFilter) merge(filters ...SelectFilter) {
+	for _, filter := range filters {
+		filter(f)
+	}
+}
+
+func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.extID != nil {
+		query = query.Where("ext_id = ?", *f.extID)
+	}
+	if f.userID != nil {
+		query = query.Where("user_id = ?", *f.userID)
+	}
+	if f.state != nil {
+		query = query.Where("state = ?", *f.state)
+	}
+	return query
+}
@@ -0,0 +1,360 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
+	"github.com/android-sms-gateway/server/pkg/templates"
+	"github.com/capcom6/go-helpers/anys"
+	"github.com/capcom6/go-helpers/slices"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const maxErrorLength = 256
+
+type ServiceParams struct {
+	fx.In
+
+	Config Config
+
+	IDGen db.IDGen
+
+	Campaigns *repository
+
+	MessagesSvc *messages.Service
+	DevicesSvc  *devices.Service
+	ContactsSvc *contacts.Service
+	QuotasSvc   *quotas.Service
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	config Config
+
+	idgen db.IDGen
+
+	campaigns *repository
+
+	messagesSvc *messages.Service
+	devicesSvc  *devices.Service
+	contactsSvc *contacts.Service
+	quotasSvc   *quotas.Service
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	if params.Config.Interval <= 0 {
+		params.Config.Interval = 5 * time.Second
+	}
+	if params.Config.BatchLimit <= 0 {
+		params.Config.BatchLimit = 100
+	}
+
+	return &Service{
+		config:      params.Config,
+		idgen:       params.IDGen,
+		campaigns:   params.Campaigns,
+		messagesSvc: params.MessagesSvc,
+		devicesSvc:  params.DevicesSvc,
+		contactsSvc: params.ContactsSvc,
+		quotasSvc:   params.QuotasSvc,
+		logger:      params.Logger.Named("service"),
+	}
+}
+
+// Create validates and stores a new campaign. Contacts of the groups are
+// added to the recipients at the time of creation. Messages are enqueued later
+// by the background task.
+func (s *Service) Create(userID string, campaign CampaignIn) (CampaignOut, error) {
+	if campaign.RatePerMinute == 0 {
+		return CampaignOut{}, newValidationError("ratePerMinute", "0", errors.New("positive value required"))
+	}
+
+	if len(campaign.GroupIDs) > 0 {
+		items, err := s.contactsSvc.Expand(userID, campaign.GroupIDs)
+		if err != nil {
+			var errValidation contacts.ValidationError
+			if errors.As(err, &errValidation) {
+				return CampaignOut{}, newValidationError(errValidation.Field, errValidation.Value, errValidation.Err)
+			}
+			return CampaignOut{}, fmt.Errorf("can't expand groups: %w", err)
+		}
+
+		for _, contact := range items {
+			campaign.Recipients = append(campaign.Recipients, RecipientIn{
+				PhoneNumber: contact.PhoneNumber,
+				Variables:   contact.Variables(),
+			})
+		}
+	}
+
+	if len(campaign.Recipients) == 0 {
+		return CampaignOut{}, newValidationError("recipients", "", errors.New("at least one recipient required"))
+	}
+
+	for _, deviceID := range campaign.DeviceIDs {
+		ok, err := s.devicesSvc.Exists(userID, devices.WithID(deviceID))
+		if err != nil {
+			return CampaignOut{}, fmt.Errorf("can't select devices: %w", err)
+		}
+		if !ok {
+			return CampaignOut{}, newValidationError("deviceIds", deviceID, devices.ErrNotFound)
+		}
+	}
+
+	if campaign.ID == "" {
+		campaign.ID = s.idgen()
+	}
+
+	model := Campaign{
+		ExtID:   campaign.ID,
+		UserID:  userID,
+		Name:    campaign.Name,
+		Message: campaign.Message,
+		State:   StateActive,
+
+		RatePerMinute: campaign.RatePerMinute,
+		DeviceIDs:     campaign.DeviceIDs,
+
+		SimNumber:          campaign.SimNumber,
+		WithDeliveryReport: campaign.WithDeliveryReport,
+		Priority:           int8(campaign.Priority),
+		TTL:                campaign.TTL,
+
+		LastRunAt: time.Now(),
+
+		Recipients: slices.Map(campaign.Recipients, func(r RecipientIn) Recipient {
+			return Recipient{
+				PhoneNumber: r.PhoneNumber,
+				Variables:   r.Variables,
+				State:       RecipientStatePending,
+			}
+		}),
+	}
+	if model.DeviceIDs == nil {
+		model.DeviceIDs = []string{}
+	}
+
+	if err := s.campaigns.Insert(&model); err != nil {
+		return CampaignOut{}, fmt.Errorf("can't create campaign: %w", err)
+	}
+
+	return s.Get(userID, model.ExtID)
+}
+
+// Select returns campaigns of the user without progress.
+func (s *Service) Select(userID string) ([]CampaignOut, error) {
+	items, err := s.campaigns.Select(WithUserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("can't select campaigns: %w", err)
+	}
+
+	return slices.Map(items, campaignToDTO), nil
+}
+
+// Get returns the campaign of the user with its progress.
+func (s *Service) Get(userID, id string) (CampaignOut, error) {
+	campaign, err := s.campaigns.Get(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return CampaignOut{}, err
+	}
+
+	progress, err := s.campaigns.Progress(campaign.ID)
+	if err != nil {
+		return CampaignOut{}, fmt.Errorf("can't get progress: %w", err)
+	}
+
+	dto := campaignToDTO(campaign)
+	dto.Progress = &progress
+
+	return dto, nil
+}
+
+// Pause stops enqueueing messages of the active campaign.
+func (s *Service) Pause(userID, id string) error {
+	return s.updateState(userID, id, StatePaused, StateActive)
+}
+
+// Resume continues enqueueing messages of the paused campaign.
+func (s *Service) Resume(userID, id string) error {
+	return s.updateState(userID, id, StateActive, StatePaused)
+}
+
+// Cancel stops the campaign permanently. Recipients that were not enqueued yet
+// are skipped, already enqueued messages are not affected.
+func (s *Service) Cancel(userID, id string) error {
+	return s.updateState(userID, id, StateCancelled, StateActive, StatePaused)
+}
+
+func (s *Service) updateState(userID, id string, state State, from ...State) error {
+	campaign, err := s.campaigns.Get(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return err
+	}
+
+	return s.campaigns.UpdateState(campaign.ID, state, from...)
+}
+
+// Run enqueues messages of active campaigns until the context is canceled.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Campaigns task started")
+	defer s.logger.Info("Campaigns task stopped")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.campaigns.WithLock(func() error {
+				return s.process(ctx)
+			})
+			if err != nil && !errors.Is(err, errLocked) {
+				s.logger.Error("Can't process campaigns", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *Service) process(ctx context.Context) error {
+	active, err := s.campaigns.Select(WithState(StateActive))
+	if err != nil {
+		return fmt.Errorf("can't select active campaigns: %w", err)
+	}
+
+	for _, campaign := range active {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if err := s.processCampaign(campaign); err != nil {
+			s.logger.Error("Can't process campaign", zap.String("campaign_id", campaign.ExtID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// processCampaign enqueues as many messages as the campaign rate allows since
+// the last run.
+func (s *Service) processCampaign(campaign Campaign) error {
+	now := time.Now()
+	step := time.Minute / time.Duration(campaign.RatePerMinute)
+
+	count := int(now.Sub(campaign.LastRunAt) / step)
+	if count == 0 {
+		return nil
+	}
+	if count > s.config.BatchLimit {
+		count = s.config.BatchLimit
+	}
+
+	recipients, err := s.campaigns.SelectPendingRecipients(campaign.ID, count)
+	if err != nil {
+		return fmt.Errorf("can't select recipients: %w", err)
+	}
+
+	if len(recipients) == 0 {
+		if err := s.campaigns.UpdateState(campaign.ID, StateCompleted, StateActive); err != nil && !errors.Is(err, ErrInvalidState) {
+			return fmt.Errorf("can't complete campaign: %w", err)
+		}
+		return nil
+	}
+
+	filters := slices.Map(campaign.DeviceIDs, devices.WithID)
+	targets := []models.Device{}
+	if len(filters) == 0 {
+		if targets, err = s.devicesSvc.Select(campaign.UserID, devices.WithEnabled()); err != nil {
+			return fmt.Errorf("can't select devices: %w", err)
+		}
+	}
+	for _, filter := range filters {
+		device, err := s.devicesSvc.Get(campaign.UserID, filter, devices.WithEnabled())
+		if errors.Is(err, devices.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("can't get device: %w", err)
+		}
+		targets = append(targets, device)
+	}
+	if targets, err = s.quotasSvc.Available(campaign.UserID, targets); err != nil {
+		return fmt.Errorf("can't check sending limit: %w", err)
+	}
+	if len(targets) == 0 {
+		s.logger.Warn("No devices available for campaign", zap.String("campaign_id", campaign.ExtID))
+		return nil
+	}
+
+	for _, recipient := range recipients {
+		s.enqueue(campaign, &recipient, targets)
+		if err := s.campaigns.UpdateRecipient(&recipient); err != nil {
+			return fmt.Errorf("can't update recipient: %w", err)
+		}
+	}
+
+	// don't accumulate more than one step of unused rate
+	lastRunAt := campaign.LastRunAt.Add(time.Duration(count) * step)
+	if lastRunAt.Before(now.Add(-step)) {
+		lastRunAt = now.Add(-step)
+	}
+
+	return s.campaigns.UpdateLastRunAt(campaign.ID, lastRunAt)
+}
+
+// enqueue sends the message to the recipient via one of the devices and
+// updates the recipient state in place.
+func (s *Service) enqueue(campaign Campaign, recipient *Recipient, targets []models.Device) {
+	// targets are not empty
+	device, _ := devices.RouteByKey(targets, recipient.ID)
+
+	vars := make(map[string]string, len(recipient.Variables)+1)
+	for k, v := range recipient.Variables {
+		vars[k] = v
+	}
+	vars["phone"] = recipient.PhoneNumber
+
+	state, err := s.messagesSvc.Enqueue(
+		device,
+		messages.MessageIn{
+			Message:      templates.Render(campaign.Message, vars),
+			PhoneNumbers: []string{recipient.PhoneNumber},
+
+			SimNumber:          campaign.SimNumber,
+			WithDeliveryReport: campaign.WithDeliveryReport,
+			TTL:                campaign.TTL,
+			Priority:           smsgateway.MessagePriority(campaign.Priority),
+		},
+		messages.EnqueueOptions{},
+	)
+	if err != nil {
+		errMsg := err.Error()
+		if len(errMsg) > maxErrorLength {
+			errMsg = errMsg[:maxErrorLength]
+		}
+
+		recipient.State = RecipientStateFailed
+		recipient.Error = &errMsg
+		return
+	}
+
+	recipient.State = RecipientStateEnqueued
+	recipient.DeviceID = anys.AsPointer(device.ID)
+	recipient.MessageID = anys.AsPointer(state.ID)
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+)
+
+type Type string
+
+const (
+	TypePing         Type = "ping"
+	TypeSyncSettings Type = "sync_settings"
+	TypeSyncWebhooks Type = "sync_webhooks"
+	TypeFlushQueue   Type = "flush_queue"
+)
+
+var pushEvents = map[Type]smsgateway.PushEventType{
+	TypePing:         push.PushPingRequested,
+	TypeSyncSettings: push.PushSettingsSyncRequested,
+	TypeSyncWebhooks: push.PushWebhooksSyncRequested,
+	TypeFlushQueue:   push.PushQueueFlushRequested,
+}
+
+type State string
+
+const (
+	StatePending      State = "Pending"
+	StateAcknowledged State = "Acknowledged"
+	StateFailed       State = "Failed"
+	// StateExpired is not stored, pending commands become expired after the
+	// acknowledgement timeout.
+	StateExpired State = "Expired"
+)
+
+type CommandOut struct {
+	// Command ID
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Device ID
+	DeviceID string `json:"deviceId" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Command type
+	Type Type `json:"type" enums:"ping,sync_settings,sync_webhooks,flush_queue" example:"ping"`
+	// Command state
+	State State `json:"state" enums:"Pending,Acknowledged,Failed,Expired" example:"Pending"`
+	// Error reported by the device
+	Error *string `json:"error,omitempty" example:"Settings are invalid"`
+	// Created at
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+	// Acknowledged at
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty" example:"2020-01-01T00:00:00Z"`
+}
@@ -0,0 +1,11 @@
+package commands
+
+import "errors"
+
+var (
+	ErrNotFound    = errors.New("command not found")
+	ErrNotPending  = errors.New("command is not pending")
+	ErrNoPushToken = errors.New("device has no push token")
+	ErrPushBlocked = errors.New("pushes to the device are suspended after delivery failures")
+	ErrInvalidType = errors.New("invalid command type")
+)
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type Command struct {
+	ID       string  `gorm:"primaryKey;type:char(21)"`
+	DeviceID string  `gorm:"not null;type:char(21);index:idx_device_commands_device"`
+	Type     Type    `gorm:"not null;type:varchar(32)"`
+	State    State   `gorm:"not null;type:enum('Pending','Acknowledged','Failed');default:Pending"`
+	Error    *string `gorm:"type:varchar(256)"`
+
+	AcknowledgedAt *time.Time `gorm:"type:datetime(3)"`
+
+	Device models.Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Command) TableName() string {
+	return "device_commands"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Command{}); err != nil {
+		return fmt.Errorf("device_commands migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Insert(command *Command) error {
+	return r.db.Omit("Device").Create(command).Error
+}
+
+func (r *repository) Get(deviceID, id string) (Command, error) {
+	command := Command{}
+
+	err := r.db.Where("id = ? AND device_id = ?", id, deviceID).Take(&command).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return command, ErrNotFound
+	}
+
+	return command, err
+}
+
+// Select returns commands of the device, newest first.
+func (r *repository) Select(deviceID string, limit int) ([]Command, error) {
+	commands := []Command{}
+
+	return commands, r.db.
+		Where("device_id = ?", deviceID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&commands).
+		Error
+}
+
+// Acknowledge sets the final state of the pending command.
+func (r *repository) Acknowledge(deviceID, id string, state State, errorMessage *string, pendingSince time.Time) error {
+	res := r.db.
+		Model(&Command{}).
+		Where("id = ? AND device_id = ? AND state = ? AND created_at >= ?", id, deviceID, StatePending, pendingSince).
+		Updates(map[string]any{
+			"state":           state,
+			"error":           errorMessage,
+			"acknowledged_at": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		if _, err := r.Get(deviceID, id); err != nil {
+			return err
+		}
+		return ErrNotPending
+	}
+
+	return nil
+}
+
+func (r *repository) removeOlder(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("created_at < ?", until).
+		Delete(&Command{})
+
+	return res.RowsAffected, res.Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
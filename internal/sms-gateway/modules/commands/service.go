@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// Pending commands are considered expired after this timeout.
+	ackTimeout = time.Hour
+	// Commands older than this are removed.
+	lifetime = 7 * 24 * time.Hour
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Commands *repository
+
+	PushSvc *push.Service
+
+	IDGen db.IDGen
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	commands *repository
+
+	pushSvc *push.Service
+
+	commandsCounter *prometheus.CounterVec
+
+	idGen db.IDGen
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		commands: params.Commands,
+
+		pushSvc: params.PushSvc,
+
+		commandsCounter: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sms",
+			Subsystem: "commands",
+			Name:      "total",
+			Help:      "Total number of remote commands by type and state",
+		}, []string{"type", "state"}),
+
+		idGen: params.IDGen,
+
+		logger: params.Logger.Named("service"),
+	}
+}
+
+// Send stores the command and delivers it to the device via push. Commands
+// that can't be delivered are not stored or are marked as failed.
+func (s *Service) Send(device models.Device, commandType Type) (CommandOut, error) {
+	event, ok := pushEvents[commandType]
+	if !ok {
+		return CommandOut{}, fmt.Errorf("%w: %s", ErrInvalidType, commandType)
+	}
+
+	if device.PushToken == nil {
+		return CommandOut{}, ErrNoPushToken
+	}
+	if s.pushSvc.IsBlacklisted(*device.PushToken) {
+		return CommandOut{}, ErrPushBlocked
+	}
+
+	command := Command{
+		ID:       s.idGen(),
+		DeviceID: device.ID,
+		Type:     commandType,
+		State:    StatePending,
+	}
+	if err := s.commands.Insert(&command); err != nil {
+		return CommandOut{}, fmt.Errorf("can't insert command: %w", err)
+	}
+
+	if err := s.pushSvc.Enqueue(*device.PushToken, push.NewCommandEvent(event, command.ID)); err != nil {
+		errorMessage := "can't enqueue push"
+		if ackErr := s.commands.Acknowledge(device.ID, command.ID, StateFailed, &errorMessage, time.Now().Add(-ackTimeout)); ackErr != nil {
+			s.logger.Error("can't mark command as failed", zap.String("command_id", command.ID), zap.Error(ackErr))
+		}
+		s.commandsCounter.WithLabelValues(string(commandType), string(StateFailed)).Inc()
+
+		return CommandOut{}, fmt.Errorf("can't enqueue command: %w", err)
+	}
+
+	s.commandsCounter.WithLabelValues(string(commandType), string(StatePending)).Inc()
+
+	// read the command back to get the creation time
+	return s.Get(device.ID, command.ID)
+}
+
+func (s *Service) Get(deviceID, id string) (CommandOut, error) {
+	command, err := s.commands.Get(deviceID, id)
+	if err != nil {
+		return CommandOut{}, err
+	}
+
+	return s.commandToDTO(command, time.Now()), nil
+}
+
+// Select returns the latest commands of the device, newest first.
+func (s *Service) Select(deviceID string, limit int) ([]CommandOut, error) {
+	commands, err := s.commands.Select(deviceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't select commands: %w", err)
+	}
+
+	now := time.Now()
+	result := make([]CommandOut, 0, len(commands))
+	for _, command := range commands {
+		result = append(result, s.commandToDTO(command, now))
+	}
+
+	return result, nil
+}
+
+// Acknowledge records the result of the command reported by the device. A
+// non-empty error message marks the command as failed.
+func (s *Service) Acknowledge(deviceID, id string, errorMessage string) error {
+	state := StateAcknowledged
+	var errPtr *string
+	if errorMessage != "" {
+		state = StateFailed
+		errPtr = &errorMessage
+	}
+
+	if err := s.commands.Acknowledge(deviceID, id, state, errPtr, time.Now().Add(-ackTimeout)); err != nil {
+		return err
+	}
+
+	command, err := s.commands.Get(deviceID, id)
+	if err != nil {
+		return fmt.Errorf("can't get command: %w", err)
+	}
+
+	s.commandsCounter.WithLabelValues(string(command.Type), string(state)).Inc()
+
+	return nil
+}
+
+func (s *Service) Clean(ctx context.Context) error {
+	n, err := s.commands.removeOlder(ctx, time.Now().Add(-lifetime))
+
+	s.logger.Info("Cleaned commands", zap.Int64("count", n))
+	return err
+}
+
+func (s *Service) commandToDTO(command Command, now time.Time) CommandOut {
+	state := command.State
+	if state == StatePending && command.CreatedAt.Before(now.Add(-ackTimeout)) {
+		state = StateExpired
+	}
+
+	return CommandOut{
+		ID:             command.ID,
+		DeviceID:       command.DeviceID,
+		Type:           command.Type,
+		State:          state,
+		Error:          command.Error,
+		CreatedAt:      command.CreatedAt,
+		AcknowledgedAt: command.AcknowledgedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package contacts
+
+import "github.com/capcom6/go-helpers/slices"
+
+func contactToDTO(contact Contact) ContactOut {
+	fields := contact.Fields
+	if fields == nil {
+		fields = map[string]string{}
+	}
+
+	return ContactOut{
+		ID:          contact.ExtID,
+		PhoneNumber: contact.PhoneNumber,
+		Name:        contact.Name,
+		Fields:      fields,
+		GroupIDs: slices.Map(contact.Groups, func(g Group) string {
+			return g.ExtID
+		}),
+		CreatedAt: contact.CreatedAt,
+		UpdatedAt: contact.UpdatedAt,
+	}
+}
+
+func groupToDTO(group Group) GroupOut {
+	return GroupOut{
+		ID:        group.ExtID,
+		Name:      group.Name,
+		Contacts:  group.ContactsCount,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package contacts
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var phoneColumns = map[string]struct{}{
+	"phone":        {},
+	"phonenumber":  {},
+	"phone_number": {},
+}
+
+const nameColumn = "name"
+
+// ParseContactsCSV reads contacts from CSV with a header row. The phone number
+// is taken from the `phone`, `phoneNumber` or `phone_number` column and the
+// name from the `name` column, all other columns are used as custom fields.
+func ParseContactsCSV(r io.Reader) ([]ContactIn, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("empty csv")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read header: %w", err)
+	}
+
+	phoneIndex, nameIndex := -1, -1
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+
+		lower := strings.ToLower(column)
+		if _, ok := phoneColumns[lower]; ok && phoneIndex == -1 {
+			phoneIndex = i
+		} else if lower == nameColumn && nameIndex == -1 {
+			nameIndex = i
+		}
+	}
+	if phoneIndex == -1 {
+		return nil, fmt.Errorf("phone column not found")
+	}
+
+	contacts := []ContactIn{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read row %d: %w", len(contacts)+2, err)
+		}
+
+		contact := ContactIn{
+			PhoneNumber: strings.TrimSpace(record[phoneIndex]),
+			Fields:      make(map[string]string, len(record)),
+		}
+		if contact.PhoneNumber == "" {
+			return nil, fmt.Errorf("empty phone number in row %d", len(contacts)+2)
+		}
+
+		for i, value := range record {
+			switch {
+			case i == phoneIndex || header[i] == "":
+				continue
+			case i == nameIndex:
+				contact.Name = strings.TrimSpace(value)
+			default:
+				contact.Fields[header[i]] = value
+			}
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, nil
+}
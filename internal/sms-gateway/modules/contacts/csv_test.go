@@ -0,0 +1,73 @@
+package contacts_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contacts"
+)
+
+func TestParseContactsCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []contacts.ContactIn
+		expectError bool
+	}{
+		{
+			name:  "Phone only",
+			input: "phone\n+79990001234\n",
+			expected: []contacts.ContactIn{
+				{PhoneNumber: "+79990001234", Fields: map[string]string{}},
+			},
+		},
+		{
+			name:  "With name and fields",
+			input: "Name,phoneNumber,city\n John ,+79990001234,London\n",
+			expected: []contacts.ContactIn{
+				{PhoneNumber: "+79990001234", Name: "John", Fields: map[string]string{"city": "London"}},
+			},
+		},
+		{
+			name:     "Header only",
+			input:    "phone_number,name\n",
+			expected: []contacts.ContactIn{},
+		},
+		{
+			name:        "Empty input",
+			input:       "",
+			expectError: true,
+		},
+		{
+			name:        "Missing phone column",
+			input:       "name\nJohn\n",
+			expectError: true,
+		},
+		{
+			name:        "Empty phone number",
+			input:       "phone,name\n,John\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := contacts.ParseContactsCSV(strings.NewReader(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ParseContactsCSV() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package contacts
+
+import "time"
+
+// ContactIn is a contact to create, update or import
+type ContactIn struct {
+	// Contact ID (if not set - will be generated)
+	ID string `json:"id,omitempty" validate:"omitempty,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Phone number
+	PhoneNumber string `json:"phoneNumber" validate:"required,min=1,max=128" example:"79990001234"`
+	// Name
+	Name string `json:"name,omitempty" validate:"max=256" example:"John"`
+	// Custom fields, available as template variables
+	Fields map[string]string `json:"fields,omitempty" validate:"max=32,dive,keys,required,max=64,endkeys,max=1024" example:"city:London"`
+	// Groups the contact belongs to
+	GroupIDs []string `json:"groupIds,omitempty" validate:"max=100,dive,required,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+}
+
+// ContactOut is a stored contact
+type ContactOut struct {
+	// Contact ID
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Phone number
+	PhoneNumber string `json:"phoneNumber" example:"79990001234"`
+	// Name
+	Name string `json:"name" example:"John"`
+	// Custom fields
+	Fields map[string]string `json:"fields" example:"city:London"`
+	// Groups the contact belongs to
+	GroupIDs []string `json:"groupIds"`
+
+	// Created at (read only)
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+	// Updated at (read only)
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+// Variables returns template variables of the contact: custom fields,
+// `name` and `phone`.
+func (c ContactOut) Variables() map[string]string {
+	vars := make(map[string]string, len(c.Fields)+2)
+	for k, v := range c.Fields {
+		vars[k] = v
+	}
+	vars["name"] = c.Name
+	vars["phone"] = c.PhoneNumber
+
+	return vars
+}
+
+// GroupIn is a group to create or update
+type GroupIn struct {
+	// Group ID (if not set - will be generated)
+	ID string `json:"id,omitempty" validate:"omitempty,max=36" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Name
+	Name string `json:"name" validate:"required,max=128" example:"Customers"`
+}
+
+// GroupOut is a stored group
+type GroupOut struct {
+	// Group ID
+	ID string `json:"id" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// Name
+	Name string `json:"name" example:"Customers"`
+	// Number of contacts in the group
+	Contacts int64 `json:"contacts" example:"100"`
+
+	// Created at (read only)
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+	// Updated at (read only)
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
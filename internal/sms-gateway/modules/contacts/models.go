@@ -0,0 +1,47 @@
+package contacts
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type Contact struct {
+	ID          uint64            `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	ExtID       string            `gorm:"not null;type:varchar(36);uniqueIndex:unq_contacts_user_extid,priority:2"`
+	UserID      string            `gorm:"<-:create;not null;type:varchar(32);uniqueIndex:unq_contacts_user_extid,priority:1;uniqueIndex:unq_contacts_user_phone,priority:1"`
+	PhoneNumber string            `gorm:"not null;type:varchar(128);uniqueIndex:unq_contacts_user_phone,priority:2"`
+	Name        string            `gorm:"not null;type:varchar(256);default:''"`
+	Fields      map[string]string `gorm:"type:json;serializer:json"`
+
+	User   models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Groups []Group     `gorm:"many2many:contact_group_members;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+type Group struct {
+	ID     uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	ExtID  string `gorm:"not null;type:varchar(36);uniqueIndex:unq_contact_groups_user_extid,priority:2"`
+	UserID string `gorm:"<-:create;not null;type:varchar(32);uniqueIndex:unq_contact_groups_user_extid,priority:1"`
+	Name   string `gorm:"not null;type:varchar(128)"`
+
+	ContactsCount int64 `gorm:"->;-:migration"`
+
+	User     models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Contacts []Contact   `gorm:"many2many:contact_group_members;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Group) TableName() string {
+	return "contact_groups"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Contact{}, &Group{}); err != nil {
+		return fmt.Errorf("contacts migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package contacts
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) SelectContacts(filters ...SelectFilter) ([]Contact, error) {
+	contacts := []Contact{}
+
+	return contacts, newFilter(filters...).apply(r.db).
+		Preload("Groups", selectGroupIDs).
+		Order("id").
+		Find(&contacts).
+		Error
+}
+
+func (r *repository) GetContact(filters ...SelectFilter) (Contact, error) {
+	contact := Contact{}
+
+	return contact, newFilter(filters...).apply(r.db).
+		Preload("Groups", selectGroupIDs).
+		Take(&contact).
+		Error
+}
+
+func (r *repository) InsertContact(contact *Contact) error {
+	return translateError(
+		r.db.Omit("User", "Groups.*").Create(contact).Error,
+	)
+}
+
+// UpdateContact updates the contact fields and replaces its groups.
+func (r *repository) UpdateContact(contact *Contact) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(contact).Select("PhoneNumber", "Name", "Fields").Updates(contact).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(contact).Omit("Groups.*").Association("Groups").Replace(contact.Groups)
+	})
+
+	return translateError(err)
+}
+
+// UpsertContacts inserts the contacts or updates name and fields of existing
+// ones with the same phone number. All contacts are added to the groups.
+func (r *repository) UpsertContacts(userID string, contacts []Contact, groups []Group) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Omit("User", "Groups").
+			Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"name", "fields"})}).
+			CreateInBatches(contacts, 1000).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(groups) == 0 {
+			return nil
+		}
+
+		phones := make([]string, len(contacts))
+		for i, c := range contacts {
+			phones[i] = c.PhoneNumber
+		}
+
+		ids := []uint64{}
+		if err := tx.Model(&Contact{}).Where("user_id = ? AND phone_number IN ?", userID, phones).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		members := make([]map[string]any, 0, len(ids)*len(groups))
+		for _, id := range ids {
+			for _, g := range groups {
+				members = append(members, map[string]any{"contact_id": id, "group_id": g.ID})
+			}
+		}
+
+		return tx.Table("contact_group_members").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(members, 1000).
+			Error
+	})
+}
+
+func (r *repository) DeleteContact(id uint64) error {
+	return r.db.Delete(&Contact{}, id).Error
+}
+
+func (r *repository) SelectGroups(filters ...SelectFilter) ([]Group, error) {
+	groups := []Group{}
+
+	return groups, newFilter(filters...).apply(r.db).
+		Select("contact_groups.*, (SELECT COUNT(*) FROM contact_group_members m WHERE m.group_id = contact_groups.id) AS contacts_count").
+		Order("id").
+		Find(&groups).
+		Error
+}
+
+func (r *repository) GetGroup(filters ...SelectFilter) (Group, error) {
+	group := Group{}
+
+	return group, newFilter(filters...).apply(r.db).
+		Select("contact_groups.*, (SELECT COUNT(*) FROM contact_group_members m WHERE m.group_id = contact_groups.id) AS contacts_count").
+		Take(&group).
+		Error
+}
+
+func (r *repository) InsertGroup(group *Group) error {
+	return translateError(
+		r.db.Omit("User", "Contacts").Create(group).Error,
+	)
+}
+
+func (r *repository) UpdateGroup(group *Group) error {
+	return r.db.Model(group).Select("Name").Updates(group).Error
+}
+
+func (r *repository) DeleteGroup(id uint64) error {
+	return r.db.Delete(&Group{}, id).Error
+}
+
+func selectGroupIDs(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "ext_id").Order("id")
+}
+
+func translateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return ErrAlreadyExists
+	}
+
+	return err
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,59 @@
+package contacts
+
+import "gorm.io/gorm"
+
+type SelectFilter func(*selectFilter)
+
+func WithExtID(extID ...string) SelectFilter {
+	return func(f *selectFilter) {
+		f.extIDs = extID
+	}
+}
+
+func WithUserID(userID string) SelectFilter {
+	return func(f *selectFilter) {
+		f.userID = &userID
+	}
+}
+
+// WithGroupID selects contacts that belong to any of the groups. It is
+// applicable to contacts only.
+func WithGroupID(groupID ...string) SelectFilter {
+	return func(f *selectFilter) {
+		f.groupIDs = groupID
+	}
+}
+
+type selectFilter struct {
+	extIDs   []string
+	userID   *string
+	groupIDs []string
+}
+
+func newFilter(filters ...SelectFilter) *selectFilter {
+	f := &selectFilter{}
+	f.merge(filters...)
+	return f
+}
+
+func (f *selectFilter) merge(filters ...SelectFilter) {
+	for _, filter := range filters {
+		filter(f)
+	}
+}
+
+func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.extIDs != nil {
+		query = query.Where("ext_id IN ?", f.extIDs)
+	}
+	if f.userID != nil {
+		query = query.Where("user_id = ?", *f.userID)
+	}
+	if f.groupIDs != nil {
+		query = query.Where(
+			"id IN (SELECT m.contact_id FROM contact_group_members m JOIN contact_groups g ON g.id = m.group_id WHERE g.user_id = contacts.user_id AND g.ext_id IN ?)",
+			f.groupIDs,
+		)
+	}
+	return query
+}
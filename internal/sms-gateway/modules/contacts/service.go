@@ -0,0 +1,246 @@
+package contacts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/capcom6/go-helpers/slices"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type ServiceParams struct {
+	fx.In
+
+	IDGen db.IDGen
+
+	Contacts *repository
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	idgen db.IDGen
+
+	contacts *repository
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		idgen:    params.IDGen,
+		contacts: params.Contacts,
+		logger:   params.Logger.Named("service"),
+	}
+}
+
+// SelectContacts returns contacts of the user, optionally only the ones
+// belonging to any of the groups.
+func (s *Service) SelectContacts(userID string, groupIDs ...string) ([]ContactOut, error) {
+	filters := []SelectFilter{WithUserID(userID)}
+	if len(groupIDs) > 0 {
+		filters = append(filters, WithGroupID(groupIDs...))
+	}
+
+	items, err := s.contacts.SelectContacts(filters...)
+	if err != nil {
+		return nil, fmt.Errorf("can't select contacts: %w", err)
+	}
+
+	return slices.Map(items, contactToDTO), nil
+}
+
+func (s *Service) GetContact(userID, id string) (ContactOut, error) {
+	contact, err := s.contacts.GetContact(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return ContactOut{}, err
+	}
+
+	return contactToDTO(contact), nil
+}
+
+func (s *Service) CreateContact(userID string, contact ContactIn) (ContactOut, error) {
+	groups, err := s.getGroups(userID, contact.GroupIDs)
+	if err != nil {
+		return ContactOut{}, err
+	}
+
+	if contact.ID == "" {
+		contact.ID = s.idgen()
+	}
+
+	model := Contact{
+		ExtID:       contact.ID,
+		UserID:      userID,
+		PhoneNumber: strings.TrimSpace(contact.PhoneNumber),
+		Name:        contact.Name,
+		Fields:      contact.Fields,
+		Groups:      groups,
+	}
+
+	if err := s.contacts.InsertContact(&model); err != nil {
+		return ContactOut{}, fmt.Errorf("can't create contact: %w", err)
+	}
+
+	return s.GetContact(userID, model.ExtID)
+}
+
+// UpdateContact replaces the contact data and groups.
+func (s *Service) UpdateContact(userID, id string, contact ContactIn) (ContactOut, error) {
+	model, err := s.contacts.GetContact(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return ContactOut{}, err
+	}
+
+	groups, err := s.getGroups(userID, contact.GroupIDs)
+	if err != nil {
+		return ContactOut{}, err
+	}
+
+	model.PhoneNumber = strings.TrimSpace(contact.PhoneNumber)
+	model.Name = contact.Name
+	model.Fields = contact.Fields
+	model.Groups = groups
+
+	if err := s.contacts.UpdateContact(&model); err != nil {
+		return ContactOut{}, fmt.Errorf("can't update contact: %w", err)
+	}
+
+	return s.GetContact(userID, id)
+}
+
+func (s *Service) DeleteContact(userID, id string) error {
+	contact, err := s.contacts.GetContact(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return err
+	}
+
+	return s.contacts.DeleteContact(contact.ID)
+}
+
+// ImportContacts creates the contacts or updates existing ones with the same
+// phone number and adds all of them to the groups. Group membership of
+// existing contacts is extended, not replaced.
+func (s *Service) ImportContacts(userID string, contacts []ContactIn, groupIDs []string) (int, error) {
+	groups, err := s.getGroups(userID, groupIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	items := slices.Map(contacts, func(c ContactIn) Contact {
+		return Contact{
+			ExtID:       s.idgen(),
+			UserID:      userID,
+			PhoneNumber: strings.TrimSpace(c.PhoneNumber),
+			Name:        c.Name,
+			Fields:      c.Fields,
+		}
+	})
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	if err := s.contacts.UpsertContacts(userID, items, groups); err != nil {
+		return 0, fmt.Errorf("can't import contacts: %w", err)
+	}
+
+	return len(items), nil
+}
+
+// Expand returns distinct contacts of the groups. It returns a validation
+// error if any of the groups doesn't exist.
+func (s *Service) Expand(userID string, groupIDs []string) ([]ContactOut, error) {
+	if _, err := s.getGroups(userID, groupIDs); err != nil {
+		return nil, err
+	}
+
+	return s.SelectContacts(userID, groupIDs...)
+}
+
+func (s *Service) SelectGroups(userID string) ([]GroupOut, error) {
+	items, err := s.contacts.SelectGroups(WithUserID(userID))
+	if err != nil {
+		return nil, fmt.Errorf("can't select groups: %w", err)
+	}
+
+	return slices.Map(items, groupToDTO), nil
+}
+
+func (s *Service) GetGroup(userID, id string) (GroupOut, error) {
+	group, err := s.contacts.GetGroup(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return GroupOut{}, err
+	}
+
+	return groupToDTO(group), nil
+}
+
+func (s *Service) CreateGroup(userID string, group GroupIn) (GroupOut, error) {
+	if group.ID == "" {
+		group.ID = s.idgen()
+	}
+
+	model := Group{
+		ExtID:  group.ID,
+		UserID: userID,
+		Name:   group.Name,
+	}
+
+	if err := s.contacts.InsertGroup(&model); err != nil {
+		return GroupOut{}, fmt.Errorf("can't create group: %w", err)
+	}
+
+	return s.GetGroup(userID, model.ExtID)
+}
+
+func (s *Service) UpdateGroup(userID, id string, group GroupIn) (GroupOut, error) {
+	model, err := s.contacts.GetGroup(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return GroupOut{}, err
+	}
+
+	model.Name = group.Name
+	if err := s.contacts.UpdateGroup(&model); err != nil {
+		return GroupOut{}, fmt.Errorf("can't update group: %w", err)
+	}
+
+	return s.GetGroup(userID, id)
+}
+
+// DeleteGroup deletes the group. Contacts of the group are not deleted.
+func (s *Service) DeleteGroup(userID, id string) error {
+	group, err := s.contacts.GetGroup(WithUserID(userID), WithExtID(id))
+	if err != nil {
+		return err
+	}
+
+	return s.contacts.DeleteGroup(group.ID)
+}
+
+// getGroups returns groups of the user by IDs. It returns a validation error
+// if any of the groups doesn't exist.
+func (s *Service) getGroups(userID string, ids []string) ([]Group, error) {
+	if len(ids) == 0 {
+		return []Group{}, nil
+	}
+
+	groups, err := s.contacts.SelectGroups(WithUserID(userID), WithExtID(ids...))
+	if err != nil {
+		return nil, fmt.Errorf("can't select groups: %w", err)
+	}
+
+	found := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		found[g.ExtID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return nil, newValidationError("groupIds", id, errors.New("group not found"))
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,38 @@
+package contentpolicy
+
+type EncryptedMode string
+
+const (
+	// EncryptedModeAllow lets encrypted messages bypass the policy as their
+	// content can't be checked. The flag is not verified, so it must be opted
+	// in explicitly.
+	EncryptedModeAllow EncryptedMode = "allow"
+	// EncryptedModeReject rejects all encrypted messages
+	EncryptedModeReject EncryptedMode = "reject"
+)
+
+type Rule string
+
+const (
+	RuleMaxLength    Rule = "maxLength"
+	RuleDenyKeywords Rule = "denyKeywords"
+	RuleDenyPatterns Rule = "denyPatterns"
+	RuleURLAllowlist Rule = "urlAllowlist"
+	RuleEncrypted    Rule = "encrypted"
+)
+
+// Policy is a set of rules for content of outgoing messages
+type Policy struct {
+	// Max message length in characters, `0` - no limit
+	MaxLength uint32 `json:"maxLength" validate:"max=65535" example:"640"`
+	// Case-insensitive words and phrases that are not allowed
+	DenyKeywords []string `json:"denyKeywords" validate:"max=1000,dive,required,max=128" example:"casino"`
+	// Regular expressions that must not match the message
+	DenyPatterns []string `json:"denyPatterns" validate:"max=100,dive,required,max=512" example:"(?i)free\\s+money"`
+	// Reject messages with URLs which domains are not in `urlAllowlist`
+	RestrictURLs bool `json:"restrictUrls" example:"true"`
+	// Allowed URL domains, subdomains are allowed too
+	URLAllowlist []string `json:"urlAllowlist" validate:"max=1000,dive,required,fqdn" example:"example.com"`
+	// Encrypted messages can't be checked, so they are either allowed or rejected as a whole. The `isEncrypted` flag is set by the client and is not verified, so `allow` lets any sender bypass the other rules. Defaults to `reject` if the policy has any rules, otherwise to `allow`
+	Encrypted EncryptedMode `json:"encrypted" validate:"omitempty,oneof=allow reject" example:"reject"`
+}
@@ -0,0 +1,100 @@
+package contentpolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var urlRegexp = regexp.MustCompile(`(?i)(?:https?://)?((?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63})\b`)
+
+// engine checks messages against the compiled policy
+type engine struct {
+	policy Policy
+
+	keywords  *regexp.Regexp
+	patterns  []*regexp.Regexp
+	allowlist []string
+}
+
+func newEngine(policy Policy) (*engine, error) {
+	e := &engine{
+		policy:    policy,
+		patterns:  make([]*regexp.Regexp, 0, len(policy.DenyPatterns)),
+		allowlist: make([]string, 0, len(policy.URLAllowlist)),
+	}
+
+	alternatives := make([]string, 0, len(policy.DenyKeywords))
+	for _, keyword := range policy.DenyKeywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			alternatives = append(alternatives, regexp.QuoteMeta(keyword))
+		}
+	}
+	if len(alternatives) > 0 {
+		// keywords match whole words only, \b is not Unicode-aware
+		e.keywords = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}_])(` + strings.Join(alternatives, "|") + `)(?:$|[^\p{L}\p{N}_])`)
+	}
+
+	for _, pattern := range policy.DenyPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, newValidationError("denyPatterns", pattern, err)
+		}
+		e.patterns = append(e.patterns, re)
+	}
+
+	for _, domain := range policy.URLAllowlist {
+		e.allowlist = append(e.allowlist, strings.ToLower(strings.TrimSuffix(domain, ".")))
+	}
+
+	return e, nil
+}
+
+// Check returns Violation if the message violates the policy.
+func (e *engine) Check(text string, isEncrypted bool) error {
+	if isEncrypted {
+		if e.policy.Encrypted == EncryptedModeReject {
+			return Violation{Rule: RuleEncrypted, Detail: "encrypted messages are not allowed"}
+		}
+		return nil
+	}
+
+	if e.policy.MaxLength > 0 {
+		if length := utf8.RuneCountInString(text); length > int(e.policy.MaxLength) {
+			return Violation{Rule: RuleMaxLength, Detail: fmt.Sprintf("length %d exceeds %d", length, e.policy.MaxLength)}
+		}
+	}
+
+	if e.keywords != nil {
+		if match := e.keywords.FindStringSubmatch(text); match != nil {
+			return Violation{Rule: RuleDenyKeywords, Detail: fmt.Sprintf("keyword `%s`", match[1])}
+		}
+	}
+
+	for i, re := range e.patterns {
+		if re.MatchString(text) {
+			return Violation{Rule: RuleDenyPatterns, Detail: fmt.Sprintf("pattern `%s`", e.policy.DenyPatterns[i])}
+		}
+	}
+
+	if e.policy.RestrictURLs {
+		for _, match := range urlRegexp.FindAllStringSubmatch(text, -1) {
+			if domain := strings.ToLower(match[1]); !e.isAllowedDomain(domain) {
+				return Violation{Rule: RuleURLAllowlist, Detail: fmt.Sprintf("domain `%s` is not allowed", domain)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *engine) isAllowedDomain(domain string) bool {
+	for _, allowed := range e.allowlist {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
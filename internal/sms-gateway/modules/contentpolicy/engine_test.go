@@ -0,0 +1,117 @@
+package contentpolicy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEngine_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      Policy
+		text        string
+		isEncrypted bool
+		want        Rule
+	}{
+		{
+			name:   "empty policy",
+			policy: Policy{},
+			text:   "Visit https://casino.example for free money",
+		},
+		{
+			name:   "max length",
+			policy: Policy{MaxLength: 5},
+			text:   "Привет!",
+			want:   RuleMaxLength,
+		},
+		{
+			name:   "max length unicode",
+			policy: Policy{MaxLength: 6},
+			text:   "Привет",
+		},
+		{
+			name:   "keyword",
+			policy: Policy{DenyKeywords: []string{"casino"}},
+			text:   "Best CASINO in town",
+			want:   RuleDenyKeywords,
+		},
+		{
+			name:   "keyword part of word",
+			policy: Policy{DenyKeywords: []string{"casino"}},
+			text:   "Casinos are closed",
+		},
+		{
+			name:   "keyword phrase",
+			policy: Policy{DenyKeywords: []string{"free money", " "}},
+			text:   "Get free money now",
+			want:   RuleDenyKeywords,
+		},
+		{
+			name:   "pattern",
+			policy: Policy{DenyPatterns: []string{`\d{4}-\d{4}`}},
+			text:   "Card 1234-5678",
+			want:   RuleDenyPatterns,
+		},
+		{
+			name:   "allowed domain",
+			policy: Policy{RestrictURLs: true, URLAllowlist: []string{"example.com"}},
+			text:   "See https://www.example.com/path and example.com",
+		},
+		{
+			name:   "denied domain",
+			policy: Policy{RestrictURLs: true, URLAllowlist: []string{"example.com"}},
+			text:   "See https://example.com.evil.org/path",
+			want:   RuleURLAllowlist,
+		},
+		{
+			name:   "urls are not restricted",
+			policy: Policy{URLAllowlist: []string{"example.com"}},
+			text:   "See https://evil.org",
+		},
+		{
+			name:        "encrypted allowed",
+			policy:      Policy{MaxLength: 1, Encrypted: EncryptedModeAllow},
+			text:        "encrypted",
+			isEncrypted: true,
+		},
+		{
+			name:        "encrypted rejected",
+			policy:      Policy{Encrypted: EncryptedModeReject},
+			text:        "encrypted",
+			isEncrypted: true,
+			want:        RuleEncrypted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newEngine(tt.policy)
+			if err != nil {
+				t.Fatalf("newEngine() error = %v", err)
+			}
+
+			err = e.Check(tt.text, tt.isEncrypted)
+
+			var violation Violation
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &violation) {
+				t.Fatalf("Check() error = %v, want Violation", err)
+			}
+			if violation.Rule != tt.want {
+				t.Errorf("Check() rule = %v, want %v", violation.Rule, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEngine_InvalidPattern(t *testing.T) {
+	_, err := newEngine(Policy{DenyPatterns: []string{"("}})
+	if !IsValidationError(err) {
+		t.Errorf("newEngine() error = %v, want ValidationError", err)
+	}
+}
@@ -0,0 +1,42 @@
+package contentpolicy
+
+import (
+	"fmt"
+)
+
+// Violation is returned when the message content violates the policy
+type Violation struct {
+	Rule   Rule
+	Detail string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("content policy rule `%s` violated: %s", v.Rule, v.Detail)
+}
+
+type ValidationError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid `%s` = `%s`: %s", e.Field, e.Value, e.Err)
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newValidationError(field, value string, err error) ValidationError {
+	return ValidationError{
+		Field: field,
+		Value: value,
+		Err:   err,
+	}
+}
+
+func IsValidationError(err error) bool {
+	_, ok := err.(ValidationError)
+	return ok
+}
@@ -0,0 +1,24 @@
+package contentpolicy
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type ContentPolicy struct {
+	UserID string `gorm:"primaryKey;type:varchar(32)"`
+	Policy Policy `gorm:"not null;type:json;serializer:json"`
+
+	User models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&ContentPolicy{}); err != nil {
+		return fmt.Errorf("content_policies migration failed: %w", err)
+	}
+	return nil
+}
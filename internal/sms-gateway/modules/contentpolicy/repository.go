@@ -0,0 +1,34 @@
+package contentpolicy
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Get(userID string) (ContentPolicy, error) {
+	policy := ContentPolicy{}
+
+	return policy, r.db.Where("user_id = ?", userID).Take(&policy).Error
+}
+
+func (r *repository) Replace(policy *ContentPolicy) error {
+	return r.db.
+		Omit("User").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(policy).
+		Error
+}
+
+func (r *repository) Delete(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&ContentPolicy{}).Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
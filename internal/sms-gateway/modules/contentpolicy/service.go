@@ -0,0 +1,133 @@
+package contentpolicy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/capcom6/go-helpers/cache"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// policies are cached per instance, so changes made via other instances are
+// applied within this time
+const cacheTTL = time.Minute
+
+type ServiceParams struct {
+	fx.In
+
+	Policies *repository
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	policies *repository
+	engines  *cache.Cache[*engine]
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		policies: params.Policies,
+		engines:  cache.New[*engine](cache.Config{TTL: cacheTTL}),
+		logger:   params.Logger.Named("service"),
+	}
+}
+
+// Get returns the user's policy or an empty policy if it is not set.
+func (s *Service) Get(userID string) (Policy, error) {
+	policy, err := s.policies.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultPolicy(), nil
+	}
+	if err != nil {
+		return Policy{}, fmt.Errorf("can't get policy: %w", err)
+	}
+
+	return normalize(policy.Policy), nil
+}
+
+// Replace validates and stores the user's policy.
+func (s *Service) Replace(userID string, policy Policy) (Policy, error) {
+	policy = normalize(policy)
+
+	if _, err := newEngine(policy); err != nil {
+		return Policy{}, err
+	}
+
+	if err := s.policies.Replace(&ContentPolicy{UserID: userID, Policy: policy}); err != nil {
+		return Policy{}, fmt.Errorf("can't replace policy: %w", err)
+	}
+
+	_ = s.engines.Delete(userID)
+
+	return policy, nil
+}
+
+// Delete removes the user's policy, so all messages are allowed.
+func (s *Service) Delete(userID string) error {
+	if err := s.policies.Delete(userID); err != nil {
+		return fmt.Errorf("can't delete policy: %w", err)
+	}
+
+	_ = s.engines.Delete(userID)
+
+	return nil
+}
+
+// Check returns Violation if the message violates the user's policy.
+func (s *Service) Check(userID string, text string, isEncrypted bool) error {
+	e, err := s.engines.Get(userID)
+	if err != nil {
+		policy, err := s.Get(userID)
+		if err != nil {
+			return err
+		}
+
+		if e, err = newEngine(policy); err != nil {
+			return fmt.Errorf("can't compile policy: %w", err)
+		}
+
+		_ = s.engines.Set(userID, e)
+	}
+
+	return e.Check(text, isEncrypted)
+}
+
+func defaultPolicy() Policy {
+	return normalize(Policy{})
+}
+
+func normalize(policy Policy) Policy {
+	if policy.DenyKeywords == nil {
+		policy.DenyKeywords = []string{}
+	}
+	if policy.DenyPatterns == nil {
+		policy.DenyPatterns = []string{}
+	}
+	if policy.URLAllowlist == nil {
+		policy.URLAllowlist = []string{}
+	}
+	if policy.Encrypted == "" {
+		// the encryption flag is set by the client and can't be verified, so
+		// allowing encrypted messages would bypass all the rules
+		policy.Encrypted = EncryptedModeAllow
+		if hasRules(policy) {
+			policy.Encrypted = EncryptedModeReject
+		}
+	}
+
+	return policy
+}
+
+// hasRules reports whether the policy restricts message content.
+func hasRules(policy Policy) bool {
+	return policy.MaxLength > 0 ||
+		len(policy.DenyKeywords) > 0 ||
+		len(policy.DenyPatterns) > 0 ||
+		policy.RestrictURLs
+}
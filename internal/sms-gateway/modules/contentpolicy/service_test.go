@@ -0,0 +1,26 @@
+package contentpolicy
+
+import "testing"
+
+func TestNormalizeEncrypted(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   EncryptedMode
+	}{
+		{"empty", Policy{}, EncryptedModeAllow},
+		{"max length", Policy{MaxLength: 10}, EncryptedModeReject},
+		{"keywords", Policy{DenyKeywords: []string{"casino"}}, EncryptedModeReject},
+		{"patterns", Policy{DenyPatterns: []string{"free"}}, EncryptedModeReject},
+		{"urls", Policy{RestrictURLs: true}, EncryptedModeReject},
+		{"explicit allow", Policy{MaxLength: 10, Encrypted: EncryptedModeAllow}, EncryptedModeAllow},
+		{"explicit reject", Policy{Encrypted: EncryptedModeReject}, EncryptedModeReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalize(tt.policy).Encrypted; got != tt.want {
+				t.Errorf("normalize().Encrypted = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
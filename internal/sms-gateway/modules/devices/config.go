@@ -4,4 +4,11 @@ import "time"
 
 type Config struct {
 	UnusedLifetime time.Duration
+
+	// Devices not seen for longer are stale
+	StaleAfter time.Duration
+	// Devices not seen for longer are offline
+	OfflineAfter time.Duration
+	// Interval of checking devices going offline
+	StatusInterval time.Duration
 }
@@ -0,0 +1,33 @@
+package devices
+
+import (
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+func simToDTO(sim SIM) SIMOut {
+	return SIMOut{
+		SIMIn: SIMIn{
+			Slot:    sim.Slot,
+			Carrier: sim.Carrier,
+			MSISDN:  sim.MSISDN,
+			Country: sim.Country,
+		},
+		UpdatedAt: sim.UpdatedAt,
+	}
+}
+
+// normalizeMSISDN formats valid phone numbers as E.164, so numbers reported by
+// devices match sender numbers of messages. The country of the SIM card is used
+// for numbers in national format.
+func normalizeMSISDN(msisdn, country string) string {
+	msisdn = strings.TrimSpace(msisdn)
+
+	phone, err := phonenumbers.Parse(msisdn, strings.ToUpper(country))
+	if err != nil || !phonenumbers.IsValidNumber(phone) {
+		return msisdn
+	}
+
+	return phonenumbers.Format(phone, phonenumbers.E164)
+}
@@ -0,0 +1,29 @@
+package devices
+
+import "time"
+
+type SIMIn struct {
+	// SIM card slot, same as the message `simNumber`
+	Slot uint8 `json:"slot" validate:"required,min=1,max=3" example:"1"`
+	// Carrier name
+	Carrier string `json:"carrier,omitempty" validate:"max=64" example:"MTS"`
+	// Phone number of the SIM card, if known
+	MSISDN string `json:"msisdn,omitempty" validate:"max=32" example:"+79990001234"`
+	// ISO 3166-1 alpha-2 country code of the SIM card
+	Country string `json:"country,omitempty" validate:"omitempty,len=2,alpha" example:"RU"`
+}
+
+type SIMOut struct {
+	SIMIn
+
+	// Reported at
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+// DeviceUpdate contains device fields to update, nil fields are not changed.
+type DeviceUpdate struct {
+	Name    *string
+	Tags    *[]string
+	Enabled *bool
+	Weight  *uint8
+}
@@ -0,0 +1,61 @@
+package devices
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/health"
+	"go.uber.org/fx"
+)
+
+// Share of offline devices in percent starting from which the check warns.
+const offlineWarnPercent = 50
+
+type HealthProviderParams struct {
+	fx.In
+
+	Config  Config
+	Devices *repository
+}
+
+type HealthProvider struct {
+	config  Config
+	devices *repository
+}
+
+func (p *HealthProvider) Name() string {
+	return "devices"
+}
+
+func (p *HealthProvider) HealthCheck(ctx context.Context) (health.Checks, error) {
+	total, offline, err := p.devices.CountOffline(ctx, time.Now().Add(-p.config.OfflineAfter))
+	if err != nil {
+		return nil, err
+	}
+
+	share := 0
+	if total > 0 {
+		share = int(offline * 100 / total)
+	}
+
+	status := health.StatusPass
+	if share >= offlineWarnPercent {
+		status = health.StatusWarn
+	}
+
+	return health.Checks{
+		"offline": {
+			Description:   "Share of offline devices",
+			ObservedUnit:  "%",
+			ObservedValue: share,
+			Status:        status,
+		},
+	}, nil
+}
+
+func NewHealthProvider(params HealthProviderParams) *HealthProvider {
+	return &HealthProvider{
+		config:  params.Config,
+		devices: params.Devices,
+	}
+}
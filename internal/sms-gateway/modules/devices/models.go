@@ -0,0 +1,32 @@
+package devices
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// SIM is a SIM card slot reported by the device
+type SIM struct {
+	DeviceID string `gorm:"primaryKey;type:char(21)"`
+	Slot     uint8  `gorm:"primaryKey;type:tinyint(1) unsigned"`
+	Carrier  string `gorm:"not null;type:varchar(64);default:''"`
+	MSISDN   string `gorm:"not null;type:varchar(32);default:'';index:idx_device_sims_msisdn"`
+	Country  string `gorm:"not null;type:char(2);default:''"`
+
+	Device models.Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (SIM) TableName() string {
+	return "device_sims"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SIM{}); err != nil {
+		return fmt.Errorf("device_sims migration failed: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package devices
 
 import (
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/cleaner"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/health"
+	"github.com/capcom6/go-infra-fx/db"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -22,6 +24,10 @@ var Module = fx.Module(
 		newDevicesRepository,
 		fx.Private,
 	),
+	fx.Provide(
+		health.AsHealthProvider(NewHealthProvider),
+		NewMonitor,
+	),
 	fx.Provide(func(p ServiceParams) FxResult {
 		svc := NewService(p)
 		return FxResult{
@@ -30,3 +36,7 @@ var Module = fx.Module(
 		}
 	}),
 )
+
+func init() {
+	db.RegisterMigration(Migrate)
+}
@@ -0,0 +1,101 @@
+package devices
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type MonitorParams struct {
+	fx.In
+
+	Config Config
+
+	Devices    *repository
+	DevicesSvc *Service
+
+	Handlers []StatusHandler `group:"device-status-handlers"`
+
+	Logger *zap.Logger
+}
+
+// Monitor detects devices going offline and passes status events to the
+// handlers.
+type Monitor struct {
+	config Config
+
+	devices *repository
+	events  <-chan StatusEvent
+
+	handlers []StatusHandler
+
+	logger *zap.Logger
+}
+
+func NewMonitor(params MonitorParams) *Monitor {
+	return &Monitor{
+		config:   params.Config,
+		devices:  params.Devices,
+		events:   params.DevicesSvc.statusEvents,
+		handlers: params.Handlers,
+		logger:   params.Logger.Named("monitor"),
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.StatusInterval)
+	defer ticker.Stop()
+
+	m.logger.Info("Devices monitor started")
+	defer m.logger.Info("Devices monitor stopped")
+
+	// devices offline at start are not reported
+	checkedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-m.events:
+			m.emit(ctx, event)
+		case now := <-ticker.C:
+			if err := m.checkOffline(ctx, checkedAt, now); err != nil {
+				m.logger.Error("Can't check offline devices", zap.Error(err))
+				continue
+			}
+			checkedAt = now
+		}
+	}
+}
+
+// checkOffline emits events for devices that went offline since the previous
+// check.
+func (m *Monitor) checkOffline(ctx context.Context, checkedAt, now time.Time) error {
+	devices, err := m.devices.SelectLastSeen(
+		ctx,
+		checkedAt.Add(-m.config.OfflineAfter),
+		now.Add(-m.config.OfflineAfter),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		m.emit(ctx, StatusEvent{Device: device, Status: StatusOffline})
+	}
+
+	return nil
+}
+
+func (m *Monitor) emit(ctx context.Context, event StatusEvent) {
+	m.logger.Info(
+		"Device status changed",
+		zap.String("device_id", event.Device.ID),
+		zap.String("status", string(event.Status)),
+	)
+
+	for _, h := range m.handlers {
+		h.HandleStatus(ctx, event)
+	}
+}
@@ -13,6 +13,7 @@ var (
 	ErrNotFound      = gorm.ErrRecordNotFound
 	ErrInvalidFilter = errors.New("invalid filter")
 	ErrMoreThanOne   = errors.New("more than one record")
+	ErrInvalidSIM    = errors.New("invalid SIM")
 )
 
 type repository struct {
@@ -30,6 +31,18 @@ func (r *repository) Select(filter ...SelectFilter) ([]models.Device, error) {
 	return devices, f.apply(r.db).Find(&devices).Error
 }
 
+// SelectPage returns a page of devices of all users, sorted by ID.
+func (r *repository) SelectPage(limit, offset int, filter ...SelectFilter) ([]models.Device, error) {
+	devices := []models.Device{}
+
+	return devices, newFilter(filter...).apply(r.db).
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&devices).
+		Error
+}
+
 // Exists checks if there exists a device with the given filters.
 //
 // If the device does not exist, it returns false and nil error. If there is an
@@ -71,8 +84,105 @@ func (r *repository) UpdatePushToken(id, token string) error {
 	return r.db.Model(&models.Device{}).Where("id", id).Update("push_token", token).Error
 }
 
-func (r *repository) UpdateLastSeen(id string) error {
-	return r.db.Model(&models.Device{}).Where("id", id).Update("last_seen", time.Now()).Error
+// Update updates the fields of the device.
+func (r *repository) Update(device *models.Device, fields ...string) error {
+	return r.db.Model(&models.Device{}).Where("id", device.ID).Select(fields).Updates(device).Error
+}
+
+// Transfer assigns the device to another user. The within function is called
+// in the same transaction before the device is updated, so data linked to the
+// device by other modules moves along with it or not at all.
+func (r *repository) Transfer(deviceID, userID string, within func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if within != nil {
+			if err := within(tx); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.Device{}).Where("id", deviceID).Update("user_id", userID).Error
+	})
+}
+
+// UpdateLastSeen sets the last seen time of the device to now. It returns true
+// if the device was last seen before offlineSince, i.e. came back online.
+func (r *repository) UpdateLastSeen(id string, offlineSince time.Time) (bool, error) {
+	now := time.Now()
+
+	res := r.db.Model(&models.Device{}).
+		Where("id = ? AND last_seen >= ?", id, offlineSince).
+		Update("last_seen", now)
+	if res.Error != nil || res.RowsAffected > 0 {
+		return false, res.Error
+	}
+
+	res = r.db.Model(&models.Device{}).
+		Where("id = ? AND last_seen < ?", id, offlineSince).
+		Update("last_seen", now)
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// SelectLastSeen returns devices last seen in the [from, to) interval.
+func (r *repository) SelectLastSeen(ctx context.Context, from, to time.Time) ([]models.Device, error) {
+	devices := []models.Device{}
+
+	return devices, r.db.
+		WithContext(ctx).
+		Where("last_seen >= ? AND last_seen < ?", from, to).
+		Find(&devices).
+		Error
+}
+
+// CountOffline returns the total number of devices and the number of devices
+// last seen before offlineSince.
+func (r *repository) CountOffline(ctx context.Context, offlineSince time.Time) (total, offline int64, err error) {
+	row := r.db.
+		WithContext(ctx).
+		Model(&models.Device{}).
+		Select("COUNT(*), COALESCE(SUM(last_seen < ?), 0)", offlineSince).
+		Row()
+	err = row.Scan(&total, &offline)
+
+	return
+}
+
+// ReplaceSIMs replaces the SIM inventory of the device.
+func (r *repository) ReplaceSIMs(deviceID string, sims []SIM) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", deviceID).Delete(&SIM{}).Error; err != nil {
+			return err
+		}
+
+		if len(sims) == 0 {
+			return nil
+		}
+
+		return tx.Omit("Device").Create(&sims).Error
+	})
+}
+
+// SelectSIMs returns SIM cards of the devices ordered by slot.
+func (r *repository) SelectSIMs(deviceIDs ...string) ([]SIM, error) {
+	sims := []SIM{}
+
+	return sims, r.db.
+		Where("device_id IN ?", deviceIDs).
+		Order("device_id, slot").
+		Find(&sims).
+		Error
+}
+
+// SelectSIMsByMSISDN returns the user's SIM cards with the phone number on
+// enabled devices.
+func (r *repository) SelectSIMsByMSISDN(userID, msisdn string) ([]SIM, error) {
+	sims := []SIM{}
+
+	return sims, r.db.
+		Joins("Device").
+		Where("Device.user_id = ? AND Device.enabled = ? AND device_sims.msisdn = ?", userID, true, msisdn).
+		Find(&sims).
+		Error
 }
 
 func (r *repository) Remove(filter ...SelectFilter) error {
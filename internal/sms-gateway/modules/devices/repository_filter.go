@@ -1,6 +1,10 @@
 package devices
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type SelectFilter func(*selectFilter)
 
@@ -10,6 +14,8 @@ func WithID(id string) SelectFilter {
 	}
 }
 
+// WithToken selects the device by the auth token or by the previous one during
+// the grace period.
 func WithToken(token string) SelectFilter {
 	return func(f *selectFilter) {
 		f.token = &token
@@ -22,10 +28,26 @@ func WithUserID(userID string) SelectFilter {
 	}
 }
 
+// WithTag selects devices having the tag.
+func WithTag(tag string) SelectFilter {
+	return func(f *selectFilter) {
+		f.tag = &tag
+	}
+}
+
+// WithEnabled selects devices enabled for message routing.
+func WithEnabled() SelectFilter {
+	return func(f *selectFilter) {
+		f.enabled = true
+	}
+}
+
 type selectFilter struct {
-	id     *string
-	userID *string
-	token  *string
+	id      *string
+	userID  *string
+	token   *string
+	tag     *string
+	enabled bool
 }
 
 func newFilter(filters ...SelectFilter) *selectFilter {
@@ -45,10 +67,19 @@ func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
 		query = query.Where("id = ?", *f.id)
 	}
 	if f.token != nil {
-		query = query.Where("auth_token = ?", *f.token)
+		query = query.Where(
+			"(auth_token = ? OR (prev_auth_token = ? AND prev_auth_token_valid_until > ?))",
+			*f.token, *f.token, time.Now(),
+		)
 	}
 	if f.userID != nil {
 		query = query.Where("user_id = ?", *f.userID)
 	}
+	if f.tag != nil {
+		query = query.Where("JSON_CONTAINS(tags, JSON_ARRAY(?))", *f.tag)
+	}
+	if f.enabled {
+		query = query.Where("enabled = ?", true)
+	}
 	return query
 }
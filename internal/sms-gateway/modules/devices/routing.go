@@ -0,0 +1,38 @@
+package devices
+
+import (
+	"errors"
+	"math/rand/v2"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+)
+
+var ErrNoDevices = errors.New("no devices")
+
+// Route returns a random device with probability proportional to its weight.
+func Route(devices []models.Device) (models.Device, error) {
+	return RouteByKey(devices, rand.Uint64())
+}
+
+// RouteByKey returns a device for the key, so the same key gets the same device
+// and keys are spread according to device weights.
+func RouteByKey(devices []models.Device, key uint64) (models.Device, error) {
+	total := uint64(0)
+	for _, d := range devices {
+		total += uint64(max(d.Weight, 1))
+	}
+	if total == 0 {
+		return models.Device{}, ErrNoDevices
+	}
+
+	key %= total
+	for _, d := range devices {
+		weight := uint64(max(d.Weight, 1))
+		if key < weight {
+			return d, nil
+		}
+		key -= weight
+	}
+
+	return devices[len(devices)-1], nil
+}
@@ -0,0 +1,35 @@
+package devices
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+)
+
+func TestRouteByKey(t *testing.T) {
+	devices := []models.Device{
+		{ID: "a", Weight: 1},
+		{ID: "b", Weight: 3},
+		{ID: "c"},
+	}
+
+	counts := map[string]int{}
+	for key := range uint64(50) {
+		device, err := RouteByKey(devices, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[device.ID]++
+	}
+
+	// zero weight counts as 1, so keys are spread as 1:3:1
+	if counts["a"] != 10 || counts["b"] != 30 || counts["c"] != 10 {
+		t.Errorf("unexpected distribution: %v", counts)
+	}
+}
+
+func TestRouteByKey_Empty(t *testing.T) {
+	if _, err := RouteByKey(nil, 1); err != ErrNoDevices {
+		t.Errorf("RouteByKey() error = %v, want %v", err, ErrNoDevices)
+	}
+}
@@ -5,15 +5,23 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/capcom6/go-helpers/anys"
 	"github.com/capcom6/go-helpers/cache"
+	"github.com/capcom6/go-helpers/slices"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// tokenCacheTTL limits how long other instances use a cached device after it
+// is changed, e.g. its token is rotated or it's transferred to another user.
+const tokenCacheTTL = time.Minute
+
 type ServiceParams struct {
 	fx.In
 
@@ -32,6 +40,8 @@ type Service struct {
 	devices     *repository
 	tokensCache *cache.Cache[models.Device]
 
+	statusEvents chan StatusEvent
+
 	idGen db.IDGen
 
 	logger *zap.Logger
@@ -52,6 +62,12 @@ func (s *Service) Select(userID string, filter ...SelectFilter) ([]models.Device
 	return s.devices.Select(filter...)
 }
 
+// SelectAll returns a page of devices of all users that match the provided
+// filters. It is intended for administrative use.
+func (s *Service) SelectAll(limit, offset int, filter ...SelectFilter) ([]models.Device, error) {
+	return s.devices.SelectPage(limit, offset, filter...)
+}
+
 // Exists checks if there exists a device that matches the provided filters.
 //
 // If the device does not exist, it returns false and nil error. If there is an
@@ -78,8 +94,7 @@ func (s *Service) Get(userID string, filter ...SelectFilter) (models.Device, err
 // This method is used to retrieve a device by its auth token. If the device
 // does not exist, it returns ErrNotFound.
 func (s *Service) GetByToken(token string) (models.Device, error) {
-	hash := sha256.Sum256([]byte(token))
-	cacheKey := hex.EncodeToString(hash[:])
+	cacheKey := tokenCacheKey(token)
 
 	device, err := s.tokensCache.Get(cacheKey)
 	if err != nil {
@@ -88,7 +103,13 @@ func (s *Service) GetByToken(token string) (models.Device, error) {
 			return device, fmt.Errorf("can't get device: %w", err)
 		}
 
-		if err := s.tokensCache.Set(cacheKey, device); err != nil {
+		opts := []cache.Option{}
+		if device.AuthToken != token && device.PrevAuthTokenValidUntil != nil {
+			// previous token must not outlive the grace period in the cache
+			opts = append(opts, cache.WithValidUntil(*device.PrevAuthTokenValidUntil))
+		}
+
+		if err := s.tokensCache.Set(cacheKey, device, opts...); err != nil {
 			s.logger.Error("can't cache device", zap.Error(err))
 		}
 	}
@@ -96,12 +117,185 @@ func (s *Service) GetByToken(token string) (models.Device, error) {
 	return device, nil
 }
 
+// RotateToken replaces the auth token of the device. The previous token stays
+// valid during the grace period, if any. Tokens of other instances' caches are
+// valid until tokenCacheTTL expires.
+func (s *Service) RotateToken(userID, deviceID string, gracePeriod time.Duration) (models.Device, error) {
+	device, err := s.Get(userID, WithID(deviceID))
+	if err != nil {
+		return device, err
+	}
+
+	// cached tokens are invalidated after the update, so a concurrent request
+	// can't cache the old row again
+	previous := device
+
+	device.PrevAuthToken = nil
+	device.PrevAuthTokenValidUntil = nil
+	if gracePeriod > 0 {
+		device.PrevAuthToken = anys.AsPointer(device.AuthToken)
+		device.PrevAuthTokenValidUntil = anys.AsPointer(time.Now().Add(gracePeriod))
+	}
+	device.AuthToken = s.idGen()
+
+	if err := s.devices.Update(&device, "AuthToken", "PrevAuthToken", "PrevAuthTokenValidUntil"); err != nil {
+		return device, fmt.Errorf("can't update token: %w", err)
+	}
+
+	s.invalidateTokens(previous)
+
+	return device, nil
+}
+
+// Transfer moves the user's device to the target user. Data linked to the
+// device, e.g. messages, moves along with it. The within function, if any, is
+// called in the same transaction to move data stored by other modules.
+func (s *Service) Transfer(userID, deviceID, targetUserID string, within func(tx *gorm.DB) error) (models.Device, error) {
+	device, err := s.Get(userID, WithID(deviceID))
+	if err != nil {
+		return device, err
+	}
+
+	if err := s.devices.Transfer(device.ID, targetUserID, within); err != nil {
+		return device, fmt.Errorf("can't transfer device: %w", err)
+	}
+
+	s.invalidateTokens(device)
+	device.UserID = targetUserID
+
+	return device, nil
+}
+
 func (s *Service) UpdatePushToken(deviceId string, token string) error {
 	return s.devices.UpdatePushToken(deviceId, token)
 }
 
+// Update updates the user's device. Duplicate tags are removed.
+func (s *Service) Update(userID, deviceID string, update DeviceUpdate) (models.Device, error) {
+	device, err := s.Get(userID, WithID(deviceID))
+	if err != nil {
+		return device, err
+	}
+
+	fields := make([]string, 0, 4)
+	if update.Name != nil {
+		device.Name = update.Name
+		fields = append(fields, "Name")
+	}
+	if update.Tags != nil {
+		device.Tags = uniqueTags(*update.Tags)
+		fields = append(fields, "Tags")
+	}
+	if update.Enabled != nil {
+		device.Enabled = *update.Enabled
+		fields = append(fields, "Enabled")
+	}
+	if update.Weight != nil {
+		device.Weight = *update.Weight
+		fields = append(fields, "Weight")
+	}
+
+	if len(fields) == 0 {
+		return device, nil
+	}
+
+	if err := s.devices.Update(&device, fields...); err != nil {
+		return device, fmt.Errorf("can't update device: %w", err)
+	}
+
+	return device, nil
+}
+
+// Status returns the device status according to the time it was last seen.
+func (s *Service) Status(device models.Device) Status {
+	return s.config.status(device.LastSeen, time.Now())
+}
+
+// UpdateLastSeen sets the last seen time of the device to now. A status event
+// is emitted if the device was offline.
 func (s *Service) UpdateLastSeen(deviceId string) error {
-	return s.devices.UpdateLastSeen(deviceId)
+	cameBack, err := s.devices.UpdateLastSeen(deviceId, time.Now().Add(-s.config.OfflineAfter))
+	if err != nil || !cameBack {
+		return err
+	}
+
+	device, err := s.devices.Get(WithID(deviceId))
+	if err != nil {
+		return fmt.Errorf("can't get device: %w", err)
+	}
+
+	select {
+	case s.statusEvents <- StatusEvent{Device: device, Status: StatusOnline}:
+	default:
+		s.logger.Warn("status events queue is full", zap.String("device_id", deviceId))
+	}
+
+	return nil
+}
+
+// ReplaceSIMs replaces the SIM inventory reported by the device.
+func (s *Service) ReplaceSIMs(deviceID string, sims []SIMIn) error {
+	items := make([]SIM, 0, len(sims))
+	seen := make(map[uint8]struct{}, len(sims))
+	for _, sim := range sims {
+		if _, ok := seen[sim.Slot]; ok {
+			return fmt.Errorf("%w: duplicate slot %d", ErrInvalidSIM, sim.Slot)
+		}
+		seen[sim.Slot] = struct{}{}
+
+		items = append(items, SIM{
+			DeviceID: deviceID,
+			Slot:     sim.Slot,
+			Carrier:  sim.Carrier,
+			MSISDN:   normalizeMSISDN(sim.MSISDN, sim.Country),
+			Country:  strings.ToUpper(sim.Country),
+		})
+	}
+
+	if err := s.devices.ReplaceSIMs(deviceID, items); err != nil {
+		return fmt.Errorf("can't replace SIMs: %w", err)
+	}
+
+	return nil
+}
+
+// SelectSIMs returns SIM cards of the devices grouped by device ID.
+func (s *Service) SelectSIMs(deviceIDs ...string) (map[string][]SIMOut, error) {
+	result := make(map[string][]SIMOut, len(deviceIDs))
+	if len(deviceIDs) == 0 {
+		return result, nil
+	}
+
+	sims, err := s.devices.SelectSIMs(deviceIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("can't select SIMs: %w", err)
+	}
+
+	for _, sim := range sims {
+		result[sim.DeviceID] = append(result[sim.DeviceID], simToDTO(sim))
+	}
+
+	return result, nil
+}
+
+// GetBySenderNumber returns the user's device and the SIM card slot with the
+// phone number. A random device is returned if there are several of them.
+func (s *Service) GetBySenderNumber(userID, phoneNumber string) (models.Device, uint8, error) {
+	sims, err := s.devices.SelectSIMsByMSISDN(userID, normalizeMSISDN(phoneNumber, ""))
+	if err != nil {
+		return models.Device{}, 0, fmt.Errorf("can't select SIMs: %w", err)
+	}
+
+	if len(sims) == 0 {
+		return models.Device{}, 0, ErrNotFound
+	}
+
+	sim, err := slices.Random(sims)
+	if err != nil {
+		return models.Device{}, 0, err
+	}
+
+	return sim.Device, sim.Slot, nil
 }
 
 // Remove removes devices for a specific user that match the provided filters.
@@ -114,11 +308,28 @@ func (s *Service) Remove(userID string, filter ...SelectFilter) error {
 		return err
 	}
 
-	if err := s.tokensCache.Delete(device.AuthToken); err != nil {
-		s.logger.Error("can't invalidate token cache", zap.Error(err))
+	if err := s.devices.Remove(filter...); err != nil {
+		return err
+	}
+
+	s.invalidateTokens(device)
+
+	return nil
+}
+
+// invalidateTokens removes the current and the previous tokens of the device
+// from the cache.
+func (s *Service) invalidateTokens(device models.Device) {
+	tokens := []string{device.AuthToken}
+	if device.PrevAuthToken != nil {
+		tokens = append(tokens, *device.PrevAuthToken)
 	}
 
-	return s.devices.Remove(filter...)
+	for _, token := range tokens {
+		if err := s.tokensCache.Delete(tokenCacheKey(token)); err != nil {
+			s.logger.Error("can't invalidate token cache", zap.Error(err))
+		}
+	}
 }
 
 func (s *Service) Clean(ctx context.Context) error {
@@ -128,12 +339,34 @@ func (s *Service) Clean(ctx context.Context) error {
 	return err
 }
 
+func tokenCacheKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func uniqueTags(tags []string) []string {
+	unique := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		unique = append(unique, tag)
+	}
+
+	return unique
+}
+
 func NewService(params ServiceParams) *Service {
 	return &Service{
 		config:      params.Config,
 		devices:     params.Devices,
-		tokensCache: cache.New[models.Device](cache.Config{TTL: 10 * time.Minute}),
-		idGen:       params.IDGen,
-		logger:      params.Logger.Named("service"),
+		tokensCache: cache.New[models.Device](cache.Config{TTL: tokenCacheTTL}),
+
+		statusEvents: make(chan StatusEvent, 128),
+
+		idGen:  params.IDGen,
+		logger: params.Logger.Named("service"),
 	}
 }
@@ -0,0 +1,49 @@
+package devices
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"go.uber.org/fx"
+)
+
+type Status string
+
+const (
+	StatusOnline  Status = "online"
+	StatusStale   Status = "stale"
+	StatusOffline Status = "offline"
+)
+
+// StatusEvent is emitted when a device goes offline or comes back online.
+type StatusEvent struct {
+	Device models.Device
+	Status Status
+}
+
+// StatusHandler handles device status changes.
+type StatusHandler interface {
+	HandleStatus(ctx context.Context, event StatusEvent)
+}
+
+func AsStatusHandler(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(StatusHandler)),
+		fx.ResultTags(`group:"device-status-handlers"`),
+	)
+}
+
+// status classifies the device by the time it was last seen.
+func (c Config) status(lastSeen, now time.Time) Status {
+	idle := now.Sub(lastSeen)
+	switch {
+	case idle >= c.OfflineAfter:
+		return StatusOffline
+	case idle >= c.StaleAfter:
+		return StatusStale
+	}
+
+	return StatusOnline
+}
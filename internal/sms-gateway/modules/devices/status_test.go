@@ -0,0 +1,36 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_status(t *testing.T) {
+	config := Config{
+		StaleAfter:   5 * time.Minute,
+		OfflineAfter: time.Hour,
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		lastSeen time.Time
+		want     Status
+	}{
+		{name: "just seen", lastSeen: now, want: StatusOnline},
+		{name: "clock skew", lastSeen: now.Add(time.Minute), want: StatusOnline},
+		{name: "below stale", lastSeen: now.Add(-4 * time.Minute), want: StatusOnline},
+		{name: "stale", lastSeen: now.Add(-5 * time.Minute), want: StatusStale},
+		{name: "below offline", lastSeen: now.Add(-59 * time.Minute), want: StatusStale},
+		{name: "offline", lastSeen: now.Add(-time.Hour), want: StatusOffline},
+		{name: "never seen", lastSeen: time.Time{}, want: StatusOffline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.status(tt.lastSeen, now); got != tt.want {
+				t.Errorf("status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
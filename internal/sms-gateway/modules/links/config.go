@@ -0,0 +1,7 @@
+package links
+
+type Config struct {
+	// Public URL of the gateway short links are built on, shortening is
+	// disabled if empty
+	BaseURL string
+}
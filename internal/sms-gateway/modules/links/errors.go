@@ -0,0 +1,12 @@
+package links
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound = gorm.ErrRecordNotFound
+	ErrDisabled = errors.New("link shortening is disabled")
+)
@@ -0,0 +1,33 @@
+package links
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// Link is a short link of a message. Links of single-recipient messages are
+// attributed to the recipient.
+type Link struct {
+	ID          uint64     `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	Code        string     `gorm:"not null;type:char(10);uniqueIndex:unq_links_code"`
+	MessageID   uint64     `gorm:"not null;type:BIGINT UNSIGNED;index:idx_links_message"`
+	RecipientID *uint64    `gorm:"type:BIGINT UNSIGNED"`
+	URL         string     `gorm:"not null;type:varchar(2048)"`
+	Clicks      uint64     `gorm:"not null;type:BIGINT UNSIGNED;default:0"`
+	LastClickAt *time.Time `gorm:"type:datetime(3)"`
+
+	Message   models.Message           `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE"`
+	Recipient *models.MessageRecipient `gorm:"foreignKey:RecipientID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Link{}); err != nil {
+		return fmt.Errorf("links migration failed: %w", err)
+	}
+	return nil
+}
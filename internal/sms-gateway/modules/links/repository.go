@@ -0,0 +1,39 @@
+package links
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) GetByCode(code string) (Link, error) {
+	link := Link{}
+
+	return link, r.db.Where("code = ?", code).Take(&link).Error
+}
+
+func (r *repository) Click(id uint64, at time.Time) error {
+	return r.db.Model(&Link{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"clicks":        gorm.Expr("clicks + 1"),
+			"last_click_at": at,
+		}).
+		Error
+}
+
+func (r *repository) SelectByMessages(ids []uint64) ([]Link, error) {
+	links := []Link{}
+
+	return links, r.db.Where("message_id IN ?", ids).Order("id").Find(&links).Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
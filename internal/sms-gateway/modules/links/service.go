@@ -0,0 +1,95 @@
+package links
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Config Config
+
+	Links *repository
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	config Config
+
+	links *repository
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		config: params.Config,
+		links:  params.Links,
+		logger: params.Logger.Named("service"),
+	}
+}
+
+// Shorten replaces URLs in the text with short links. Returned links are not
+// stored, they must be inserted along with the message.
+func (s *Service) Shorten(text string) (string, []Link, error) {
+	if s.config.BaseURL == "" {
+		return text, nil, ErrDisabled
+	}
+
+	links := []Link{}
+	text, err := shorten(text, func(url string) (string, error) {
+		code, err := newCode()
+		if err != nil {
+			return "", err
+		}
+
+		links = append(links, Link{Code: code, URL: url})
+
+		return s.ShortURL(code), nil
+	})
+	if err != nil {
+		return text, nil, err
+	}
+
+	return text, links, nil
+}
+
+// ShortURL returns the public URL of the link with the code.
+func (s *Service) ShortURL(code string) string {
+	return strings.TrimRight(s.config.BaseURL, "/") + "/l/" + code
+}
+
+// Click records a click on the link and returns its target URL.
+func (s *Service) Click(code string) (string, error) {
+	link, err := s.links.GetByCode(code)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.links.Click(link.ID, time.Now()); err != nil {
+		// redirect is more important than stats
+		s.logger.Error("Can't record click", zap.Uint64("link_id", link.ID), zap.Error(err))
+	}
+
+	return link.URL, nil
+}
+
+// SelectByMessages returns links of the messages.
+func (s *Service) SelectByMessages(ids []uint64) ([]Link, error) {
+	if len(ids) == 0 {
+		return []Link{}, nil
+	}
+
+	links, err := s.links.SelectByMessages(ids)
+	if err != nil {
+		return nil, fmt.Errorf("can't select links: %w", err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,69 @@
+package links
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	codeLength   = 10
+	codeAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	maxURLLength = 2048
+)
+
+var urlRegexp = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+// HasURLs reports whether the text contains URLs to shorten.
+func HasURLs(text string) bool {
+	return urlRegexp.MatchString(text)
+}
+
+// shorten replaces URLs in the text with short links built by the short
+// function. The same URL is replaced with the same link.
+func shorten(text string, short func(url string) (string, error)) (string, error) {
+	links := map[string]string{}
+
+	var err error
+	result := urlRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		// punctuation after a URL most likely ends the sentence
+		url := strings.TrimRight(match, ".,;:!?')]}")
+		suffix := match[len(url):]
+		if len(url) > maxURLLength {
+			return match
+		}
+
+		link, ok := links[url]
+		if !ok {
+			if link, err = short(url); err != nil {
+				return match
+			}
+			links[url] = link
+		}
+
+		return link + suffix
+	})
+
+	return result, err
+}
+
+func newCode() (string, error) {
+	code := make([]byte, codeLength)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("can't generate code: %w", err)
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
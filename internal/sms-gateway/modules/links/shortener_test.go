@@ -0,0 +1,67 @@
+package links
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		want  string
+		count int
+	}{
+		{
+			name: "no urls",
+			text: "Hello, world!",
+			want: "Hello, world!",
+		},
+		{
+			name:  "trailing punctuation",
+			text:  "See https://example.com/offer?id=1.",
+			want:  "See L1.",
+			count: 1,
+		},
+		{
+			name:  "same url",
+			text:  "http://example.com and (http://example.com) and HTTPS://example.org/",
+			want:  "L1 and (L1) and L2",
+			count: 2,
+		},
+		{
+			name: "without scheme",
+			text: "Visit example.com",
+			want: "Visit example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			got, err := shorten(tt.text, func(url string) (string, error) {
+				count++
+				return "L" + strconv.Itoa(count), nil
+			})
+			if err != nil {
+				t.Fatalf("shorten() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("shorten() = %q, want %q", got, tt.want)
+			}
+			if count != tt.count {
+				t.Errorf("shorten() links = %d, want %d", count, tt.count)
+			}
+		})
+	}
+}
+
+func TestNewCode(t *testing.T) {
+	code, err := newCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != codeLength {
+		t.Errorf("newCode() = %q, want length %d", code, codeLength)
+	}
+}
@@ -0,0 +1,22 @@
+package lockout
+
+import "time"
+
+// Policy defines when and for how long a subject is locked out.
+type Policy struct {
+	// MaxAttempts is the number of failed attempts in the window that triggers
+	// the lockout, zero disables the protection
+	MaxAttempts int
+	// Window is the period failed attempts are counted in
+	Window time.Duration
+	// BaseLockout is the duration of the first lockout, every next one is
+	// twice as long
+	BaseLockout time.Duration
+	// MaxLockout limits the lockout duration
+	MaxLockout time.Duration
+}
+
+type Config struct {
+	Basic Policy
+	Code  Policy
+}
@@ -0,0 +1,60 @@
+package lockout
+
+import "time"
+
+// Kind is the authentication method attempts are tracked for.
+type Kind string
+
+const (
+	KindBasic Kind = "basic"
+	KindCode  Kind = "code"
+)
+
+const (
+	subjectLogin = "login"
+	subjectIP    = "ip"
+)
+
+// Key identifies the subject attempts are tracked for.
+type Key struct {
+	subject string
+	value   string
+}
+
+func LoginKey(login string) Key {
+	return Key{subject: subjectLogin, value: login}
+}
+
+func IPKey(ip string) Key {
+	return Key{subject: subjectIP, value: ip}
+}
+
+type entry struct {
+	failures    int
+	windowStart time.Time
+	lastFailure time.Time
+
+	// number of lockouts in a row, the next lockout is twice as long
+	level       int
+	lockedUntil time.Time
+}
+
+// lockoutDuration returns the duration of the lockout of the level starting
+// with zero.
+func (p Policy) lockoutDuration(level int) time.Duration {
+	d := p.BaseLockout
+	for range level {
+		if d >= p.MaxLockout/2 {
+			return p.MaxLockout
+		}
+		d *= 2
+	}
+
+	return min(d, p.MaxLockout)
+}
+
+// idle returns the time after the last failure the entry is forgotten along
+// with its lockout level.
+func (p Policy) idle() time.Duration {
+	return p.Window + p.MaxLockout
+}
@@ -0,0 +1,15 @@
+package lockout
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockedError is returned while the subject is locked out.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, locked until %s", e.Until.UTC().Format(time.RFC3339))
+}
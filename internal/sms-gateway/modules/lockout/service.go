@@ -0,0 +1,238 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// pruneInterval is the minimal interval between removals of idle entries
+	// on failed attempts
+	pruneInterval = time.Minute
+	// maxEntries limits the number of tracked keys of each kind, the least
+	// recently failed key is forgotten when the limit is reached
+	maxEntries = 100_000
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Config Config
+
+	Logger *zap.Logger
+}
+
+// Service tracks failed attempts in memory, so limits apply per instance.
+type Service struct {
+	policies   map[Kind]Policy
+	maxEntries int
+
+	mux      sync.Mutex
+	entries  map[Kind]map[Key]*entry
+	prunedAt time.Time
+
+	failuresCounter *prometheus.CounterVec
+	lockoutsCounter *prometheus.CounterVec
+
+	logger *zap.Logger
+	audit  *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		policies: map[Kind]Policy{
+			KindBasic: params.Config.Basic,
+			KindCode:  params.Config.Code,
+		},
+		maxEntries: maxEntries,
+
+		entries: map[Kind]map[Key]*entry{
+			KindBasic: {},
+			KindCode:  {},
+		},
+
+		failuresCounter: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sms",
+			Subsystem: "auth",
+			Name:      "failed_attempts_total",
+			Help:      "Total number of failed authentication attempts by method",
+		}, []string{"kind"}),
+		lockoutsCounter: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sms",
+			Subsystem: "auth",
+			Name:      "lockouts_total",
+			Help:      "Total number of authentication lockouts by method and subject",
+		}, []string{"kind", "subject"}),
+
+		logger: params.Logger.Named("service"),
+		audit:  params.Logger.Named("audit"),
+	}
+}
+
+// Check returns LockedError if any of the keys is locked out.
+func (s *Service) Check(kind Kind, keys ...Key) error {
+	if s.policies[kind].MaxAttempts <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var until time.Time
+	for _, key := range keys {
+		if e, ok := s.entries[kind][key]; ok && e.lockedUntil.After(now) && e.lockedUntil.After(until) {
+			until = e.lockedUntil
+		}
+	}
+
+	if until.IsZero() {
+		return nil
+	}
+
+	return LockedError{Until: until}
+}
+
+// Fail records a failed attempt for each of the keys. If the attempt triggers
+// a lockout of any key, LockedError is returned.
+func (s *Service) Fail(kind Kind, keys ...Key) error {
+	s.failuresCounter.WithLabelValues(string(kind)).Inc()
+
+	policy := s.policies[kind]
+	if policy.MaxAttempts <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if now.Sub(s.prunedAt) > pruneInterval {
+		s.prune(now)
+	}
+
+	var until time.Time
+	for _, key := range keys {
+		e, ok := s.entries[kind][key]
+		if !ok {
+			if len(s.entries[kind]) >= s.maxEntries {
+				s.evict(kind)
+			}
+			e = &entry{windowStart: now}
+			s.entries[kind][key] = e
+		}
+
+		if now.Sub(e.lastFailure) > policy.idle() {
+			e.level = 0
+		}
+		if now.Sub(e.windowStart) > policy.Window {
+			e.failures = 0
+			e.windowStart = now
+		}
+
+		e.failures++
+		e.lastFailure = now
+
+		if e.failures < policy.MaxAttempts {
+			continue
+		}
+
+		duration := policy.lockoutDuration(e.level)
+		e.lockedUntil = now.Add(duration)
+		e.level++
+		e.failures = 0
+		e.windowStart = now
+
+		if e.lockedUntil.After(until) {
+			until = e.lockedUntil
+		}
+
+		s.lockoutsCounter.WithLabelValues(string(kind), key.subject).Inc()
+		s.audit.Warn("Authentication lockout",
+			zap.String("event", "auth.lockout"),
+			zap.String("kind", string(kind)),
+			zap.String(key.subject, key.value),
+			zap.Int("level", e.level),
+			zap.Duration("duration", duration),
+			zap.Time("until", e.lockedUntil),
+		)
+	}
+
+	if until.IsZero() {
+		return nil
+	}
+
+	return LockedError{Until: until}
+}
+
+// Succeed resets failed attempts of the keys. Lockout levels are kept until
+// the keys are idle.
+func (s *Service) Succeed(kind Kind, keys ...Key) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, key := range keys {
+		if e, ok := s.entries[kind][key]; ok {
+			e.failures = 0
+		}
+	}
+}
+
+// Clean removes idle entries.
+func (s *Service) Clean(_ context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	removed := s.prune(time.Now())
+
+	s.logger.Info("Cleaned attempts", zap.Int("count", removed))
+
+	return nil
+}
+
+// prune removes idle entries and returns their number. The caller must hold
+// the lock.
+func (s *Service) prune(now time.Time) int {
+	s.prunedAt = now
+
+	removed := 0
+	for kind, entries := range s.entries {
+		idle := s.policies[kind].idle()
+		for key, e := range entries {
+			if e.lockedUntil.Before(now) && now.Sub(e.lastFailure) > idle {
+				delete(entries, key)
+				removed++
+			}
+		}
+	}
+
+	return removed
+}
+
+// evict removes the least recently failed entry of the kind. The caller must
+// hold the lock.
+func (s *Service) evict(kind Kind) {
+	var (
+		oldest Key
+		found  bool
+		last   time.Time
+	)
+	for key, e := range s.entries[kind] {
+		if !found || e.lastFailure.Before(last) {
+			oldest, last, found = key, e.lastFailure, true
+		}
+	}
+
+	if found {
+		delete(s.entries[kind], oldest)
+		s.logger.Warn("Too many tracked keys, the least recent one is forgotten", zap.String("kind", string(kind)))
+	}
+}
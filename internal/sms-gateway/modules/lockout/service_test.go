@@ -0,0 +1,206 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var testPolicy = Policy{
+	MaxAttempts: 3,
+	Window:      time.Minute,
+	BaseLockout: time.Minute,
+	MaxLockout:  time.Hour,
+}
+
+// newTestService creates the service with unregistered metrics, so it can be
+// created more than once.
+func newTestService(policy Policy) *Service {
+	return &Service{
+		policies: map[Kind]Policy{
+			KindBasic: policy,
+			KindCode:  policy,
+		},
+		maxEntries: maxEntries,
+		entries: map[Kind]map[Key]*entry{
+			KindBasic: {},
+			KindCode:  {},
+		},
+		failuresCounter: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "failures"}, []string{"kind"}),
+		lockoutsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lockouts"}, []string{"kind", "subject"}),
+		logger:          zap.NewNop(),
+		audit:           zap.NewNop(),
+	}
+}
+
+// expire moves the lockout of the key to the past as if it has ended.
+func expire(s *Service, kind Kind, key Key) {
+	e := s.entries[kind][key]
+	e.lockedUntil = time.Now().Add(-time.Second)
+}
+
+func lockoutFor(t *testing.T, err error) time.Duration {
+	t.Helper()
+
+	var errLocked LockedError
+	if !errors.As(err, &errLocked) {
+		t.Fatalf("error = %v, want LockedError", err)
+	}
+	return time.Until(errLocked.Until)
+}
+
+func TestPolicy_lockoutDuration(t *testing.T) {
+	want := []time.Duration{
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		8 * time.Minute,
+		16 * time.Minute,
+		32 * time.Minute,
+		time.Hour,
+		time.Hour,
+	}
+	for level, w := range want {
+		if got := testPolicy.lockoutDuration(level); got != w {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", level, got, w)
+		}
+	}
+
+	if got := testPolicy.lockoutDuration(1000); got != time.Hour {
+		t.Errorf("lockoutDuration(1000) = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestService_ExponentialLockout(t *testing.T) {
+	s := newTestService(testPolicy)
+	login, ip := LoginKey("user"), IPKey("127.0.0.1")
+
+	for i := 0; i < testPolicy.MaxAttempts-1; i++ {
+		if err := s.Fail(KindBasic, login, ip); err != nil {
+			t.Fatalf("Fail() #%d error = %v, want nil", i, err)
+		}
+	}
+	if err := s.Check(KindBasic, login, ip); err != nil {
+		t.Fatalf("Check() error = %v, want nil", err)
+	}
+
+	d := lockoutFor(t, s.Fail(KindBasic, login, ip))
+	if d <= 0 || d > time.Minute {
+		t.Fatalf("first lockout = %v, want up to %v", d, time.Minute)
+	}
+
+	// any of the keys is enough
+	lockoutFor(t, s.Check(KindBasic, LoginKey("other"), ip))
+	lockoutFor(t, s.Check(KindBasic, login))
+	if err := s.Check(KindCode, ip); err != nil {
+		t.Fatalf("Check() of another kind error = %v, want nil", err)
+	}
+
+	expire(s, KindBasic, login)
+	expire(s, KindBasic, ip)
+	if err := s.Check(KindBasic, login, ip); err != nil {
+		t.Fatalf("Check() after lockout error = %v, want nil", err)
+	}
+
+	for i := 0; i < testPolicy.MaxAttempts-1; i++ {
+		if err := s.Fail(KindBasic, login, ip); err != nil {
+			t.Fatalf("Fail() #%d error = %v, want nil", i, err)
+		}
+	}
+	d = lockoutFor(t, s.Fail(KindBasic, login, ip))
+	if d <= time.Minute || d > 2*time.Minute {
+		t.Fatalf("second lockout = %v, want (%v, %v]", d, time.Minute, 2*time.Minute)
+	}
+}
+
+func TestService_Succeed(t *testing.T) {
+	s := newTestService(testPolicy)
+	login := LoginKey("user")
+
+	for i := 0; i < 2*testPolicy.MaxAttempts; i++ {
+		if i%(testPolicy.MaxAttempts-1) == 0 {
+			s.Succeed(KindBasic, login)
+		}
+		if err := s.Fail(KindBasic, login); err != nil {
+			t.Fatalf("Fail() #%d error = %v, want nil", i, err)
+		}
+	}
+}
+
+func TestService_Disabled(t *testing.T) {
+	s := newTestService(Policy{})
+
+	for i := 0; i < 100; i++ {
+		if err := s.Fail(KindCode, IPKey("127.0.0.1")); err != nil {
+			t.Fatalf("Fail() error = %v, want nil", err)
+		}
+	}
+	if err := s.Check(KindCode, IPKey("127.0.0.1")); err != nil {
+		t.Fatalf("Check() error = %v, want nil", err)
+	}
+}
+
+func TestService_Clean(t *testing.T) {
+	s := newTestService(testPolicy)
+	idle, active := IPKey("10.0.0.1"), IPKey("10.0.0.2")
+
+	_ = s.Fail(KindCode, idle)
+	_ = s.Fail(KindCode, active)
+	s.entries[KindCode][idle].lastFailure = time.Now().Add(-testPolicy.idle() - time.Second)
+
+	if err := s.Clean(context.Background()); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+
+	if _, ok := s.entries[KindCode][idle]; ok {
+		t.Error("idle entry is not removed")
+	}
+	if _, ok := s.entries[KindCode][active]; !ok {
+		t.Error("active entry is removed")
+	}
+}
+
+func TestService_PruneOnFail(t *testing.T) {
+	s := newTestService(testPolicy)
+	idle, active := IPKey("10.0.0.1"), IPKey("10.0.0.2")
+
+	_ = s.Fail(KindCode, idle)
+	s.entries[KindCode][idle].lastFailure = time.Now().Add(-testPolicy.idle() - time.Second)
+
+	// pruned recently
+	_ = s.Fail(KindCode, active)
+	if _, ok := s.entries[KindCode][idle]; !ok {
+		t.Fatal("idle entry is removed before the prune interval")
+	}
+
+	s.prunedAt = time.Now().Add(-pruneInterval - time.Second)
+	_ = s.Fail(KindCode, active)
+	if _, ok := s.entries[KindCode][idle]; ok {
+		t.Error("idle entry is not removed")
+	}
+}
+
+func TestService_MaxEntries(t *testing.T) {
+	s := newTestService(testPolicy)
+	s.maxEntries = 2
+	first, second, third := IPKey("10.0.0.1"), IPKey("10.0.0.2"), IPKey("10.0.0.3")
+
+	_ = s.Fail(KindCode, first)
+	_ = s.Fail(KindCode, second)
+	s.entries[KindCode][first].lastFailure = time.Now().Add(-time.Second)
+	_ = s.Fail(KindCode, third)
+
+	if len(s.entries[KindCode]) != 2 {
+		t.Fatalf("entries = %d, want 2", len(s.entries[KindCode]))
+	}
+	if _, ok := s.entries[KindCode][first]; ok {
+		t.Error("least recently failed entry is not evicted")
+	}
+	if _, ok := s.entries[KindCode][third]; !ok {
+		t.Error("new entry is not added")
+	}
+}
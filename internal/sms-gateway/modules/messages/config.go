@@ -2,6 +2,23 @@ package messages
 
 import "time"
 
+type SchedulingPolicy string
+
+const (
+	// SchedulingPolicyStrict selects messages with the highest priority first,
+	// newest first within the same priority.
+	SchedulingPolicyStrict SchedulingPolicy = "strict"
+	// SchedulingPolicyFIFO selects messages with the highest priority first,
+	// oldest first within the same priority.
+	SchedulingPolicyFIFO SchedulingPolicy = "fifo"
+	// SchedulingPolicyAging selects messages by effective priority, which grows
+	// by one for every AgingInterval the message has been waiting.
+	SchedulingPolicyAging SchedulingPolicy = "aging"
+)
+
 type Config struct {
 	ProcessedLifetime time.Duration
+
+	SchedulingPolicy SchedulingPolicy
+	AgingInterval    time.Duration
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
-	"github.com/capcom6/go-helpers/slices"
 )
 
 func messageToDomain(input models.Message) MessageOut {
@@ -20,18 +19,34 @@ func messageToDomain(input models.Message) MessageOut {
 		MessageIn: MessageIn{
 			ID:                 input.ExtID,
 			Message:            input.Message,
-			PhoneNumbers:       slices.Map(input.Recipients, recipientToDomain),
+			PhoneNumbers:       activeRecipients(input.Recipients),
 			IsEncrypted:        input.IsEncrypted,
 			SimNumber:          input.SimNumber,
 			WithDeliveryReport: &input.WithDeliveryReport,
 			TTL:                ttl,
 			ValidUntil:         input.ValidUntil,
 			Priority:           smsgateway.MessagePriority(input.Priority),
+
+			Metadata: input.Metadata,
+			Tags:     input.Tags,
 		},
 		CreatedAt: input.CreatedAt,
 	}
 }
 
+// activeRecipients returns phone numbers the message should be sent to. Only
+// suppressed recipients are failed before sending.
+func activeRecipients(input []models.MessageRecipient) []string {
+	output := make([]string, 0, len(input))
+	for _, v := range input {
+		if v.State != models.ProcessingStateFailed {
+			output = append(output, recipientToDomain(v))
+		}
+	}
+
+	return output
+}
+
 func recipientToDomain(input models.MessageRecipient) string {
 	return input.PhoneNumber
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
 )
 
 type MessageIn struct {
@@ -17,6 +18,17 @@ type MessageIn struct {
 	TTL                *uint64
 	ValidUntil         *time.Time
 	Priority           smsgateway.MessagePriority
+
+	Metadata map[string]string
+	Tags     []string
+
+	// Sending window in recipients' local time, the user's default window is
+	// used if not set
+	SendingWindow *SendingWindow
+	// Transactional messages are sent regardless of sending windows
+	IsTransactional bool
+	// Replace URLs with tracked short links
+	ShortenLinks bool
 }
 
 type MessageOut struct {
@@ -24,3 +36,77 @@ type MessageOut struct {
 
 	CreatedAt time.Time
 }
+
+// MessageStateOut is a message state with details not covered by
+// smsgateway.MessageState.
+type MessageStateOut struct {
+	smsgateway.MessageState
+
+	// Recipients states
+	Recipients []RecipientStateOut `json:"recipients"`
+
+	// ID of the message this one retries
+	RetryOf *string `json:"retryOf,omitempty" example:"PyDmBQZZXYmyxMwED8Fzy"`
+	// IDs of the messages retrying this one
+	Retries []string `json:"retries,omitempty" example:"PyDmBQZZXYmyxMwED8Fzy"`
+
+	// Custom metadata
+	Metadata map[string]string `json:"metadata,omitempty" example:"orderId:12345"`
+	// Tags
+	Tags []string `json:"tags,omitempty" example:"orders"`
+
+	// Time the message is held until to be sent in the sending window
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty" example:"2020-01-01T09:00:00Z"`
+
+	// Short links with click stats
+	Links []LinkOut `json:"links,omitempty"`
+}
+
+// MobileMessage is a message for sending by device with details not covered
+// by smsgateway.MobileMessage.
+type MobileMessage struct {
+	smsgateway.MobileMessage
+
+	// Custom metadata
+	Metadata map[string]string `json:"metadata,omitempty" example:"orderId:12345"`
+	// Tags
+	Tags []string `json:"tags,omitempty" example:"orders"`
+}
+
+type RecipientStateOut struct {
+	smsgateway.RecipientState
+
+	// History of recipient states
+	States []RecipientStateHistoryItem `json:"states"`
+
+	// Clicks on the message short links, counted per recipient for
+	// single-recipient messages only
+	Clicks uint64 `json:"clicks,omitempty" example:"1"`
+}
+
+type RecipientStateHistoryItem struct {
+	// State
+	State smsgateway.ProcessingState `json:"state" example:"Delivered"`
+	// Error (for `Failed` state)
+	Error *string `json:"error,omitempty" example:"timeout"`
+	// Time of state change
+	UpdatedAt time.Time `json:"updatedAt" example:"2020-01-01T00:00:00Z"`
+}
+
+type LinkOut struct {
+	// Original URL
+	URL string `json:"url" example:"https://example.com/offer"`
+	// Short URL
+	ShortURL string `json:"shortUrl" example:"https://sms.example.com/l/0a1b2c3d4e"`
+	// Number of clicks
+	Clicks uint64 `json:"clicks" example:"1"`
+	// Time of the last click
+	LastClickAt *time.Time `json:"lastClickAt,omitempty" example:"2020-01-01T00:00:00Z"`
+}
+
+type CountsOut struct {
+	// Total number of messages
+	Total int64 `json:"total" example:"10"`
+	// Number of messages by state
+	States map[models.ProcessingState]int64 `json:"states" example:"Sent:10"`
+}
@@ -0,0 +1,27 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"go.uber.org/fx"
+)
+
+// StateEvent is emitted when a device reports a message state.
+type StateEvent struct {
+	Device models.Device
+	State  MessageStateOut
+}
+
+// StateHandler handles message states reported by devices.
+type StateHandler interface {
+	HandleMessageState(ctx context.Context, event StateEvent)
+}
+
+func AsStateHandler(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(StateHandler)),
+		fx.ResultTags(`group:"message-state-handlers"`),
+	)
+}
@@ -30,4 +30,5 @@ var Module = fx.Module(
 	}),
 	fx.Provide(newRepository),
 	fx.Provide(NewHashingTask, fx.Private),
+	fx.Provide(NewScheduleTask, fx.Private),
 )
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -19,12 +21,15 @@ var ErrMessageAlreadyExists = errors.New("duplicate id")
 
 type repository struct {
 	db *gorm.DB
+
+	pendingOrder clause.OrderBy
 }
 
 func (r *repository) SelectPending(deviceID string) (messages []models.Message, err error) {
 	err = r.db.
 		Where("device_id = ? AND state = ?", deviceID, models.ProcessingStatePending).
-		Order("priority DESC, id DESC").
+		Where("schedule_at IS NULL OR schedule_at <= ?", time.Now()).
+		Clauses(r.pendingOrder).
 		Limit(100).
 		Preload("Recipients").
 		Find(&messages).
@@ -33,13 +38,26 @@ func (r *repository) SelectPending(deviceID string) (messages []models.Message,
 	return
 }
 
-func (r *repository) Get(ID string, filter MessagesSelectFilter, options ...MessagesSelectOptions) (message models.Message, err error) {
-	query := r.db.Model(&message).
-		Where("ext_id = ?", ID)
+// SelectDueDevices returns devices with pending messages held until a time in
+// the (from, to] range.
+func (r *repository) SelectDueDevices(from, to time.Time) (devices []models.Device, err error) {
+	due := r.db.Model(&models.Message{}).
+		Select("device_id").
+		Where("state = ? AND schedule_at > ? AND schedule_at <= ?", models.ProcessingStatePending, from, to)
 
-	if filter.DeviceID != "" {
-		query = query.Where("device_id = ?", filter.DeviceID)
-	}
+	err = r.db.
+		Where("id IN (?)", due).
+		Find(&devices).
+		Error
+
+	return
+}
+
+func (r *repository) Get(ID string, filter MessagesSelectFilter, options ...MessagesSelectOptions) (message models.Message, err error) {
+	query := filter.apply(
+		r.db.Model(&message).
+			Where("messages.ext_id = ?", ID),
+	)
 
 	if len(options) > 0 {
 		if options[0].WithRecipients {
@@ -50,6 +68,20 @@ func (r *repository) Get(ID string, filter MessagesSelectFilter, options ...Mess
 		}
 		if options[0].WithStates {
 			query = query.Preload("States")
+			if options[0].WithRecipients {
+				query = query.Preload("Recipients.States", func(db *gorm.DB) *gorm.DB {
+					return db.Order("updated_at, id")
+				})
+			}
+		}
+		if options[0].WithRetries {
+			query = query.
+				Preload("RetryOf", func(db *gorm.DB) *gorm.DB {
+					return db.Select("id", "ext_id")
+				}).
+				Preload("Retries", func(db *gorm.DB) *gorm.DB {
+					return db.Select("id", "ext_id", "retry_of_id").Order("id")
+				})
 		}
 	}
 
@@ -58,8 +90,43 @@ func (r *repository) Get(ID string, filter MessagesSelectFilter, options ...Mess
 	return
 }
 
-func (r *repository) Insert(message *models.Message) error {
-	err := r.db.Omit("Device").Create(message).Error
+// Select returns messages matching the filter, newest first.
+func (r *repository) Select(filter MessagesSelectFilter, limit, offset int, options ...MessagesSelectOptions) ([]models.Message, error) {
+	messages := []models.Message{}
+
+	query := filter.apply(r.db.Model(&models.Message{})).
+		Order("messages.id DESC").
+		Limit(limit).
+		Offset(offset)
+
+	if len(options) > 0 && options[0].WithRecipients {
+		query = query.Preload("Recipients")
+	}
+
+	return messages, query.Find(&messages).Error
+}
+
+// Insert inserts the message along with its short links. Links of a
+// single-recipient message are attributed to the recipient.
+func (r *repository) Insert(message *models.Message, shortLinks ...links.Link) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Device").Create(message).Error; err != nil {
+			return err
+		}
+
+		if len(shortLinks) == 0 {
+			return nil
+		}
+
+		for i := range shortLinks {
+			shortLinks[i].MessageID = message.ID
+			if len(message.Recipients) == 1 {
+				shortLinks[i].RecipientID = &message.Recipients[0].ID
+			}
+		}
+
+		return tx.Omit("Message", "Recipient").Create(&shortLinks).Error
+	})
 	if err == nil {
 		return nil
 	}
@@ -86,9 +153,34 @@ func (r *repository) UpdateState(message *models.Message) error {
 		}
 
 		for _, v := range message.Recipients {
-			if err := tx.Model(&v).Where("message_id = ?", message.ID).Select("State", "Error").Updates(&v).Error; err != nil {
+			recipient := tx.Model(&models.MessageRecipient{}).
+				Where("message_id = ? AND phone_number = ?", message.ID, v.PhoneNumber).
+				Session(&gorm.Session{})
+
+			if err := recipient.Select("State", "Error").Updates(&v).Error; err != nil {
 				return err
 			}
+
+			if len(v.States) == 0 {
+				continue
+			}
+
+			ids := []uint64{}
+			if err := recipient.Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+
+			for _, state := range v.States {
+				state.RecipientID = ids[0]
+				if err := tx.Clauses(clause.OnConflict{
+					DoNothing: true,
+				}).Create(&state).Error; err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -122,6 +214,33 @@ func (r *repository) HashProcessed(ids []uint64) error {
 	})
 }
 
+// CountByState returns the number of messages of the user's devices by state.
+func (r *repository) CountByState(userID string) (map[models.ProcessingState]int64, error) {
+	rows := []struct {
+		State models.ProcessingState
+		Count int64
+	}{}
+
+	err := r.db.
+		Model(&models.Message{}).
+		Select("messages.state, COUNT(*) AS count").
+		Joins("JOIN devices ON devices.id = messages.device_id").
+		Where("devices.user_id = ?", userID).
+		Group("messages.state").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.ProcessingState]int64, len(rows))
+	for _, row := range rows {
+		counts[row.State] = row.Count
+	}
+
+	return counts, nil
+}
+
 // removeProcessed removes messages older than the given time that are not in
 // the Pending state.
 //
@@ -136,8 +255,39 @@ func (r *repository) removeProcessed(ctx context.Context, until time.Time) (int6
 	return res.RowsAffected, res.Error
 }
 
-func newRepository(db *gorm.DB) *repository {
+// newPendingOrder builds the order in which pending messages are handed out to
+// devices according to the scheduling policy.
+func newPendingOrder(policy SchedulingPolicy, agingInterval time.Duration) (clause.OrderBy, error) {
+	switch policy {
+	case SchedulingPolicyStrict, "":
+		return clause.OrderBy{Expression: clause.Expr{SQL: "`priority` DESC, `id` DESC"}}, nil
+	case SchedulingPolicyFIFO:
+		return clause.OrderBy{Expression: clause.Expr{SQL: "`priority` DESC, `id` ASC"}}, nil
+	case SchedulingPolicyAging:
+		if agingInterval < time.Second {
+			return clause.OrderBy{}, fmt.Errorf("aging interval must be at least 1s, got %s", agingInterval)
+		}
+
+		return clause.OrderBy{
+			Expression: clause.Expr{
+				SQL:  "`priority` + FLOOR(TIMESTAMPDIFF(SECOND, `created_at`, NOW(3)) / ?) DESC, `id` ASC",
+				Vars: []any{int64(agingInterval.Seconds())},
+			},
+		}, nil
+	}
+
+	return clause.OrderBy{}, fmt.Errorf("unknown scheduling policy: %s", policy)
+}
+
+func newRepository(db *gorm.DB, config Config) (*repository, error) {
+	pendingOrder, err := newPendingOrder(config.SchedulingPolicy, config.AgingInterval)
+	if err != nil {
+		return nil, err
+	}
+
 	return &repository{
 		db: db,
-	}
+
+		pendingOrder: pendingOrder,
+	}, nil
 }
@@ -1,11 +1,54 @@
 package messages
 
+import (
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
 type MessagesSelectFilter struct {
 	DeviceID string
+
+	UserID string
+	State  models.ProcessingState
+	// Messages must have all the tags
+	Tags []string
+	// Messages must have all the metadata keys with the same values
+	Metadata map[string]string
+	Since    *time.Time
+	Until    *time.Time
 }
 
 type MessagesSelectOptions struct {
 	WithRecipients bool
 	WithDevice     bool
 	WithStates     bool
+	WithRetries    bool
+}
+
+func (f MessagesSelectFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.DeviceID != "" {
+		query = query.Where("messages.device_id = ?", f.DeviceID)
+	}
+	if f.UserID != "" {
+		query = query.Where("messages.device_id IN (SELECT id FROM devices WHERE user_id = ?)", f.UserID)
+	}
+	if f.State != "" {
+		query = query.Where("messages.state = ?", f.State)
+	}
+	for _, tag := range f.Tags {
+		query = query.Where("JSON_CONTAINS(messages.tags, JSON_ARRAY(?))", tag)
+	}
+	for key, value := range f.Metadata {
+		query = query.Where("JSON_CONTAINS(messages.metadata, JSON_OBJECT(?, ?))", key, value)
+	}
+	if f.Since != nil {
+		query = query.Where("messages.created_at >= ?", *f.Since)
+	}
+	if f.Until != nil {
+		query = query.Where("messages.created_at < ?", *f.Until)
+	}
+
+	return query
 }
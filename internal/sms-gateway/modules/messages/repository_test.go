@@ -0,0 +1,81 @@
+package messages
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestNewPendingOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        SchedulingPolicy
+		agingInterval time.Duration
+		expectedSQL   string
+		expectedVars  []any
+		expectError   bool
+	}{
+		{
+			name:        "Default policy",
+			policy:      "",
+			expectedSQL: "`priority` DESC, `id` DESC",
+		},
+		{
+			name:        "Strict policy",
+			policy:      SchedulingPolicyStrict,
+			expectedSQL: "`priority` DESC, `id` DESC",
+		},
+		{
+			name:        "FIFO policy",
+			policy:      SchedulingPolicyFIFO,
+			expectedSQL: "`priority` DESC, `id` ASC",
+		},
+		{
+			name:          "Aging policy",
+			policy:        SchedulingPolicyAging,
+			agingInterval: time.Minute,
+			expectedSQL:   "`priority` + FLOOR(TIMESTAMPDIFF(SECOND, `created_at`, NOW(3)) / ?) DESC, `id` ASC",
+			expectedVars:  []any{int64(60)},
+		},
+		{
+			name:          "Aging policy without interval",
+			policy:        SchedulingPolicyAging,
+			agingInterval: 0,
+			expectError:   true,
+		},
+		{
+			name:        "Unknown policy",
+			policy:      "random",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := newPendingOrder(tt.policy, tt.agingInterval)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expr, ok := order.Expression.(clause.Expr)
+			if !ok {
+				t.Fatalf("Expected clause.Expr, got %T", order.Expression)
+			}
+			if expr.SQL != tt.expectedSQL {
+				t.Errorf("Expected %s, got %s", tt.expectedSQL, expr.SQL)
+			}
+			if len(tt.expectedVars) > 0 && !reflect.DeepEqual(expr.Vars, tt.expectedVars) {
+				t.Errorf("Expected %v, got %v", tt.expectedVars, expr.Vars)
+			}
+		})
+	}
+}
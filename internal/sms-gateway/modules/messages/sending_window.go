@@ -0,0 +1,143 @@
+package messages
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+const unknownTimezone = "Etc/Unknown"
+
+// SendingWindow is a time range in the recipient's local time when messages
+// can be sent
+type SendingWindow struct {
+	// Start of the window in `HH:MM` format
+	Start string `json:"start" validate:"required,datetime=15:04" example:"09:00"`
+	// End of the window in `HH:MM` format, can be less than start for overnight windows
+	End string `json:"end" validate:"required,datetime=15:04" example:"21:00"`
+}
+
+// Next returns the earliest time not before now when the window is open in
+// all the locations. If there is no such time, it returns the latest of the
+// window starts, so the message is sent in the window of at least one
+// location.
+func (w SendingWindow) Next(now time.Time, locations []*time.Location) (time.Time, error) {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return now, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return now, fmt.Errorf("invalid end: %w", err)
+	}
+
+	if start == end || len(locations) == 0 {
+		return now, nil
+	}
+
+	isOpen := func(t time.Time) bool {
+		for _, loc := range locations {
+			local := t.In(loc)
+			clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+			open := start <= clock && clock < end
+			if start > end {
+				open = clock >= start || clock < end
+			}
+			if !open {
+				return false
+			}
+		}
+		return true
+	}
+
+	// the common window, if any, opens either now or at a window start of one
+	// of the locations
+	candidates := []time.Time{now}
+	for _, loc := range locations {
+		local := now.In(loc)
+		next := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).Add(start)
+		if next.Before(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		candidates = append(candidates, next, next.AddDate(0, 0, 1))
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	for _, t := range candidates {
+		if isOpen(t) {
+			return t, nil
+		}
+	}
+
+	latest := now
+	for _, t := range candidates[1:] {
+		if t.Sub(now) < 24*time.Hour && t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+var locationsCache sync.Map
+
+// recipientsLocations estimates the time zones of the phone numbers by their
+// country and area codes. Numbers with unknown time zone are skipped.
+func recipientsLocations(phoneNumbers []string) []*time.Location {
+	seen := map[string]struct{}{}
+	locations := []*time.Location{}
+
+	for _, v := range phoneNumbers {
+		phone, err := phonenumbers.Parse(v, "RU")
+		if err != nil {
+			continue
+		}
+
+		timezones, err := phonenumbers.GetTimezonesForNumber(phone)
+		if err != nil {
+			continue
+		}
+
+		for _, tz := range timezones {
+			if _, ok := seen[tz]; ok || tz == unknownTimezone {
+				continue
+			}
+			seen[tz] = struct{}{}
+
+			loc, err := loadLocation(tz)
+			if err != nil {
+				continue
+			}
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locationsCache.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locationsCache.Store(name, loc)
+
+	return loc, nil
+}
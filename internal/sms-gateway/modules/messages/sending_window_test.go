@@ -0,0 +1,119 @@
+package messages
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendingWindow_Next(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip(err)
+	}
+	vladivostok, err := time.LoadLocation("Asia/Vladivostok")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 2025-01-01 12:00 in Moscow, 19:00 in Vladivostok
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		window    SendingWindow
+		locations []*time.Location
+		want      time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "open",
+			window:    SendingWindow{Start: "09:00", End: "21:00"},
+			locations: []*time.Location{moscow},
+			want:      now,
+		},
+		{
+			name:      "later today",
+			window:    SendingWindow{Start: "14:30", End: "21:00"},
+			locations: []*time.Location{moscow},
+			want:      time.Date(2025, 1, 1, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "tomorrow",
+			window:    SendingWindow{Start: "09:00", End: "11:00"},
+			locations: []*time.Location{moscow},
+			want:      time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "overnight open",
+			window:    SendingWindow{Start: "18:00", End: "02:00"},
+			locations: []*time.Location{vladivostok},
+			want:      now,
+		},
+		{
+			name:      "overnight closed",
+			window:    SendingWindow{Start: "22:00", End: "06:00"},
+			locations: []*time.Location{moscow},
+			want:      time.Date(2025, 1, 1, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "common window",
+			window:    SendingWindow{Start: "09:00", End: "18:00"},
+			locations: []*time.Location{moscow, vladivostok},
+			want:      time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "no common window",
+			window:    SendingWindow{Start: "09:00", End: "12:00"},
+			locations: []*time.Location{moscow, vladivostok},
+			want:      time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "unknown locations",
+			window: SendingWindow{Start: "22:00", End: "06:00"},
+			want:   now,
+		},
+		{
+			name:      "whole day",
+			window:    SendingWindow{Start: "00:00", End: "00:00"},
+			locations: []*time.Location{moscow},
+			want:      now,
+		},
+		{
+			name:      "invalid",
+			window:    SendingWindow{Start: "9am", End: "21:00"},
+			locations: []*time.Location{moscow},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.window.Next(now, tt.locations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipientsLocations(t *testing.T) {
+	locations := recipientsLocations([]string{"+79990001234", "+79990001235", "+4915112345678", "invalid"})
+
+	names := map[string]bool{}
+	for _, loc := range locations {
+		names[loc.String()] = true
+	}
+
+	if len(locations) != len(names) {
+		t.Errorf("duplicate locations: %v", locations)
+	}
+	if !names["Europe/Berlin"] {
+		t.Errorf("Europe/Berlin expected, got %v", locations)
+	}
+}
@@ -10,8 +10,13 @@ import (
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/contentpolicy"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/links"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/quotas"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/suppressions"
 	"github.com/capcom6/go-helpers/anys"
 	"github.com/capcom6/go-helpers/slices"
 	"github.com/nyaruka/phonenumbers"
@@ -24,6 +29,7 @@ import (
 
 const (
 	ErrorTTLExpired = "TTL expired"
+	ErrorSuppressed = "recipient is in the suppression list"
 )
 
 type ErrValidation string
@@ -34,6 +40,9 @@ func (e ErrValidation) Error() string {
 
 type EnqueueOptions struct {
 	SkipPhoneValidation bool
+	// Send to recipients in the suppression list, e.g. replies to their
+	// messages
+	SkipSuppressionList bool
 }
 
 type ServiceParams struct {
@@ -43,21 +52,37 @@ type ServiceParams struct {
 
 	Config Config
 
-	Messages    *repository
-	HashingTask *HashingTask
+	Messages     *repository
+	HashingTask  *HashingTask
+	ScheduleTask *ScheduleTask
 
-	PushSvc *push.Service
-	Logger  *zap.Logger
+	PushSvc          *push.Service
+	SettingsSvc      *settings.Service
+	ContentPolicySvc *contentpolicy.Service
+	LinksSvc         *links.Service
+	SuppressionsSvc  *suppressions.Service
+	QuotasSvc        *quotas.Service
+	Logger           *zap.Logger
+
+	StateHandlers []StateHandler `group:"message-state-handlers"`
 }
 
 type Service struct {
 	config Config
 
-	messages    *repository
-	hashingTask *HashingTask
+	messages     *repository
+	hashingTask  *HashingTask
+	scheduleTask *ScheduleTask
+
+	pushSvc          *push.Service
+	settingsSvc      *settings.Service
+	contentPolicySvc *contentpolicy.Service
+	linksSvc         *links.Service
+	suppressionsSvc  *suppressions.Service
+	quotasSvc        *quotas.Service
+	logger           *zap.Logger
 
-	pushSvc *push.Service
-	logger  *zap.Logger
+	stateHandlers []StateHandler
 
 	messagesCounter *prometheus.CounterVec
 
@@ -75,11 +100,18 @@ func NewService(params ServiceParams) *Service {
 	return &Service{
 		config: params.Config,
 
-		messages:    params.Messages,
-		hashingTask: params.HashingTask,
+		messages:     params.Messages,
+		hashingTask:  params.HashingTask,
+		scheduleTask: params.ScheduleTask,
 
-		pushSvc: params.PushSvc,
-		logger:  params.Logger.Named("Service"),
+		pushSvc:          params.PushSvc,
+		settingsSvc:      params.SettingsSvc,
+		contentPolicySvc: params.ContentPolicySvc,
+		linksSvc:         params.LinksSvc,
+		suppressionsSvc:  params.SuppressionsSvc,
+		quotasSvc:        params.QuotasSvc,
+		stateHandlers:    params.StateHandlers,
+		logger:           params.Logger.Named("Service"),
 
 		messagesCounter: messagesCounter,
 
@@ -93,6 +125,12 @@ func (s *Service) RunBackgroundTasks(ctx context.Context, wg *sync.WaitGroup) {
 		defer wg.Done()
 		s.hashingTask.Run(ctx)
 	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.scheduleTask.Run(ctx)
+	}()
 }
 
 func (s *Service) SelectPending(deviceID string) ([]MessageOut, error) {
@@ -104,8 +142,14 @@ func (s *Service) SelectPending(deviceID string) ([]MessageOut, error) {
 	return slices.Map(messages, messageToDomain), nil
 }
 
-func (s *Service) UpdateState(deviceID string, message smsgateway.MessageState) error {
-	existing, err := s.messages.Get(message.ID, MessagesSelectFilter{DeviceID: deviceID})
+// UpdateState stores the message state reported by the device and passes it to
+// the state handlers.
+func (s *Service) UpdateState(device models.Device, message smsgateway.MessageState) error {
+	existing, err := s.messages.Get(
+		message.ID,
+		MessagesSelectFilter{DeviceID: device.ID},
+		MessagesSelectOptions{WithRecipients: true},
+	)
 	if err != nil {
 		return err
 	}
@@ -114,6 +158,8 @@ func (s *Service) UpdateState(deviceID string, message smsgateway.MessageState)
 		message.State = smsgateway.ProcessingStateProcessed
 	}
 
+	previous := existing.Recipients
+
 	existing.State = models.ProcessingState(message.State)
 	existing.States = slices.Map(maps.Keys(message.States), func(key string) models.MessageState {
 		return models.MessageState{
@@ -123,6 +169,20 @@ func (s *Service) UpdateState(deviceID string, message smsgateway.MessageState)
 		}
 	})
 	existing.Recipients = s.recipientsStateToModel(message.Recipients, existing.IsHashed)
+	for i, v := range existing.Recipients {
+		updatedAt, ok := message.States[string(v.State)]
+		if !ok {
+			updatedAt = time.Now()
+		}
+
+		existing.Recipients[i].States = []models.MessageRecipientState{
+			{
+				State:     v.State,
+				Error:     v.Error,
+				UpdatedAt: updatedAt,
+			},
+		}
+	}
 
 	if err := s.messages.UpdateState(&existing); err != nil {
 		return err
@@ -132,24 +192,51 @@ func (s *Service) UpdateState(deviceID string, message smsgateway.MessageState)
 
 	s.messagesCounter.WithLabelValues(string(existing.State)).Inc()
 
+	if err := s.quotasSvc.Record(device.ID, existing.SimNumber, newlySent(previous, existing.Recipients)); err != nil {
+		s.logger.Error("can't record sent messages", zap.String("device_id", device.ID), zap.Error(err))
+	}
+
+	event := StateEvent{Device: device, State: modelToMessageState(existing)}
+	for _, h := range s.stateHandlers {
+		h.HandleMessageState(context.Background(), event)
+	}
+
 	return nil
 }
 
-func (s *Service) GetState(user models.User, ID string) (smsgateway.MessageState, error) {
+func (s *Service) GetState(user models.User, ID string) (MessageStateOut, error) {
 	message, err := s.messages.Get(
 		ID,
 		MessagesSelectFilter{},
-		MessagesSelectOptions{WithRecipients: true, WithDevice: true, WithStates: true},
+		MessagesSelectOptions{WithRecipients: true, WithDevice: true, WithStates: true, WithRetries: true},
 	)
 	if err != nil {
-		return smsgateway.MessageState{}, ErrMessageNotFound
+		return MessageStateOut{}, ErrMessageNotFound
 	}
 
 	if message.Device.UserID != user.ID {
-		return smsgateway.MessageState{}, ErrMessageNotFound
+		return MessageStateOut{}, ErrMessageNotFound
+	}
+
+	states, err := s.withLinks([]models.Message{message})
+	if err != nil {
+		return MessageStateOut{}, err
 	}
 
-	return modelToMessageState(message), nil
+	return states[0], nil
+}
+
+// Select returns states of the user's messages matching the filter, newest
+// first. Recipients' state history is not included.
+func (s *Service) Select(user models.User, filter MessagesSelectFilter, limit, offset int) ([]MessageStateOut, error) {
+	filter.UserID = user.ID
+
+	messages, err := s.messages.Select(filter, limit, offset, MessagesSelectOptions{WithRecipients: true})
+	if err != nil {
+		return nil, fmt.Errorf("can't select messages: %w", err)
+	}
+
+	return s.withLinks(messages)
 }
 
 func (s *Service) Enqueue(device models.Device, message MessageIn, opts EnqueueOptions) (smsgateway.MessageState, error) {
@@ -178,14 +265,28 @@ func (s *Service) Enqueue(device models.Device, message MessageIn, opts EnqueueO
 		}
 	}
 
+	if err := s.checkContent(device.UserID, message.Message, message.IsEncrypted); err != nil {
+		return state, err
+	}
+
+	text, shortLinks, err := s.shortenLinks(message)
+	if err != nil {
+		return state, err
+	}
+
 	var validUntil *time.Time = message.ValidUntil
 	if message.TTL != nil && *message.TTL > 0 {
 		validUntil = anys.AsPointer(time.Now().Add(time.Duration(*message.TTL) * time.Second))
 	}
 
+	scheduleAt, err := s.scheduleAt(device.UserID, message)
+	if err != nil {
+		return state, err
+	}
+
 	msg := models.Message{
 		ExtID:       message.ID,
-		Message:     message.Message,
+		Message:     text,
 		Recipients:  s.recipientsToModel(message.PhoneNumbers),
 		IsEncrypted: message.IsEncrypted,
 
@@ -196,29 +297,149 @@ func (s *Service) Enqueue(device models.Device, message MessageIn, opts EnqueueO
 
 		Priority:   int8(message.Priority),
 		ValidUntil: validUntil,
+		ScheduleAt: scheduleAt,
+
+		IsTransactional: message.IsTransactional,
+
+		Metadata: message.Metadata,
+		Tags:     message.Tags,
 	}
+	if message.SendingWindow != nil {
+		msg.WindowStart = &message.SendingWindow.Start
+		msg.WindowEnd = &message.SendingWindow.End
+	}
+	if !message.IsEncrypted && !opts.SkipSuppressionList {
+		if err := s.applySuppressionList(device.UserID, msg.Recipients); err != nil {
+			return state, err
+		}
+		for i, v := range msg.Recipients {
+			state.Recipients[i].State = smsgateway.ProcessingState(v.State)
+			state.Recipients[i].Error = v.Error
+		}
+	}
+
 	if msg.ExtID == "" {
 		msg.ExtID = s.idgen()
 	}
 	state.ID = msg.ExtID
 
-	if err := s.messages.Insert(&msg); err != nil {
+	if err := s.insert(device, &msg, shortLinks...); err != nil {
 		return state, err
 	}
 
-	if device.PushToken == nil {
-		return state, nil
+	return state, nil
+}
+
+// Retry enqueues a new message for the failed recipients of the message with
+// the given ID. The new message is sent via the original device unless another
+// device is provided. The retry is held until the sending window opens the same
+// way as the original message.
+func (s *Service) Retry(user models.User, ID string, device *models.Device) (MessageStateOut, error) {
+	original, err := s.messages.Get(
+		ID,
+		MessagesSelectFilter{},
+		MessagesSelectOptions{WithRecipients: true, WithDevice: true},
+	)
+	if err != nil {
+		return MessageStateOut{}, ErrMessageNotFound
 	}
 
-	go func(token string) {
-		if err := s.pushSvc.Enqueue(token, push.NewMessageEnqueuedEvent()); err != nil {
-			s.logger.Error("Can't enqueue message", zap.String("token", token), zap.Error(err))
+	if original.Device.UserID != user.ID {
+		return MessageStateOut{}, ErrMessageNotFound
+	}
+
+	if original.IsHashed {
+		return MessageStateOut{}, ErrValidation("message content is no longer available")
+	}
+
+	phoneNumbers := []string{}
+	for _, v := range original.Recipients {
+		if v.State == models.ProcessingStateFailed {
+			phoneNumbers = append(phoneNumbers, v.PhoneNumber)
 		}
-	}(*device.PushToken)
+	}
+	if len(phoneNumbers) == 0 {
+		return MessageStateOut{}, ErrValidation("no failed recipients")
+	}
 
-	s.messagesCounter.WithLabelValues(string(state.State)).Inc()
+	// the policy may have been changed since the original message was sent
+	if err := s.checkContent(user.ID, original.Message, original.IsEncrypted); err != nil {
+		return MessageStateOut{}, err
+	}
 
-	return state, nil
+	simNumber := original.SimNumber
+	if device == nil {
+		device = &original.Device
+	} else if device.ID != original.DeviceID {
+		// SIM slots are device specific
+		simNumber = nil
+	}
+
+	var validUntil *time.Time
+	if original.ValidUntil != nil {
+		validUntil = anys.AsPointer(time.Now().Add(original.ValidUntil.Sub(original.CreatedAt)))
+	}
+
+	schedule := MessageIn{
+		PhoneNumbers:    phoneNumbers,
+		IsTransactional: original.IsTransactional,
+	}
+	if original.WindowStart != nil && original.WindowEnd != nil {
+		schedule.SendingWindow = &SendingWindow{Start: *original.WindowStart, End: *original.WindowEnd}
+	}
+
+	scheduleAt, err := s.scheduleAt(user.ID, schedule)
+	if err != nil {
+		return MessageStateOut{}, err
+	}
+
+	msg := models.Message{
+		ExtID:       s.idgen(),
+		Message:     original.Message,
+		Recipients:  s.recipientsToModel(phoneNumbers),
+		IsEncrypted: original.IsEncrypted,
+
+		DeviceID: device.ID,
+
+		SimNumber:          simNumber,
+		WithDeliveryReport: original.WithDeliveryReport,
+
+		Priority:   original.Priority,
+		ValidUntil: validUntil,
+		ScheduleAt: scheduleAt,
+
+		RetryOfID: &original.ID,
+
+		WindowStart:     original.WindowStart,
+		WindowEnd:       original.WindowEnd,
+		IsTransactional: original.IsTransactional,
+
+		Metadata: original.Metadata,
+		Tags:     original.Tags,
+	}
+
+	if !msg.IsEncrypted {
+		if err := s.applySuppressionList(user.ID, msg.Recipients); err != nil {
+			return MessageStateOut{}, err
+		}
+	}
+
+	if err := s.insert(*device, &msg); err != nil {
+		return MessageStateOut{}, err
+	}
+
+	return MessageStateOut{
+		MessageState: smsgateway.MessageState{
+			ID:          msg.ExtID,
+			State:       smsgateway.ProcessingStatePending,
+			IsEncrypted: msg.IsEncrypted,
+		},
+		Recipients: slices.Map(msg.Recipients, modelToRecipientState),
+		RetryOf:    &original.ExtID,
+		Metadata:   msg.Metadata,
+		Tags:       msg.Tags,
+		ScheduleAt: msg.ScheduleAt,
+	}, nil
 }
 
 func (s *Service) ExportInbox(device models.Device, since, until time.Time) error {
@@ -231,6 +452,22 @@ func (s *Service) ExportInbox(device models.Device, since, until time.Time) erro
 	return s.pushSvc.Enqueue(*device.PushToken, event)
 }
 
+// Count returns the number of stored messages of the user by state. Processed
+// messages are removed after the configured lifetime.
+func (s *Service) Count(userID string) (CountsOut, error) {
+	states, err := s.messages.CountByState(userID)
+	if err != nil {
+		return CountsOut{}, fmt.Errorf("can't count messages: %w", err)
+	}
+
+	out := CountsOut{States: states}
+	for _, n := range states {
+		out.Total += n
+	}
+
+	return out, nil
+}
+
 func (s *Service) Clean(ctx context.Context) error {
 	//TODO: use delete queue to optimize deletion
 	n, err := s.messages.removeProcessed(ctx, time.Now().Add(-s.config.ProcessedLifetime))
@@ -241,6 +478,60 @@ func (s *Service) Clean(ctx context.Context) error {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// scheduleAt returns the time the message must be held until to be sent in
+// the sending window of its recipients or nil if it can be sent now.
+func (s *Service) scheduleAt(userID string, message MessageIn) (*time.Time, error) {
+	if message.IsTransactional {
+		return nil, nil
+	}
+
+	window := message.SendingWindow
+	if window == nil {
+		start, end, err := s.settingsSvc.GetSendingWindow(userID)
+		if err != nil {
+			return nil, fmt.Errorf("can't get sending window: %w", err)
+		}
+		if start == "" || end == "" {
+			return nil, nil
+		}
+
+		window = &SendingWindow{Start: start, End: end}
+	}
+
+	now := time.Now()
+	next, err := window.Next(now, recipientsLocations(message.PhoneNumbers))
+	if err != nil {
+		return nil, ErrValidation(fmt.Sprintf("invalid sending window: %s", err.Error()))
+	}
+
+	if !next.After(now) {
+		return nil, nil
+	}
+
+	return &next, nil
+}
+
+// insert stores the message and notifies the device about it.
+func (s *Service) insert(device models.Device, msg *models.Message, shortLinks ...links.Link) error {
+	if err := s.messages.Insert(msg, shortLinks...); err != nil {
+		return err
+	}
+
+	if device.PushToken == nil {
+		return nil
+	}
+
+	go func(token string) {
+		if err := s.pushSvc.Enqueue(token, push.NewMessageEnqueuedEvent()); err != nil {
+			s.logger.Error("Can't enqueue message", zap.String("token", token), zap.Error(err))
+		}
+	}(*device.PushToken)
+
+	s.messagesCounter.WithLabelValues(string(models.ProcessingStatePending)).Inc()
+
+	return nil
+}
+
 func (s *Service) recipientsToModel(input []string) []models.MessageRecipient {
 	output := make([]models.MessageRecipient, len(input))
 
@@ -281,29 +572,174 @@ func (s *Service) recipientsStateToModel(input []smsgateway.RecipientState, hash
 	return output
 }
 
-func modelToMessageState(input models.Message) smsgateway.MessageState {
-	return smsgateway.MessageState{
-		ID:          input.ExtID,
-		State:       smsgateway.ProcessingState(input.State),
-		IsHashed:    input.IsHashed,
-		IsEncrypted: input.IsEncrypted,
-		Recipients:  slices.Map(input.Recipients, modelToRecipientState),
-		States: slices.Associate(
-			input.States,
-			func(state models.MessageState) string { return string(state.State) },
-			func(state models.MessageState) time.Time { return state.UpdatedAt },
-		),
+// newlySent counts recipients that are sent according to the current states
+// but were not sent before, so partial progress reports are counted once.
+func newlySent(previous, current []models.MessageRecipient) uint {
+	isSent := func(state models.ProcessingState) bool {
+		return state == models.ProcessingStateSent || state == models.ProcessingStateDelivered
 	}
+
+	wasSent := make(map[string]bool, len(previous))
+	for _, r := range previous {
+		wasSent[r.PhoneNumber] = isSent(r.State)
+	}
+
+	sent := uint(0)
+	for _, r := range current {
+		if isSent(r.State) && !wasSent[r.PhoneNumber] {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+func modelToMessageState(input models.Message) MessageStateOut {
+	state := MessageStateOut{
+		MessageState: smsgateway.MessageState{
+			ID:          input.ExtID,
+			State:       smsgateway.ProcessingState(input.State),
+			IsHashed:    input.IsHashed,
+			IsEncrypted: input.IsEncrypted,
+			States: slices.Associate(
+				input.States,
+				func(state models.MessageState) string { return string(state.State) },
+				func(state models.MessageState) time.Time { return state.UpdatedAt },
+			),
+		},
+		Recipients: slices.Map(input.Recipients, modelToRecipientState),
+		Retries:    slices.Map(input.Retries, func(retry models.Message) string { return retry.ExtID }),
+		Metadata:   input.Metadata,
+		Tags:       input.Tags,
+		ScheduleAt: input.ScheduleAt,
+	}
+
+	if input.RetryOf != nil {
+		state.RetryOf = &input.RetryOf.ExtID
+	}
+
+	return state
 }
 
-func modelToRecipientState(input models.MessageRecipient) smsgateway.RecipientState {
-	return smsgateway.RecipientState{
-		PhoneNumber: input.PhoneNumber,
-		State:       smsgateway.ProcessingState(input.State),
-		Error:       input.Error,
+func modelToRecipientState(input models.MessageRecipient) RecipientStateOut {
+	return RecipientStateOut{
+		RecipientState: smsgateway.RecipientState{
+			PhoneNumber: input.PhoneNumber,
+			State:       smsgateway.ProcessingState(input.State),
+			Error:       input.Error,
+		},
+		States: slices.Map(input.States, modelToRecipientStateHistoryItem),
 	}
 }
 
+func modelToRecipientStateHistoryItem(input models.MessageRecipientState) RecipientStateHistoryItem {
+	return RecipientStateHistoryItem{
+		State:     smsgateway.ProcessingState(input.State),
+		Error:     input.Error,
+		UpdatedAt: input.UpdatedAt,
+	}
+}
+
+// checkContent applies the user's content policy to the message text.
+func (s *Service) checkContent(userID, text string, isEncrypted bool) error {
+	err := s.contentPolicySvc.Check(userID, text, isEncrypted)
+	if err == nil {
+		return nil
+	}
+
+	var violation contentpolicy.Violation
+	if errors.As(err, &violation) {
+		return ErrValidation(violation.Error())
+	}
+
+	return fmt.Errorf("can't check content policy: %w", err)
+}
+
+// applySuppressionList fails recipients in the user's suppression list, so
+// they are not sent to the device.
+func (s *Service) applySuppressionList(userID string, recipients []models.MessageRecipient) error {
+	suppressed, err := s.suppressionsSvc.Filter(userID, slices.Map(recipients, recipientToDomain))
+	if err != nil {
+		return err
+	}
+	if len(suppressed) == 0 {
+		return nil
+	}
+	if len(suppressed) == len(recipients) {
+		return ErrValidation("all recipients are in the suppression list")
+	}
+
+	for i, v := range recipients {
+		if _, ok := suppressed[v.PhoneNumber]; ok {
+			recipients[i].State = models.ProcessingStateFailed
+			recipients[i].Error = anys.AsPointer(ErrorSuppressed)
+		}
+	}
+
+	return nil
+}
+
+// shortenLinks returns the message text with URLs replaced by short links if
+// requested.
+func (s *Service) shortenLinks(message MessageIn) (string, []links.Link, error) {
+	if !message.ShortenLinks {
+		return message.Message, nil, nil
+	}
+
+	if message.IsEncrypted {
+		return "", nil, ErrValidation("links can't be shortened in encrypted messages")
+	}
+
+	text, shortLinks, err := s.linksSvc.Shorten(message.Message)
+	if errors.Is(err, links.ErrDisabled) {
+		return "", nil, ErrValidation(err.Error())
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("can't shorten links: %w", err)
+	}
+
+	return text, shortLinks, nil
+}
+
+// withLinks converts messages to states with short links click stats.
+func (s *Service) withLinks(messages []models.Message) ([]MessageStateOut, error) {
+	states := slices.Map(messages, modelToMessageState)
+
+	items, err := s.linksSvc.SelectByMessages(slices.Map(messages, func(m models.Message) uint64 { return m.ID }))
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return states, nil
+	}
+
+	index := make(map[uint64]int, len(messages))
+	for i, m := range messages {
+		index[m.ID] = i
+	}
+
+	for _, link := range items {
+		i := index[link.MessageID]
+		states[i].Links = append(states[i].Links, LinkOut{
+			URL:         link.URL,
+			ShortURL:    s.linksSvc.ShortURL(link.Code),
+			Clicks:      link.Clicks,
+			LastClickAt: link.LastClickAt,
+		})
+
+		if link.RecipientID == nil {
+			continue
+		}
+		for j, r := range messages[i].Recipients {
+			if r.ID == *link.RecipientID {
+				states[i].Recipients[j].Clicks += link.Clicks
+			}
+		}
+	}
+
+	return states, nil
+}
+
 func cleanPhoneNumber(input string) (string, error) {
 	phone, err := phonenumbers.Parse(input, "RU")
 	if err != nil {
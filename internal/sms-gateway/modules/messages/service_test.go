@@ -157,3 +157,64 @@ func TestCleanPhoneNumber(t *testing.T) {
 		})
 	}
 }
+
+func Test_newlySent(t *testing.T) {
+	recipients := func(states ...models.ProcessingState) []models.MessageRecipient {
+		out := make([]models.MessageRecipient, len(states))
+		for i, state := range states {
+			out[i] = models.MessageRecipient{PhoneNumber: string(rune('A' + i)), State: state}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		previous []models.MessageRecipient
+		current  []models.MessageRecipient
+		want     uint
+	}{
+		{
+			name:     "processed",
+			previous: recipients(models.ProcessingStatePending, models.ProcessingStatePending),
+			current:  recipients(models.ProcessingStateProcessed, models.ProcessingStateProcessed),
+			want:     0,
+		},
+		{
+			name:     "first recipient sent",
+			previous: recipients(models.ProcessingStateProcessed, models.ProcessingStateProcessed),
+			current:  recipients(models.ProcessingStateSent, models.ProcessingStateProcessed),
+			want:     1,
+		},
+		{
+			name:     "second recipient sent",
+			previous: recipients(models.ProcessingStateSent, models.ProcessingStateProcessed),
+			current:  recipients(models.ProcessingStateSent, models.ProcessingStateSent),
+			want:     1,
+		},
+		{
+			name:     "delivered after sent",
+			previous: recipients(models.ProcessingStateSent, models.ProcessingStateSent),
+			current:  recipients(models.ProcessingStateDelivered, models.ProcessingStateDelivered),
+			want:     0,
+		},
+		{
+			name:     "delivered at once",
+			previous: recipients(models.ProcessingStatePending, models.ProcessingStatePending),
+			current:  recipients(models.ProcessingStateDelivered, models.ProcessingStateFailed),
+			want:     1,
+		},
+		{
+			name:     "repeated report",
+			previous: recipients(models.ProcessingStateSent, models.ProcessingStateFailed),
+			current:  recipients(models.ProcessingStateSent, models.ProcessingStateFailed),
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newlySent(tt.previous, tt.current); got != tt.want {
+				t.Errorf("newlySent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
@@ -86,3 +87,76 @@ func NewHashingTask(params HashingTaskParams) *HashingTask {
 		queue:    map[uint64]struct{}{},
 	}
 }
+
+type ScheduleTaskConfig struct {
+	Interval time.Duration
+}
+
+type ScheduleTaskParams struct {
+	fx.In
+
+	Messages *repository
+	PushSvc  *push.Service
+	Config   ScheduleTaskConfig
+	Logger   *zap.Logger
+}
+
+// ScheduleTask notifies devices about held messages that became due, so they
+// don't wait for the next unrelated push or poll.
+type ScheduleTask struct {
+	Messages *repository
+	PushSvc  *push.Service
+	Config   ScheduleTaskConfig
+	Logger   *zap.Logger
+}
+
+func (t *ScheduleTask) Run(ctx context.Context) {
+	t.Logger.Info("Starting schedule task...")
+	ticker := time.NewTicker(t.Config.Interval)
+	defer ticker.Stop()
+
+	checkedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			t.Logger.Info("Stopping schedule task...")
+			return
+		case now := <-ticker.C:
+			if err := t.process(checkedAt, now); err != nil {
+				t.Logger.Error("Can't notify devices about scheduled messages", zap.Error(err))
+				continue
+			}
+			checkedAt = now
+		}
+	}
+}
+
+// process notifies devices with messages that became due since the previous
+// check.
+func (t *ScheduleTask) process(from, to time.Time) error {
+	devices, err := t.Messages.SelectDueDevices(from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		if device.PushToken == nil {
+			continue
+		}
+
+		if err := t.PushSvc.Enqueue(*device.PushToken, push.NewMessageEnqueuedEvent()); err != nil {
+			t.Logger.Error("Can't enqueue message", zap.String("device_id", device.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func NewScheduleTask(params ScheduleTaskParams) *ScheduleTask {
+	return &ScheduleTask{
+		Messages: params.Messages,
+		PushSvc:  params.PushSvc,
+		Config:   params.Config,
+		Logger:   params.Logger,
+	}
+}
@@ -0,0 +1,17 @@
+package organizations
+
+func memberToDTO(m Member) MemberOut {
+	return MemberOut{
+		UserID:    m.UserID,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func membershipToDTO(m Member) OrganizationOut {
+	return OrganizationOut{
+		ID:   m.OrganizationID,
+		Name: m.Organization.Name,
+		Role: m.Role,
+	}
+}
@@ -0,0 +1,76 @@
+package organizations
+
+import "time"
+
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleSender Role = "sender"
+	RoleViewer Role = "viewer"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleSender: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// Allows reports whether the role grants at least the required role. An empty
+// required role is never granted.
+func (r Role) Allows(required Role) bool {
+	need, ok := roleRanks[required]
+	return ok && roleRanks[r] >= need
+}
+
+// Membership is the user's role in the organization the request is made on
+// behalf of.
+type Membership struct {
+	OrganizationID string
+	UserID         string
+	Role           Role
+}
+
+type OrganizationIn struct {
+	// Name
+	Name string `json:"name" validate:"required,max=128" example:"ACME"`
+}
+
+type OrganizationOut struct {
+	// ID
+	ID string `json:"id" example:"ABCDEF"`
+	// Name
+	Name string `json:"name" example:"ACME"`
+	// Role of the current user
+	Role Role `json:"role" example:"owner"`
+}
+
+type MemberIn struct {
+	// Role
+	Role Role `json:"role" validate:"required,oneof=owner admin sender viewer" example:"sender"`
+}
+
+type NewMemberIn struct {
+	MemberIn
+
+	// One-time code of an existing user to add, see `GET /mobile/v1/user/code`. A new user is created if empty
+	Code string `json:"code,omitempty" validate:"omitempty,len=6,numeric" example:"123456"`
+}
+
+type MemberOut struct {
+	// User login
+	UserID string `json:"userId" example:"ABCDEF"`
+	// Role
+	Role Role `json:"role" example:"sender"`
+	// Joined at
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+}
+
+type CreatedMemberOut struct {
+	MemberOut
+
+	// Password of the new user, it is shown only once. Empty for existing users
+	Password string `json:"password,omitempty" example:"abcdefghijklmn"`
+}
@@ -0,0 +1,57 @@
+package organizations
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{RoleOwner, RoleAdmin, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleSender, RoleAdmin, false},
+		{RoleSender, RoleSender, true},
+		{RoleViewer, RoleSender, false},
+		{RoleViewer, RoleViewer, true},
+		{RoleOwner, "", false},
+		{"unknown", RoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.required), func(t *testing.T) {
+			if got := tt.role.Allows(tt.required); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckManage(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor Role
+		from  Role
+		to    Role
+		want  error
+	}{
+		{"owner adds owner", RoleOwner, "", RoleOwner, nil},
+		{"admin adds sender", RoleAdmin, "", RoleSender, nil},
+		{"admin adds owner", RoleAdmin, "", RoleOwner, ErrForbidden},
+		{"admin demotes owner", RoleAdmin, RoleOwner, RoleAdmin, ErrForbidden},
+		{"admin removes admin", RoleAdmin, RoleAdmin, "", nil},
+		{"sender adds viewer", RoleSender, "", RoleViewer, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkManage(Membership{Role: tt.actor}, tt.from, tt.to)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkManage() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package organizations
+
+import "errors"
+
+var (
+	ErrNotFound  = errors.New("organization or member not found")
+	ErrForbidden = errors.New("insufficient role")
+	ErrLastOwner = errors.New("organization must have at least one owner")
+
+	ErrInvalidCode   = errors.New("invalid or expired code")
+	ErrAlreadyMember = errors.New("user is already a member")
+)
@@ -0,0 +1,60 @@
+package organizations
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// Organization owns devices, webhooks, settings and other resources. Its ID
+// is the ID of the account user the resources are stored under.
+type Organization struct {
+	ID   string `gorm:"primaryKey;type:varchar(32)"`
+	Name string `gorm:"not null;type:varchar(128)"`
+
+	Account models.User `gorm:"foreignKey:ID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type Member struct {
+	OrganizationID string `gorm:"primaryKey;type:varchar(32)"`
+	UserID         string `gorm:"primaryKey;type:varchar(32);index:idx_organization_members_user"`
+	Role           Role   `gorm:"not null;type:enum('owner','admin','sender','viewer')"`
+
+	Organization Organization `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+	User         models.User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Member) TableName() string {
+	return "organization_members"
+}
+
+func Migrate(db *gorm.DB) error {
+	exists := db.Migrator().HasTable(&Organization{})
+
+	if err := db.AutoMigrate(&Organization{}, &Member{}); err != nil {
+		return fmt.Errorf("organizations migration failed: %w", err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	// existing users become owners of single-member organizations
+	if err := db.Exec("INSERT INTO `organizations` (`id`, `name`) SELECT `id`, `id` FROM `users`").Error; err != nil {
+		return fmt.Errorf("organizations migration failed: %w", err)
+	}
+	if err := db.Exec("INSERT INTO `organization_members` (`organization_id`, `user_id`, `role`) SELECT `id`, `id`, 'owner' FROM `users`").Error; err != nil {
+		return fmt.Errorf("organization_members migration failed: %w", err)
+	}
+
+	return nil
+}
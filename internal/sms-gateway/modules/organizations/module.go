@@ -0,0 +1,23 @@
+package organizations
+
+import (
+	"github.com/capcom6/go-infra-fx/db"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Module(
+	"organizations",
+	fx.Decorate(func(log *zap.Logger) *zap.Logger {
+		return log.Named("organizations")
+	}),
+	fx.Provide(
+		newRepository,
+		fx.Private,
+	),
+	fx.Provide(NewService),
+)
+
+func init() {
+	db.RegisterMigration(Migrate)
+}
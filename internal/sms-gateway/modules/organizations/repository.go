@@ -0,0 +1,141 @@
+package organizations
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// Create inserts the organization with its owner.
+func (r *repository) Create(org *Organization, owner *Member) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Account").Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(owner).Error
+	})
+}
+
+// Exists checks if the organization exists.
+func (r *repository) Exists(id string) (bool, error) {
+	count := int64(0)
+	err := r.db.Model(&Organization{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) UpdateName(id, name string) error {
+	res := r.db.Model(&Organization{}).Where("id = ?", id).Update("name", name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SelectMemberships returns memberships of the user with organizations, the
+// oldest first.
+func (r *repository) SelectMemberships(userID string) ([]Member, error) {
+	items := []Member{}
+
+	return items, r.db.
+		Joins("Organization").
+		Where("organization_members.user_id = ?", userID).
+		Order("organization_members.created_at").
+		Find(&items).
+		Error
+}
+
+func (r *repository) GetMember(orgID, userID string) (Member, error) {
+	member := Member{}
+
+	err := r.db.
+		Joins("Organization").
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID).
+		Take(&member).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return member, ErrNotFound
+	}
+
+	return member, err
+}
+
+func (r *repository) SelectMembers(orgID string) ([]Member, error) {
+	items := []Member{}
+
+	return items, r.db.
+		Where("organization_id = ?", orgID).
+		Order("created_at").
+		Find(&items).
+		Error
+}
+
+func (r *repository) InsertMember(member *Member) error {
+	return r.db.Omit(clause.Associations).Create(member).Error
+}
+
+// UpdateRole changes the role of the member. Demotion of the last owner fails
+// with ErrLastOwner.
+func (r *repository) UpdateRole(orgID, userID string, role Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.checkLastOwner(tx, orgID, userID, role); err != nil {
+			return err
+		}
+
+		return tx.Model(&Member{}).
+			Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Update("role", role).
+			Error
+	})
+}
+
+// DeleteMember removes the member. Removal of the last owner fails with
+// ErrLastOwner.
+func (r *repository) DeleteMember(orgID, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.checkLastOwner(tx, orgID, userID, ""); err != nil {
+			return err
+		}
+
+		return tx.
+			Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Delete(&Member{}).
+			Error
+	})
+}
+
+func (r *repository) checkLastOwner(tx *gorm.DB, orgID, userID string, role Role) error {
+	if role == RoleOwner {
+		return nil
+	}
+
+	owners := []string{}
+	if err := tx.
+		Model(&Member{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", orgID, RoleOwner).
+		Pluck("user_id", &owners).
+		Error; err != nil {
+		return err
+	}
+
+	if len(owners) == 1 && owners[0] == userID {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,271 @@
+package organizations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/auth"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/lockout"
+	"github.com/capcom6/go-helpers/slices"
+	"github.com/jaevor/go-nanoid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Organizations *repository
+
+	AuthSvc *auth.Service
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	organizations *repository
+
+	authSvc *auth.Service
+
+	idGen func() string
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) (*Service, error) {
+	idGen, err := nanoid.Standard(21)
+	if err != nil {
+		return nil, fmt.Errorf("can't create id generator: %w", err)
+	}
+
+	return &Service{
+		organizations: params.Organizations,
+
+		authSvc: params.AuthSvc,
+
+		idGen: idGen,
+
+		logger: params.Logger.Named("service"),
+	}, nil
+}
+
+// CreatePersonal creates the organization of the newly registered user with
+// the user as the only owner.
+func (s *Service) CreatePersonal(userID string) error {
+	org := Organization{ID: userID, Name: userID}
+	owner := Member{OrganizationID: userID, UserID: userID, Role: RoleOwner}
+
+	if err := s.organizations.Create(&org, &owner); err != nil {
+		return fmt.Errorf("can't create organization: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve returns the user's membership in the organization. If the
+// organization is not specified, the user's own organization is used or the
+// one the user joined first.
+func (s *Service) Resolve(userID, orgID string) (Membership, error) {
+	if orgID != "" {
+		member, err := s.organizations.GetMember(orgID, userID)
+		if err != nil {
+			return Membership{}, err
+		}
+		return membershipFromModel(member), nil
+	}
+
+	items, err := s.organizations.SelectMemberships(userID)
+	if err != nil {
+		return Membership{}, fmt.Errorf("can't select memberships: %w", err)
+	}
+	if len(items) == 0 {
+		return Membership{}, ErrNotFound
+	}
+
+	for _, item := range items {
+		if item.OrganizationID == userID {
+			return membershipFromModel(item), nil
+		}
+	}
+
+	return membershipFromModel(items[0]), nil
+}
+
+// ResolveManaged returns the organization the user registers devices in when
+// none is selected. Account credentials stand for the organization stored
+// under them, so the account user manages it regardless of memberships. Other
+// users need the admin role in the organization resolved by default.
+func (s *Service) ResolveManaged(userID string) (Membership, error) {
+	exists, err := s.organizations.Exists(userID)
+	if err != nil {
+		return Membership{}, fmt.Errorf("can't check organization: %w", err)
+	}
+	if exists {
+		return Membership{OrganizationID: userID, UserID: userID, Role: RoleOwner}, nil
+	}
+
+	membership, err := s.Resolve(userID, "")
+	if err != nil {
+		return Membership{}, err
+	}
+	if !membership.Role.Allows(RoleAdmin) {
+		return Membership{}, ErrForbidden
+	}
+
+	return membership, nil
+}
+
+// Get returns the organization as seen by the member.
+func (s *Service) Get(m Membership) (OrganizationOut, error) {
+	member, err := s.organizations.GetMember(m.OrganizationID, m.UserID)
+	if err != nil {
+		return OrganizationOut{}, err
+	}
+
+	return membershipToDTO(member), nil
+}
+
+// SelectMemberships returns organizations the user is a member of.
+func (s *Service) SelectMemberships(userID string) ([]OrganizationOut, error) {
+	items, err := s.organizations.SelectMemberships(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't select memberships: %w", err)
+	}
+
+	return slices.Map(items, membershipToDTO), nil
+}
+
+// Rename changes the name of the organization, admin role is required.
+func (s *Service) Rename(actor Membership, in OrganizationIn) error {
+	if !actor.Role.Allows(RoleAdmin) {
+		return ErrForbidden
+	}
+
+	return s.organizations.UpdateName(actor.OrganizationID, in.Name)
+}
+
+func (s *Service) SelectMembers(orgID string) ([]MemberOut, error) {
+	items, err := s.organizations.SelectMembers(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("can't select members: %w", err)
+	}
+
+	return slices.Map(items, memberToDTO), nil
+}
+
+// Invite adds a user to the organization with the role. An existing user is
+// identified by the one-time code generated by that user, otherwise a new user
+// is created. Credentials of a new user are returned only once. Failed code
+// attempts are tracked per client IP, lockout.LockedError is returned while
+// the client is locked out.
+func (s *Service) Invite(actor Membership, in NewMemberIn, ip string) (CreatedMemberOut, error) {
+	if err := checkManage(actor, "", in.Role); err != nil {
+		return CreatedMemberOut{}, err
+	}
+
+	login, password := "", ""
+	if in.Code != "" {
+		user, err := s.authSvc.AuthorizeUserByCode(in.Code, ip)
+		if errors.As(err, &lockout.LockedError{}) {
+			return CreatedMemberOut{}, err
+		}
+		if err != nil {
+			return CreatedMemberOut{}, ErrInvalidCode
+		}
+		login = user.ID
+
+		if _, err := s.organizations.GetMember(actor.OrganizationID, login); err == nil {
+			return CreatedMemberOut{}, ErrAlreadyMember
+		} else if !errors.Is(err, ErrNotFound) {
+			return CreatedMemberOut{}, fmt.Errorf("can't get member: %w", err)
+		}
+	} else {
+		id := s.idGen()
+		login = strings.ToUpper(id[:6])
+		password = strings.ToLower(id[7:])
+
+		if _, err := s.authSvc.RegisterUser(login, password); err != nil {
+			return CreatedMemberOut{}, fmt.Errorf("can't create user: %w", err)
+		}
+	}
+
+	member := Member{
+		OrganizationID: actor.OrganizationID,
+		UserID:         login,
+		Role:           in.Role,
+	}
+	if err := s.organizations.InsertMember(&member); err != nil {
+		return CreatedMemberOut{}, fmt.Errorf("can't add member: %w", err)
+	}
+
+	s.logger.Info("Member added",
+		zap.String("organization_id", actor.OrganizationID),
+		zap.String("user_id", login),
+		zap.String("role", string(in.Role)),
+		zap.Bool("existing", in.Code != ""),
+	)
+
+	member, err := s.organizations.GetMember(actor.OrganizationID, login)
+	if err != nil {
+		return CreatedMemberOut{}, fmt.Errorf("can't get member: %w", err)
+	}
+
+	return CreatedMemberOut{
+		MemberOut: memberToDTO(member),
+		Password:  password,
+	}, nil
+}
+
+// UpdateRole changes the role of the member.
+func (s *Service) UpdateRole(actor Membership, userID string, in MemberIn) error {
+	member, err := s.organizations.GetMember(actor.OrganizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkManage(actor, member.Role, in.Role); err != nil {
+		return err
+	}
+
+	return s.organizations.UpdateRole(actor.OrganizationID, userID, in.Role)
+}
+
+// Remove removes the member from the organization. The user itself is kept.
+func (s *Service) Remove(actor Membership, userID string) error {
+	member, err := s.organizations.GetMember(actor.OrganizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	// members can always leave
+	if userID != actor.UserID {
+		if err := checkManage(actor, member.Role, ""); err != nil {
+			return err
+		}
+	}
+
+	return s.organizations.DeleteMember(actor.OrganizationID, userID)
+}
+
+// checkManage checks if the actor can change the member's role from one to
+// another. Admins manage all members except owners, owners manage everyone.
+func checkManage(actor Membership, from, to Role) error {
+	if !actor.Role.Allows(RoleAdmin) {
+		return ErrForbidden
+	}
+
+	if (from == RoleOwner || to == RoleOwner) && actor.Role != RoleOwner {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func membershipFromModel(m Member) Membership {
+	return Membership{
+		OrganizationID: m.OrganizationID,
+		UserID:         m.UserID,
+		Role:           m.Role,
+	}
+}
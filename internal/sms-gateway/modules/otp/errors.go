@@ -0,0 +1,18 @@
+package otp
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("code not found or expired")
+
+// LockedError is returned when the recipient has exceeded verification
+// attempts.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e LockedError) Error() string {
+	return "too many attempts, try again after " + e.Until.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,203 @@
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/android-sms-gateway/server/pkg/templates"
+	"github.com/capcom6/go-helpers/cache"
+	"github.com/jaevor/go-nanoid"
+	"github.com/nyaruka/phonenumbers"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type ServiceParams struct {
+	fx.In
+
+	MessagesSvc *messages.Service
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	messagesSvc *messages.Service
+
+	codes   *cache.Cache[entry]
+	lockout *cache.Cache[time.Time]
+	mux     sync.Mutex
+
+	logger *zap.Logger
+
+	idgen func() string
+}
+
+func NewService(params ServiceParams) *Service {
+	idgen, _ := nanoid.Standard(21)
+
+	return &Service{
+		messagesSvc: params.MessagesSvc,
+
+		codes:   cache.New[entry](cache.Config{}),
+		lockout: cache.New[time.Time](cache.Config{}),
+
+		logger: params.Logger.Named("service"),
+
+		idgen: idgen,
+	}
+}
+
+// Send generates a code and sends it to the recipient via the device. Only
+// a hash of the code is stored.
+func (s *Service) Send(device models.Device, in SendIn) (SendOut, error) {
+	if until, err := s.lockout.Get(lockoutKey(device.UserID, in.PhoneNumber)); err == nil {
+		return SendOut{}, LockedError{Until: until}
+	}
+
+	if in.Template == "" {
+		in.Template = DefaultTemplate
+	}
+	if !strings.Contains(in.Template, "{{code}}") {
+		return SendOut{}, messages.ErrValidation("template must contain {{code}} placeholder")
+	}
+	if in.Length == 0 {
+		in.Length = DefaultLength
+	}
+	if in.TTL == 0 {
+		in.TTL = DefaultTTL
+	}
+
+	code, err := newCode(in.Length)
+	if err != nil {
+		return SendOut{}, err
+	}
+
+	id := s.idgen()
+	validUntil := time.Now().Add(in.TTL)
+
+	state, err := s.messagesSvc.Enqueue(
+		device,
+		messages.MessageIn{
+			Message:      templates.Render(in.Template, map[string]string{"code": code}),
+			PhoneNumbers: []string{in.PhoneNumber},
+			SimNumber:    in.SimNumber,
+			ValidUntil:   &validUntil,
+			// codes are useless after expiration, so they bypass limits and
+			// sending windows
+			Priority:        smsgateway.PriorityBypassThreshold,
+			IsTransactional: true,
+			Tags:            []string{"otp"},
+		},
+		messages.EnqueueOptions{},
+	)
+	if err != nil {
+		return SendOut{}, err
+	}
+
+	err = s.codes.Set(
+		id,
+		entry{
+			UserID:      device.UserID,
+			PhoneNumber: in.PhoneNumber,
+			Hash:        hashCode(id, code),
+			ValidUntil:  validUntil,
+		},
+		cache.WithValidUntil(validUntil),
+	)
+	if err != nil {
+		return SendOut{}, fmt.Errorf("can't store code: %w", err)
+	}
+
+	return SendOut{ID: id, MessageID: state.ID, ValidUntil: validUntil}, nil
+}
+
+// Verify checks the code. A verified code is deleted. After maxAttempts wrong
+// codes the code is deleted and the recipient is locked out.
+func (s *Service) Verify(userID, id, code string) (VerifyOut, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	item, err := s.codes.Get(id)
+	if err != nil || item.UserID != userID {
+		return VerifyOut{}, ErrNotFound
+	}
+
+	key := lockoutKey(userID, item.PhoneNumber)
+	if until, err := s.lockout.Get(key); err == nil {
+		return VerifyOut{}, LockedError{Until: until}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(item.Hash), []byte(hashCode(id, code))) == 1 {
+		_ = s.codes.Delete(id)
+		return VerifyOut{Verified: true}, nil
+	}
+
+	item.Attempts++
+	if item.Attempts >= maxAttempts {
+		_ = s.codes.Delete(id)
+
+		until := time.Now().Add(lockoutDuration)
+		if err := s.lockout.Set(key, until, cache.WithValidUntil(until)); err != nil {
+			return VerifyOut{}, fmt.Errorf("can't lock out recipient: %w", err)
+		}
+
+		return VerifyOut{}, LockedError{Until: until}
+	}
+
+	if err := s.codes.Set(id, item, cache.WithValidUntil(item.ValidUntil)); err != nil {
+		return VerifyOut{}, fmt.Errorf("can't update code: %w", err)
+	}
+
+	return VerifyOut{AttemptsLeft: maxAttempts - item.Attempts}, nil
+}
+
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.codes.Cleanup()
+			s.lockout.Cleanup()
+		}
+	}
+}
+
+// lockoutKey returns the lockout key of the recipient. The phone number is
+// normalized, so different formats of the same number share the lockout.
+func lockoutKey(userID, phoneNumber string) string {
+	if phone, err := phonenumbers.Parse(phoneNumber, "RU"); err == nil {
+		phoneNumber = phonenumbers.Format(phone, phonenumbers.E164)
+	}
+
+	return userID + ":" + phoneNumber
+}
+
+func hashCode(id, code string) string {
+	hash := sha256.Sum256([]byte(id + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+func newCode(length int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("can't generate code: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", length, n), nil
+}
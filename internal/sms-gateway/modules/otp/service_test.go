@@ -0,0 +1,90 @@
+package otp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/capcom6/go-helpers/cache"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T, id, code string) *Service {
+	t.Helper()
+
+	s := NewService(ServiceParams{Logger: zap.NewNop()})
+
+	validUntil := time.Now().Add(time.Minute)
+	err := s.codes.Set(id, entry{
+		UserID:      "user",
+		PhoneNumber: "+79990001234",
+		Hash:        hashCode(id, code),
+		ValidUntil:  validUntil,
+	}, cache.WithValidUntil(validUntil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestService_Verify(t *testing.T) {
+	s := newTestService(t, "id", "123456")
+
+	if _, err := s.Verify("other", "id", "123456"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Verify() of other user error = %v, want ErrNotFound", err)
+	}
+
+	out, err := s.Verify("user", "id", "000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Verified || out.AttemptsLeft != maxAttempts-1 {
+		t.Fatalf("Verify() = %+v, want not verified with %d attempts left", out, maxAttempts-1)
+	}
+
+	out, err = s.Verify("user", "id", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Verified {
+		t.Fatalf("Verify() = %+v, want verified", out)
+	}
+
+	if _, err := s.Verify("user", "id", "123456"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Verify() of used code error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestService_VerifyLockout(t *testing.T) {
+	s := newTestService(t, "id", "123456")
+
+	var err error
+	for range maxAttempts {
+		_, err = s.Verify("user", "id", "000000")
+	}
+
+	var errLocked LockedError
+	if !errors.As(err, &errLocked) {
+		t.Fatalf("Verify() error = %v, want LockedError", err)
+	}
+
+	for _, phone := range []string{"+79990001234", "79990001234", "89990001234", "+7 (999) 000-12-34"} {
+		if _, err := s.Send(models.Device{UserID: "user"}, SendIn{PhoneNumber: phone}); !errors.As(err, &errLocked) {
+			t.Fatalf("Send(%s) error = %v, want LockedError", phone, err)
+		}
+	}
+}
+
+func TestNewCode(t *testing.T) {
+	for _, length := range []int{4, 6, 10} {
+		code, err := newCode(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != length {
+			t.Errorf("newCode(%d) = %q", length, code)
+		}
+	}
+}
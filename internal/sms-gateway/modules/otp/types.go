@@ -0,0 +1,44 @@
+package otp
+
+import "time"
+
+const (
+	DefaultTemplate = "Your code is {{code}}"
+	DefaultLength   = 6
+	DefaultTTL      = 5 * time.Minute
+
+	// wrong codes allowed before the code is invalidated and the recipient is
+	// locked out
+	maxAttempts     = 5
+	lockoutDuration = 15 * time.Minute
+)
+
+type SendIn struct {
+	PhoneNumber string
+	// Message template with `{{code}}` placeholder
+	Template string
+	// Number of digits
+	Length int
+	TTL    time.Duration
+
+	SimNumber *uint8
+}
+
+type SendOut struct {
+	ID         string
+	MessageID  string
+	ValidUntil time.Time
+}
+
+type VerifyOut struct {
+	Verified     bool
+	AttemptsLeft int
+}
+
+type entry struct {
+	UserID      string
+	PhoneNumber string
+	Hash        string
+	Attempts    int
+	ValidUntil  time.Time
+}
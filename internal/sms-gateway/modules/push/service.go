@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
@@ -47,6 +48,7 @@ type Service struct {
 
 	cache     *cache.Cache[eventWrapper]
 	blacklist *cache.Cache[struct{}]
+	mux       sync.Mutex
 
 	enqueuedCounter  *prometheus.CounterVec
 	retriesCounter   *prometheus.CounterVec
@@ -118,6 +120,13 @@ func (s *Service) Run(ctx context.Context) {
 	}
 }
 
+// IsBlacklisted reports whether pushes to the token are skipped after repeated
+// delivery failures.
+func (s *Service) IsBlacklisted(token string) bool {
+	_, err := s.blacklist.Get(token)
+	return err == nil
+}
+
 // Enqueue adds the data to the cache and immediately sends all messages if the debounce is 0.
 func (s *Service) Enqueue(token string, event *domain.Event) error {
 	if _, err := s.blacklist.Get(token); err == nil {
@@ -126,11 +135,17 @@ func (s *Service) Enqueue(token string, event *domain.Event) error {
 		return nil
 	}
 
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
 	wrapper := eventWrapper{
 		token:   token,
 		event:   event,
 		retries: 0,
 	}
+	if pending, err := s.cache.Get(token); err == nil {
+		wrapper = pending.with(event)
+	}
 
 	if err := s.cache.Set(token, wrapper); err != nil {
 		return fmt.Errorf("can't add message to cache: %w", err)
@@ -190,7 +205,18 @@ func (s *Service) Notify(userID string, deviceID *string, event *domain.Event) e
 
 // sendAll sends messages to all targets from the cache after initializing the service.
 func (s *Service) sendAll(ctx context.Context) {
+	s.mux.Lock()
 	targets := s.cache.Drain()
+	// only one event per device is sent at once, the queued ones are sent next time
+	for token, wrapper := range targets {
+		if next, ok := wrapper.next(); ok {
+			if err := s.cache.Set(token, next); err != nil {
+				s.logger.Warn("Can't requeue message", zap.String("token", token), zap.Error(err))
+			}
+		}
+	}
+	s.mux.Unlock()
+
 	if len(targets) == 0 {
 		return
 	}
@@ -219,6 +245,8 @@ func (s *Service) sendAll(ctx context.Context) {
 
 		wrapper := targets[token]
 		wrapper.retries++
+		// the queue is already back in the cache
+		wrapper.queue = nil
 
 		if wrapper.retries >= maxRetries {
 			if err := s.blacklist.Set(token, struct{}{}); err != nil {
@@ -233,10 +261,23 @@ func (s *Service) sendAll(ctx context.Context) {
 			continue
 		}
 
-		if setErr := s.cache.SetOrFail(token, wrapper); setErr != nil {
+		if setErr := s.requeue(token, wrapper); setErr != nil {
 			s.logger.Info("Can't set message to cache", zap.Error(setErr))
 		}
 
 		s.retriesCounter.WithLabelValues(string(RetryOutcomeRetried)).Inc()
 	}
 }
+
+// requeue puts the failed event back in front of the events enqueued since
+// it was taken from the cache.
+func (s *Service) requeue(token string, wrapper eventWrapper) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if pending, err := s.cache.Get(token); err == nil {
+		wrapper = wrapper.merge(pending)
+	}
+
+	return s.cache.Set(token, wrapper)
+}
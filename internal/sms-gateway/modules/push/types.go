@@ -2,6 +2,7 @@ package push
 
 import (
 	"context"
+	"maps"
 	"time"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
@@ -28,6 +29,56 @@ type eventWrapper struct {
 	token   string
 	event   *domain.Event
 	retries int
+
+	// events sent after the current one, one per debounce interval
+	queue []*domain.Event
+}
+
+// with returns the wrapper with the event added. An event equal to the pending
+// or one of the queued ones is dropped, other events are queued, so events
+// with data, e.g. commands, are never overwritten.
+func (w eventWrapper) with(event *domain.Event) eventWrapper {
+	if sameEvent(w.event, event) {
+		return w
+	}
+
+	for _, e := range w.queue {
+		if sameEvent(e, event) {
+			return w
+		}
+	}
+
+	queue := make([]*domain.Event, 0, len(w.queue)+1)
+	queue = append(queue, w.queue...)
+	w.queue = append(queue, event)
+	return w
+}
+
+// merge returns the wrapper with the pending and the queued events of other
+// added after its own ones.
+func (w eventWrapper) merge(other eventWrapper) eventWrapper {
+	w = w.with(other.event)
+	for _, e := range other.queue {
+		w = w.with(e)
+	}
+	return w
+}
+
+// next returns the wrapper with the first queued event, if any.
+func (w eventWrapper) next() (eventWrapper, bool) {
+	if len(w.queue) == 0 {
+		return eventWrapper{}, false
+	}
+
+	return eventWrapper{
+		token: w.token,
+		event: w.queue[0],
+		queue: w.queue[1:],
+	}, true
+}
+
+func sameEvent(a, b *domain.Event) bool {
+	return a.Event() == b.Event() && maps.Equal(a.Data(), b.Data())
 }
 
 func NewMessageEnqueuedEvent() *domain.Event {
@@ -51,3 +102,24 @@ func NewMessagesExportRequestedEvent(since, until time.Time) *domain.Event {
 func NewSettingsUpdatedEvent() *domain.Event {
 	return domain.NewEvent(smsgateway.PushSettingsUpdated, nil)
 }
+
+// Remote command events, the device acknowledges the command by its ID.
+const (
+	PushPingRequested         smsgateway.PushEventType = "PingRequested"
+	PushSettingsSyncRequested smsgateway.PushEventType = "SettingsSyncRequested"
+	PushWebhooksSyncRequested smsgateway.PushEventType = "WebhooksSyncRequested"
+	PushQueueFlushRequested   smsgateway.PushEventType = "QueueFlushRequested"
+)
+
+// IsCommandEvent reports whether the event is a remote command event.
+func IsCommandEvent(event smsgateway.PushEventType) bool {
+	switch event {
+	case PushPingRequested, PushSettingsSyncRequested, PushWebhooksSyncRequested, PushQueueFlushRequested:
+		return true
+	}
+	return false
+}
+
+func NewCommandEvent(event smsgateway.PushEventType, commandID string) *domain.Event {
+	return domain.NewEvent(event, map[string]string{"commandId": commandID})
+}
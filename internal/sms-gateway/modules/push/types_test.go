@@ -0,0 +1,69 @@
+package push
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestEventWrapperQueue(t *testing.T) {
+	w := eventWrapper{token: "token", event: NewMessageEnqueuedEvent()}
+
+	w = w.with(NewCommandEvent(PushPingRequested, "1"))
+	w = w.with(NewSettingsUpdatedEvent())
+	w = w.with(NewMessageEnqueuedEvent())
+	w = w.with(NewCommandEvent(PushPingRequested, "2"))
+	w = w.with(NewSettingsUpdatedEvent())
+
+	expected := []struct {
+		event     smsgateway.PushEventType
+		commandID string
+	}{
+		{smsgateway.PushMessageEnqueued, ""},
+		{PushPingRequested, "1"},
+		{smsgateway.PushSettingsUpdated, ""},
+		{PushPingRequested, "2"},
+	}
+
+	assertQueue(t, w, expected)
+}
+
+func TestEventWrapperMerge(t *testing.T) {
+	failed := eventWrapper{token: "token", event: NewCommandEvent(PushPingRequested, "1"), retries: 1}
+	pending := eventWrapper{token: "token", event: NewMessageEnqueuedEvent()}
+	pending = pending.with(NewCommandEvent(PushPingRequested, "1"))
+	pending = pending.with(NewWebhooksUpdatedEvent())
+
+	w := failed.merge(pending)
+	if w.retries != 1 {
+		t.Fatalf("retries = %d, want 1", w.retries)
+	}
+
+	assertQueue(t, w, []struct {
+		event     smsgateway.PushEventType
+		commandID string
+	}{
+		{PushPingRequested, "1"},
+		{smsgateway.PushMessageEnqueued, ""},
+		{smsgateway.PushWebhooksUpdated, ""},
+	})
+}
+
+func assertQueue(t *testing.T, w eventWrapper, expected []struct {
+	event     smsgateway.PushEventType
+	commandID string
+}) {
+	t.Helper()
+
+	for i, e := range expected {
+		if w.event.Event() != e.event || w.event.Data()["commandId"] != e.commandID {
+			t.Fatalf("event %d = %s %v, want %s %s", i, w.event.Event(), w.event.Data(), e.event, e.commandID)
+		}
+
+		var ok bool
+		w, ok = w.next()
+		if ok != (i < len(expected)-1) {
+			t.Fatalf("next() after event %d = %v", i, ok)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package quotas
+
+import (
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// periods are tracked independently of the configured limit, so the limit
+// can be changed at any time.
+var periods = []smsgateway.LimitPeriod{
+	smsgateway.PerMinute,
+	smsgateway.PerHour,
+	smsgateway.PerDay,
+}
+
+// periodStart returns the start of the period containing t in UTC.
+func periodStart(period smsgateway.LimitPeriod, t time.Time) time.Time {
+	t = t.UTC()
+
+	switch period {
+	case smsgateway.PerMinute:
+		return t.Truncate(time.Minute)
+	case smsgateway.PerHour:
+		return t.Truncate(time.Hour)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+type LimitOut struct {
+	// Limit period
+	Period smsgateway.LimitPeriod `json:"period" enums:"PerMinute,PerHour,PerDay" example:"PerDay"`
+	// Maximum number of messages per period
+	Value int `json:"value" example:"100"`
+}
+
+type CountersOut struct {
+	// SIM card number, empty for messages sent via the SIM card selected by the device
+	SIMNumber *uint8 `json:"simNumber,omitempty" example:"1"`
+	// Sent in the current minute
+	PerMinute uint `json:"perMinute" example:"1"`
+	// Sent in the current hour
+	PerHour uint `json:"perHour" example:"10"`
+	// Sent in the current day (UTC)
+	PerDay uint `json:"perDay" example:"50"`
+}
+
+type UsageOut struct {
+	// Sending limit from the settings, applied to the device and each of its SIM cards
+	Limit *LimitOut `json:"limit,omitempty"`
+	// Total number of messages sent by the device
+	Total CountersOut `json:"total"`
+	// Number of messages sent by SIM card
+	SIMs []CountersOut `json:"sims"`
+}
@@ -0,0 +1,29 @@
+package quotas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+func TestPeriodStart(t *testing.T) {
+	at := time.Date(2025, 8, 7, 23, 45, 30, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	tests := []struct {
+		period   smsgateway.LimitPeriod
+		expected time.Time
+	}{
+		{smsgateway.PerMinute, time.Date(2025, 8, 8, 1, 45, 0, 0, time.UTC)},
+		{smsgateway.PerHour, time.Date(2025, 8, 8, 1, 0, 0, 0, time.UTC)},
+		{smsgateway.PerDay, time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			if got := periodStart(tt.period, at); !got.Equal(tt.expected) {
+				t.Errorf("periodStart() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
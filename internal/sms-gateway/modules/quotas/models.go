@@ -0,0 +1,33 @@
+package quotas
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// Counter is the number of messages sent by the device SIM card in the
+// period. SIM number 0 is used for messages without an explicit SIM card.
+type Counter struct {
+	DeviceID    string                 `gorm:"primaryKey;type:char(21)"`
+	SIMNumber   uint8                  `gorm:"primaryKey;type:tinyint(1) unsigned"`
+	Period      smsgateway.LimitPeriod `gorm:"primaryKey;type:varchar(16)"`
+	PeriodStart time.Time              `gorm:"primaryKey;type:datetime;index:idx_device_send_counters_period_start"`
+	Count       uint                   `gorm:"not null;type:int unsigned;default:0"`
+
+	Device models.Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func (Counter) TableName() string {
+	return "device_send_counters"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Counter{}); err != nil {
+		return fmt.Errorf("device_send_counters migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package quotas
+
+import (
+	"context"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// Increment adds n to the counters of all the periods containing at.
+func (r *repository) Increment(deviceID string, simNumber uint8, n uint, at time.Time) error {
+	counters := make([]Counter, 0, len(periods))
+	for _, period := range periods {
+		counters = append(counters, Counter{
+			DeviceID:    deviceID,
+			SIMNumber:   simNumber,
+			Period:      period,
+			PeriodStart: periodStart(period, at),
+			Count:       n,
+		})
+	}
+
+	return r.db.
+		Omit("Device").
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("`count` + ?", n)}),
+		}).
+		Create(&counters).
+		Error
+}
+
+// Select returns the counters of the devices for the periods containing at.
+func (r *repository) Select(deviceIDs []string, at time.Time, period ...smsgateway.LimitPeriod) ([]Counter, error) {
+	if len(period) == 0 {
+		period = periods
+	}
+
+	conditions := r.db.Where("1 = 0")
+	for _, p := range period {
+		conditions = conditions.Or("period = ? AND period_start = ?", p, periodStart(p, at))
+	}
+
+	counters := []Counter{}
+	return counters, r.db.
+		Where("device_id IN ?", deviceIDs).
+		Where(conditions).
+		Find(&counters).
+		Error
+}
+
+func (r *repository) removeOlder(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("period_start < ?", until).
+		Delete(&Counter{})
+
+	return res.RowsAffected, res.Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
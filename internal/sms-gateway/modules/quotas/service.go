@@ -0,0 +1,190 @@
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Counters are kept for the previous day to cover the whole current one.
+const lifetime = 48 * time.Hour
+
+type ServiceParams struct {
+	fx.In
+
+	Counters *repository
+
+	SettingsSvc *settings.Service
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	counters *repository
+
+	settingsSvc *settings.Service
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		counters: params.Counters,
+
+		settingsSvc: params.SettingsSvc,
+
+		logger: params.Logger.Named("service"),
+	}
+}
+
+// Record counts messages sent by the device. Nil SIM number is used when the
+// SIM card is selected by the device.
+func (s *Service) Record(deviceID string, simNumber *uint8, count uint) error {
+	if count == 0 {
+		return nil
+	}
+
+	sim := uint8(0)
+	if simNumber != nil {
+		sim = *simNumber
+	}
+
+	if err := s.counters.Increment(deviceID, sim, count, time.Now()); err != nil {
+		return fmt.Errorf("can't increment counters: %w", err)
+	}
+
+	return nil
+}
+
+// Usage returns the current counters of the device along with the user's
+// sending limit.
+func (s *Service) Usage(device models.Device) (UsageOut, error) {
+	usage := UsageOut{SIMs: []CountersOut{}}
+
+	period, value, err := s.settingsSvc.GetSendingLimit(device.UserID)
+	if err != nil {
+		return usage, fmt.Errorf("can't get sending limit: %w", err)
+	}
+	if value > 0 {
+		usage.Limit = &LimitOut{Period: period, Value: value}
+	}
+
+	counters, err := s.counters.Select([]string{device.ID}, time.Now())
+	if err != nil {
+		return usage, fmt.Errorf("can't select counters: %w", err)
+	}
+
+	sims := map[uint8]*CountersOut{}
+	for _, c := range counters {
+		sim, ok := sims[c.SIMNumber]
+		if !ok {
+			sim = &CountersOut{}
+			if c.SIMNumber > 0 {
+				sim.SIMNumber = &c.SIMNumber
+			}
+			sims[c.SIMNumber] = sim
+		}
+
+		sim.add(c.Period, c.Count)
+		usage.Total.add(c.Period, c.Count)
+	}
+
+	for _, n := range slices.Sorted(maps.Keys(sims)) {
+		usage.SIMs = append(usage.SIMs, *sims[n])
+	}
+
+	return usage, nil
+}
+
+// Available returns the devices that haven't reached the user's sending limit
+// in the current period. All devices are returned if there is no limit.
+func (s *Service) Available(userID string, devices []models.Device) ([]models.Device, error) {
+	if len(devices) == 0 {
+		return devices, nil
+	}
+
+	period, value, err := s.settingsSvc.GetSendingLimit(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get sending limit: %w", err)
+	}
+	if value == 0 {
+		return devices, nil
+	}
+
+	ids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		ids = append(ids, d.ID)
+	}
+
+	counters, err := s.counters.Select(ids, time.Now(), period)
+	if err != nil {
+		return nil, fmt.Errorf("can't select counters: %w", err)
+	}
+
+	totals := make(map[string]uint, len(devices))
+	for _, c := range counters {
+		totals[c.DeviceID] += c.Count
+	}
+
+	available := make([]models.Device, 0, len(devices))
+	for _, d := range devices {
+		if totals[d.ID] < uint(value) {
+			available = append(available, d)
+		}
+	}
+
+	return available, nil
+}
+
+// SIMAvailable reports whether neither the device nor its SIM card have
+// reached the user's sending limit in the current period.
+func (s *Service) SIMAvailable(device models.Device, simNumber uint8) (bool, error) {
+	period, value, err := s.settingsSvc.GetSendingLimit(device.UserID)
+	if err != nil {
+		return false, fmt.Errorf("can't get sending limit: %w", err)
+	}
+	if value == 0 {
+		return true, nil
+	}
+
+	counters, err := s.counters.Select([]string{device.ID}, time.Now(), period)
+	if err != nil {
+		return false, fmt.Errorf("can't select counters: %w", err)
+	}
+
+	total, sim := uint(0), uint(0)
+	for _, c := range counters {
+		total += c.Count
+		if c.SIMNumber == simNumber {
+			sim += c.Count
+		}
+	}
+
+	return total < uint(value) && sim < uint(value), nil
+}
+
+func (s *Service) Clean(ctx context.Context) error {
+	n, err := s.counters.removeOlder(ctx, time.Now().Add(-lifetime))
+
+	s.logger.Info("Cleaned send counters", zap.Int64("count", n))
+	return err
+}
+
+func (c *CountersOut) add(period smsgateway.LimitPeriod, n uint) {
+	switch period {
+	case smsgateway.PerMinute:
+		c.PerMinute += n
+	case smsgateway.PerHour:
+		c.PerHour += n
+	case smsgateway.PerDay:
+		c.PerDay += n
+	}
+}
@@ -1,11 +1,16 @@
 package settings
 
 import (
+	"errors"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
+var ErrInvalidSettings = errors.New("invalid settings")
+
 type ServiceParams struct {
 	fx.In
 
@@ -37,12 +42,72 @@ func (s *Service) GetSettings(userID string, public bool) (map[string]any, error
 	return filterMap(settings.Settings, rulesPublic)
 }
 
+// GetSendingWindow returns the user's default sending window bounds in `HH:MM`
+// format. The window is set only if both bounds are not empty.
+func (s *Service) GetSendingWindow(userID string) (start, end string, err error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	messages, _ := settings.Settings["messages"].(map[string]any)
+	start, _ = messages["sending_window_start"].(string)
+	end, _ = messages["sending_window_end"].(string)
+
+	return start, end, nil
+}
+
+// GetSendingLimit returns the user's limit of messages per period sent by
+// each device. Zero value means no limit.
+func (s *Service) GetSendingLimit(userID string) (smsgateway.LimitPeriod, int, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	messages, _ := settings.Settings["messages"].(map[string]any)
+	period, _ := messages["limit_period"].(string)
+
+	// numbers are decoded from JSON as float64
+	value := 0
+	switch v := messages["limit_value"].(type) {
+	case float64:
+		value = int(v)
+	case int:
+		value = v
+	}
+
+	if period == "" || smsgateway.LimitPeriod(period) == smsgateway.Disabled || value <= 0 {
+		return smsgateway.Disabled, 0, nil
+	}
+
+	return smsgateway.LimitPeriod(period), value, nil
+}
+
+// GetWebhooksSigningKey returns the user's key for signing webhook payloads,
+// empty if not set.
+func (s *Service) GetWebhooksSigningKey(userID string) (string, error) {
+	settings, err := s.settings.GetSettings(userID)
+	if err != nil {
+		return "", err
+	}
+
+	webhooks, _ := settings.Settings["webhooks"].(map[string]any)
+	key, _ := webhooks["signing_key"].(string)
+
+	return key, nil
+}
+
 func (s *Service) UpdateSettings(userID string, settings map[string]any) (map[string]any, error) {
 	filtered, err := filterMap(settings, rules)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := validateSendingWindow(filtered); err != nil {
+		return nil, err
+	}
+
 	updatedSettings, err := s.settings.UpdateSettings(&DeviceSettings{
 		UserID:   userID,
 		Settings: filtered,
@@ -62,6 +127,10 @@ func (s *Service) ReplaceSettings(userID string, settings map[string]any) (map[s
 		return nil, err
 	}
 
+	if err := validateSendingWindow(filtered); err != nil {
+		return nil, err
+	}
+
 	updated, err := s.settings.ReplaceSettings(&DeviceSettings{
 		UserID:   userID,
 		Settings: filtered,
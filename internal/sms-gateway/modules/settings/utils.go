@@ -1,18 +1,23 @@
 package settings
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 var rules = map[string]any{
 	"encryption": map[string]any{
 		"passphrase": "",
 	},
 	"messages": map[string]any{
-		"send_interval_min":  "",
-		"send_interval_max":  "",
-		"limit_period":       "",
-		"limit_value":        "",
-		"sim_selection_mode": "",
-		"log_lifetime_days":  "",
+		"send_interval_min":    "",
+		"send_interval_max":    "",
+		"limit_period":         "",
+		"limit_value":          "",
+		"sim_selection_mode":   "",
+		"log_lifetime_days":    "",
+		"sending_window_start": "",
+		"sending_window_end":   "",
 	},
 	"ping": map[string]any{
 		"interval_seconds": "",
@@ -30,12 +35,14 @@ var rules = map[string]any{
 var rulesPublic = map[string]any{
 	"encryption": map[string]any{},
 	"messages": map[string]any{
-		"send_interval_min":  "",
-		"send_interval_max":  "",
-		"limit_period":       "",
-		"limit_value":        "",
-		"sim_selection_mode": "",
-		"log_lifetime_days":  "",
+		"send_interval_min":    "",
+		"send_interval_max":    "",
+		"limit_period":         "",
+		"limit_value":          "",
+		"sim_selection_mode":   "",
+		"log_lifetime_days":    "",
+		"sending_window_start": "",
+		"sending_window_end":   "",
 	},
 	"ping": map[string]any{
 		"interval_seconds": "",
@@ -110,3 +117,29 @@ func appendMap(m1, m2 map[string]any, rules map[string]any) (map[string]any, err
 
 	return m1, nil
 }
+
+// validateSendingWindow checks that the sending window bounds, if set, are
+// strings in `HH:MM` format.
+func validateSendingWindow(m map[string]any) error {
+	messages, ok := m["messages"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for _, key := range []string{"sending_window_start", "sending_window_end"} {
+		value, ok := messages[key]
+		if !ok || value == nil {
+			continue
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: messages.%s must be a string", ErrInvalidSettings, key)
+		}
+		if _, err := time.Parse("15:04", str); err != nil {
+			return fmt.Errorf("%w: messages.%s must be in HH:MM format", ErrInvalidSettings, key)
+		}
+	}
+
+	return nil
+}
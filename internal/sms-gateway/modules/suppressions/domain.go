@@ -0,0 +1,17 @@
+package suppressions
+
+import "time"
+
+type EntryIn struct {
+	// Phone number
+	PhoneNumber string `json:"phoneNumber" validate:"required,max=128" example:"79990001234"`
+	// Reason
+	Reason string `json:"reason,omitempty" validate:"max=256" example:"STOP"`
+}
+
+type EntryOut struct {
+	EntryIn
+
+	// Time the number was added
+	CreatedAt time.Time `json:"createdAt" example:"2020-01-01T00:00:00Z"`
+}
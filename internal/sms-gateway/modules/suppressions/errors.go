@@ -0,0 +1,5 @@
+package suppressions
+
+import "gorm.io/gorm"
+
+var ErrNotFound = gorm.ErrRecordNotFound
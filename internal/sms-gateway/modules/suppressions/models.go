@@ -0,0 +1,30 @@
+package suppressions
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// Entry is a phone number messages must not be sent to
+type Entry struct {
+	UserID      string `gorm:"primaryKey;type:varchar(32)"`
+	PhoneNumber string `gorm:"primaryKey;type:varchar(128)"`
+	Reason      string `gorm:"not null;type:varchar(256);default:''"`
+
+	User models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (Entry) TableName() string {
+	return "suppression_list"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Entry{}); err != nil {
+		return fmt.Errorf("suppression_list migration failed: %w", err)
+	}
+	return nil
+}
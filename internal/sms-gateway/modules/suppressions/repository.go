@@ -0,0 +1,44 @@
+package suppressions
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Select(userID string, phoneNumbers ...string) ([]Entry, error) {
+	entries := []Entry{}
+
+	query := r.db.Where("user_id = ?", userID)
+	if len(phoneNumbers) > 0 {
+		query = query.Where("phone_number IN ?", phoneNumbers)
+	}
+
+	return entries, query.Order("created_at DESC").Find(&entries).Error
+}
+
+// Upsert adds the entry or updates the reason of the existing one.
+func (r *repository) Upsert(entry *Entry) error {
+	return r.db.
+		Omit("User").
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"reason"})}).
+		Create(entry).
+		Error
+}
+
+func (r *repository) Delete(userID, phoneNumber string) (int64, error) {
+	res := r.db.
+		Where("user_id = ? AND phone_number = ?", userID, phoneNumber).
+		Delete(&Entry{})
+
+	return res.RowsAffected, res.Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
@@ -0,0 +1,124 @@
+package suppressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/capcom6/go-helpers/slices"
+	"github.com/nyaruka/phonenumbers"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type ServiceParams struct {
+	fx.In
+
+	Entries *repository
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	entries *repository
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		entries: params.Entries,
+		logger:  params.Logger.Named("service"),
+	}
+}
+
+func (s *Service) Select(userID string) ([]EntryOut, error) {
+	entries, err := s.entries.Select(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't select suppression list: %w", err)
+	}
+
+	return slices.Map(entries, entryToDTO), nil
+}
+
+// Add adds the phone number to the user's suppression list.
+func (s *Service) Add(userID string, entry EntryIn) (EntryOut, error) {
+	model := Entry{
+		UserID:      userID,
+		PhoneNumber: normalize(entry.PhoneNumber),
+		Reason:      entry.Reason,
+	}
+
+	if err := s.entries.Upsert(&model); err != nil {
+		return EntryOut{}, fmt.Errorf("can't add to suppression list: %w", err)
+	}
+
+	entries, err := s.entries.Select(userID, model.PhoneNumber)
+	if err != nil {
+		return EntryOut{}, fmt.Errorf("can't get suppression list entry: %w", err)
+	}
+	if len(entries) == 0 {
+		return EntryOut{}, ErrNotFound
+	}
+
+	return entryToDTO(entries[0]), nil
+}
+
+// Remove removes the phone number from the user's suppression list.
+func (s *Service) Remove(userID, phoneNumber string) error {
+	affected, err := s.entries.Delete(userID, normalize(phoneNumber))
+	if err != nil {
+		return fmt.Errorf("can't remove from suppression list: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Filter returns the phone numbers that are in the user's suppression list.
+func (s *Service) Filter(userID string, phoneNumbers []string) (map[string]struct{}, error) {
+	if len(phoneNumbers) == 0 {
+		return map[string]struct{}{}, nil
+	}
+
+	normalized := make(map[string]string, len(phoneNumbers))
+	for _, phone := range phoneNumbers {
+		normalized[normalize(phone)] = phone
+	}
+
+	entries, err := s.entries.Select(userID, slices.Map(phoneNumbers, normalize)...)
+	if err != nil {
+		return nil, fmt.Errorf("can't select suppression list: %w", err)
+	}
+
+	suppressed := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		suppressed[normalized[entry.PhoneNumber]] = struct{}{}
+	}
+
+	return suppressed, nil
+}
+
+// normalize formats valid phone numbers as E.164, so numbers reported by
+// devices match numbers of outgoing messages.
+func normalize(phoneNumber string) string {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+
+	phone, err := phonenumbers.Parse(phoneNumber, "RU")
+	if err != nil || !phonenumbers.IsValidNumber(phone) {
+		return phoneNumber
+	}
+
+	return phonenumbers.Format(phone, phonenumbers.E164)
+}
+
+func entryToDTO(entry Entry) EntryOut {
+	return EntryOut{
+		EntryIn: EntryIn{
+			PhoneNumber: entry.PhoneNumber,
+			Reason:      entry.Reason,
+		},
+		CreatedAt: entry.CreatedAt,
+	}
+}
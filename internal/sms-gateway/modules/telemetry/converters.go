@@ -0,0 +1,14 @@
+package telemetry
+
+func sampleToDTO(sample Sample) ReportOut {
+	return ReportOut{
+		ReportIn: ReportIn{
+			BatteryLevel:   sample.BatteryLevel,
+			IsCharging:     sample.IsCharging,
+			SignalStrength: sample.SignalStrength,
+			NetworkType:    NetworkType(sample.NetworkType),
+			AppVersion:     sample.AppVersion,
+		},
+		ReportedAt: sample.CreatedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package telemetry
+
+import "time"
+
+type NetworkType string
+
+const (
+	NetworkTypeNone     NetworkType = "none"
+	NetworkTypeWiFi     NetworkType = "wifi"
+	NetworkTypeCellular NetworkType = "cellular"
+	NetworkTypeEthernet NetworkType = "ethernet"
+)
+
+type ReportIn struct {
+	// Battery level in percent
+	BatteryLevel *uint8 `json:"batteryLevel,omitempty" validate:"omitempty,max=100" example:"85"`
+	// Is the device charging
+	IsCharging *bool `json:"isCharging,omitempty" example:"false"`
+	// Cellular signal strength in dBm
+	SignalStrength *int16 `json:"signalStrength,omitempty" validate:"omitempty,min=-150,max=0" example:"-85"`
+	// Internet connection type
+	NetworkType NetworkType `json:"networkType,omitempty" validate:"omitempty,oneof=none wifi cellular ethernet" example:"wifi"`
+	// App version
+	AppVersion string `json:"appVersion,omitempty" validate:"max=32" example:"1.30.0"`
+}
+
+type ReportOut struct {
+	ReportIn
+
+	// Reported at
+	ReportedAt time.Time `json:"reportedAt" example:"2020-01-01T00:00:00Z"`
+}
@@ -0,0 +1,35 @@
+package telemetry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+// Sample is the device state reported by the app
+type Sample struct {
+	ID             uint64 `gorm:"primaryKey;type:BIGINT UNSIGNED;autoIncrement"`
+	DeviceID       string `gorm:"not null;type:char(21);index:idx_device_telemetry_device"`
+	BatteryLevel   *uint8 `gorm:"type:tinyint unsigned"`
+	IsCharging     *bool  `gorm:"type:tinyint(1) unsigned"`
+	SignalStrength *int16 `gorm:"type:smallint"`
+	NetworkType    string `gorm:"not null;type:varchar(16);default:''"`
+	AppVersion     string `gorm:"not null;type:varchar(32);default:''"`
+
+	CreatedAt time.Time `gorm:"->;not null;autocreatetime:false;default:CURRENT_TIMESTAMP(3);index:idx_device_telemetry_created_at"`
+
+	Device models.Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func (Sample) TableName() string {
+	return "device_telemetry"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Sample{}); err != nil {
+		return fmt.Errorf("device_telemetry migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Insert(sample *Sample) error {
+	return r.db.Omit("Device").Create(sample).Error
+}
+
+// Latest returns the latest sample of each device.
+func (r *repository) Latest(deviceIDs ...string) ([]Sample, error) {
+	samples := []Sample{}
+
+	latest := r.db.
+		Model(&Sample{}).
+		Select("MAX(id)").
+		Where("device_id IN ?", deviceIDs).
+		Group("device_id")
+
+	return samples, r.db.Where("id IN (?)", latest).Find(&samples).Error
+}
+
+// Select returns samples of the device, newest first.
+func (r *repository) Select(deviceID string, limit int) ([]Sample, error) {
+	samples := []Sample{}
+
+	return samples, r.db.
+		Where("device_id = ?", deviceID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&samples).
+		Error
+}
+
+func (r *repository) removeOlder(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("created_at < ?", until).
+		Delete(&Sample{})
+
+	return res.RowsAffected, res.Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
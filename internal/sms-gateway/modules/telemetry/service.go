@@ -0,0 +1,192 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Samples older than this are removed, as well as gauges of devices that
+// haven't reported since.
+const historyLifetime = 7 * 24 * time.Hour
+
+type ServiceParams struct {
+	fx.In
+
+	Samples *repository
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	samples *repository
+
+	batteryGauge  *prometheus.GaugeVec
+	chargingGauge *prometheus.GaugeVec
+	signalGauge   *prometheus.GaugeVec
+	infoGauge     *prometheus.GaugeVec
+
+	// labels of the exported info series and time of the last report by device
+	mux      sync.Mutex
+	exported map[string]exportedDevice
+
+	logger *zap.Logger
+}
+
+type exportedDevice struct {
+	info       prometheus.Labels
+	reportedAt time.Time
+}
+
+func NewService(params ServiceParams) *Service {
+	return &Service{
+		samples: params.Samples,
+
+		batteryGauge: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sms",
+			Subsystem: "device",
+			Name:      "battery_level",
+			Help:      "Battery level in percent",
+		}, []string{"device_id"}),
+		chargingGauge: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sms",
+			Subsystem: "device",
+			Name:      "charging",
+			Help:      "Whether the device is charging",
+		}, []string{"device_id"}),
+		signalGauge: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sms",
+			Subsystem: "device",
+			Name:      "signal_strength_dbm",
+			Help:      "Cellular signal strength in dBm",
+		}, []string{"device_id"}),
+		infoGauge: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sms",
+			Subsystem: "device",
+			Name:      "info",
+			Help:      "Network type and app version of the device",
+		}, []string{"device_id", "network_type", "app_version"}),
+
+		exported: map[string]exportedDevice{},
+
+		logger: params.Logger.Named("service"),
+	}
+}
+
+// Report stores the device state and updates the gauges.
+func (s *Service) Report(device models.Device, report ReportIn) error {
+	sample := Sample{
+		DeviceID:       device.ID,
+		BatteryLevel:   report.BatteryLevel,
+		IsCharging:     report.IsCharging,
+		SignalStrength: report.SignalStrength,
+		NetworkType:    string(report.NetworkType),
+		AppVersion:     report.AppVersion,
+	}
+
+	if err := s.samples.Insert(&sample); err != nil {
+		return fmt.Errorf("can't insert telemetry: %w", err)
+	}
+
+	s.export(device.ID, report)
+
+	return nil
+}
+
+// Latest returns the latest report of each device that has reported.
+func (s *Service) Latest(deviceIDs ...string) (map[string]ReportOut, error) {
+	result := make(map[string]ReportOut, len(deviceIDs))
+	if len(deviceIDs) == 0 {
+		return result, nil
+	}
+
+	samples, err := s.samples.Latest(deviceIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("can't select telemetry: %w", err)
+	}
+
+	for _, sample := range samples {
+		result[sample.DeviceID] = sampleToDTO(sample)
+	}
+
+	return result, nil
+}
+
+// History returns the latest reports of the device, newest first.
+func (s *Service) History(deviceID string, limit int) ([]ReportOut, error) {
+	samples, err := s.samples.Select(deviceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't select telemetry: %w", err)
+	}
+
+	result := make([]ReportOut, 0, len(samples))
+	for _, sample := range samples {
+		result = append(result, sampleToDTO(sample))
+	}
+
+	return result, nil
+}
+
+func (s *Service) Clean(ctx context.Context) error {
+	until := time.Now().Add(-historyLifetime)
+
+	n, err := s.samples.removeOlder(ctx, until)
+	s.logger.Info("Cleaned telemetry", zap.Int64("count", n))
+
+	s.mux.Lock()
+	for deviceID, d := range s.exported {
+		if d.reportedAt.Before(until) {
+			s.unexport(deviceID, d)
+		}
+	}
+	s.mux.Unlock()
+
+	return err
+}
+
+func (s *Service) export(deviceID string, report ReportIn) {
+	labels := prometheus.Labels{"device_id": deviceID}
+
+	if report.BatteryLevel != nil {
+		s.batteryGauge.With(labels).Set(float64(*report.BatteryLevel))
+	}
+	if report.IsCharging != nil {
+		s.chargingGauge.With(labels).Set(map[bool]float64{false: 0, true: 1}[*report.IsCharging])
+	}
+	if report.SignalStrength != nil {
+		s.signalGauge.With(labels).Set(float64(*report.SignalStrength))
+	}
+
+	info := prometheus.Labels{
+		"device_id":    deviceID,
+		"network_type": string(report.NetworkType),
+		"app_version":  report.AppVersion,
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if prev, ok := s.exported[deviceID]; ok {
+		s.infoGauge.Delete(prev.info)
+	}
+	s.infoGauge.With(info).Set(1)
+	s.exported[deviceID] = exportedDevice{info: info, reportedAt: time.Now()}
+}
+
+func (s *Service) unexport(deviceID string, d exportedDevice) {
+	labels := prometheus.Labels{"device_id": deviceID}
+
+	s.batteryGauge.Delete(labels)
+	s.chargingGauge.Delete(labels)
+	s.signalGauge.Delete(labels)
+	s.infoGauge.Delete(d.info)
+
+	delete(s.exported, deviceID)
+}
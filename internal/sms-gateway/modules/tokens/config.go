@@ -0,0 +1,50 @@
+package tokens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+type Config struct {
+	// Keys are used to verify tokens, the first one signs new tokens
+	Keys []SigningKey
+
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// placeholderSecret marks example secrets that must be replaced before use.
+const placeholderSecret = "change-me"
+
+// ParseKeys parses signing keys in the `id:secret` form. Secrets copied from
+// the example config are rejected.
+func ParseKeys(keys []string) ([]SigningKey, error) {
+	result := make([]SigningKey, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		id, secret, ok := strings.Cut(key, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid signing key: expected id:secret")
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("signing key %s is too short: at least 32 characters expected", id)
+		}
+		if strings.Contains(strings.ToLower(secret), placeholderSecret) {
+			return nil, fmt.Errorf("signing key %s is a placeholder: generate a random secret", id)
+		}
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("duplicate signing key id: %s", id)
+		}
+		seen[id] = struct{}{}
+
+		result = append(result, SigningKey{ID: id, Secret: []byte(secret)})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package tokens
+
+import "time"
+
+type TokenPair struct {
+	// Access token
+	AccessToken string `json:"accessToken" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	// Token type
+	TokenType string `json:"tokenType" example:"Bearer"`
+	// Access token lifetime in seconds
+	ExpiresIn int `json:"expiresIn" example:"900"`
+	// Refresh token, can be used once
+	RefreshToken string `json:"refreshToken" example:"PyDmBQZZXYmyxMwED8FzyPyDmBQZZXYmyxMwED8Fzy"`
+	// Refresh token expiration time
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt" example:"2020-01-01T00:00:00Z"`
+}
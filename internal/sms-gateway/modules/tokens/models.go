@@ -0,0 +1,33 @@
+package tokens
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"gorm.io/gorm"
+)
+
+type RefreshToken struct {
+	ID        string `gorm:"primaryKey;type:char(21)"`
+	UserID    string `gorm:"not null;type:varchar(32);index:idx_refresh_tokens_user"`
+	TokenHash string `gorm:"not null;type:char(64);uniqueIndex"`
+
+	ExpiresAt time.Time  `gorm:"not null;type:datetime(3);index:idx_refresh_tokens_expires_at"`
+	RevokedAt *time.Time `gorm:"type:datetime(3)"`
+
+	User models.User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+
+	models.TimedModel
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&RefreshToken{}); err != nil {
+		return fmt.Errorf("refresh_tokens migration failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package tokens
+
+import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/cleaner"
+	"github.com/capcom6/go-infra-fx/db"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type FxResult struct {
+	fx.Out
+
+	Service   *Service
+	AsCleaner cleaner.Cleanable `group:"cleaners"`
+}
+
+var Module = fx.Module(
+	"tokens",
+	fx.Decorate(func(log *zap.Logger) *zap.Logger {
+		return log.Named("tokens")
+	}),
+	fx.Provide(
+		newRepository,
+		fx.Private,
+	),
+	fx.Provide(func(p ServiceParams) (FxResult, error) {
+		svc, err := NewService(p)
+		if err != nil {
+			return FxResult{}, err
+		}
+		return FxResult{
+			Service:   svc,
+			AsCleaner: svc,
+		}, nil
+	}),
+)
+
+func init() {
+	db.RegisterMigration(Migrate)
+}
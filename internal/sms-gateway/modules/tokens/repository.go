@@ -0,0 +1,63 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func (r *repository) Insert(token *RefreshToken) error {
+	return r.db.Omit("User").Create(token).Error
+}
+
+func (r *repository) GetByHash(hash string) (RefreshToken, error) {
+	token := RefreshToken{}
+
+	err := r.db.Where("token_hash = ?", hash).Take(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return token, ErrInvalidToken
+	}
+
+	return token, err
+}
+
+// Revoke marks the token as revoked. It returns false if the token was already
+// revoked.
+func (r *repository) Revoke(id string) (bool, error) {
+	res := r.db.
+		Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// RevokeAll revokes all the user's tokens.
+func (r *repository) RevokeAll(userID string) error {
+	return r.db.
+		Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+func (r *repository) removeExpired(ctx context.Context, until time.Time) (int64, error) {
+	res := r.db.
+		WithContext(ctx).
+		Where("expires_at < ?", until).
+		Delete(&RefreshToken{})
+
+	return res.RowsAffected, res.Error
+}
+
+func newRepository(db *gorm.DB) *repository {
+	return &repository{
+		db: db,
+	}
+}
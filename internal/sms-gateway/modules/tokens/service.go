@@ -0,0 +1,207 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jaevor/go-nanoid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const issuer = "sms-gate"
+
+type ServiceParams struct {
+	fx.In
+
+	Config Config
+
+	Tokens *repository
+
+	IDGen db.IDGen
+
+	Logger *zap.Logger
+}
+
+type Service struct {
+	config Config
+
+	keys map[string][]byte
+
+	tokens *repository
+
+	idGen    db.IDGen
+	tokenGen func() string
+
+	logger *zap.Logger
+}
+
+func NewService(params ServiceParams) (*Service, error) {
+	logger := params.Logger.Named("service")
+
+	if len(params.Config.Keys) == 0 {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("can't generate signing key: %w", err)
+		}
+
+		params.Config.Keys = []SigningKey{{ID: "ephemeral", Secret: secret}}
+		logger.Warn("No JWT signing keys configured, using an ephemeral key: tokens are invalidated on restart and work only on the instance that issued them, configure jwt.keys when running more than one instance")
+	}
+
+	keys := make(map[string][]byte, len(params.Config.Keys))
+	for _, key := range params.Config.Keys {
+		keys[key.ID] = key.Secret
+	}
+
+	tokenGen, err := nanoid.Standard(43)
+	if err != nil {
+		return nil, fmt.Errorf("can't create token generator: %w", err)
+	}
+
+	return &Service{
+		config: params.Config,
+
+		keys: keys,
+
+		tokens: params.Tokens,
+
+		idGen:    params.IDGen,
+		tokenGen: tokenGen,
+
+		logger: logger,
+	}, nil
+}
+
+// Issue returns a new access token and a refresh token for the user.
+func (s *Service) Issue(userID string) (TokenPair, error) {
+	now := time.Now()
+	signing := s.config.Keys[0]
+
+	access := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   userID,
+		ID:        s.idGen(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.config.AccessTTL)),
+	})
+	access.Header["kid"] = signing.ID
+
+	accessToken, err := access.SignedString(signing.Secret)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("can't sign access token: %w", err)
+	}
+
+	refreshToken := s.tokenGen()
+	refresh := RefreshToken{
+		ID:        s.idGen(),
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	}
+	if err := s.tokens.Insert(&refresh); err != nil {
+		return TokenPair{}, fmt.Errorf("can't insert refresh token: %w", err)
+	}
+
+	return TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.config.AccessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// Refresh exchanges the refresh token for a new token pair. A refresh token
+// can be used only once, reuse of a used token revokes all the user's tokens.
+func (s *Service) Refresh(refreshToken string) (TokenPair, error) {
+	token, err := s.tokens.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if !token.ExpiresAt.After(time.Now()) {
+		return TokenPair{}, ErrInvalidToken
+	}
+
+	revoked, err := s.tokens.Revoke(token.ID)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("can't revoke refresh token: %w", err)
+	}
+	if !revoked {
+		s.logger.Warn("Refresh token reused, revoking all tokens", zap.String("user_id", token.UserID))
+		if err := s.tokens.RevokeAll(token.UserID); err != nil {
+			return TokenPair{}, fmt.Errorf("can't revoke refresh tokens: %w", err)
+		}
+		return TokenPair{}, ErrInvalidToken
+	}
+
+	return s.Issue(token.UserID)
+}
+
+// Revoke revokes the refresh token. Unknown tokens are ignored.
+func (s *Service) Revoke(refreshToken string) error {
+	token, err := s.tokens.GetByHash(hashToken(refreshToken))
+	if errors.Is(err, ErrInvalidToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.tokens.Revoke(token.ID)
+	return err
+}
+
+// RevokeAll revokes all the user's refresh tokens. Access tokens are valid
+// until they expire.
+func (s *Service) RevokeAll(userID string) error {
+	return s.tokens.RevokeAll(userID)
+}
+
+// Authorize validates the access token and returns the user ID.
+func (s *Service) Authorize(accessToken string) (string, error) {
+	claims := jwt.RegisteredClaims{}
+
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(t *jwt.Token) (any, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		secret, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+
+		return secret, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if !claims.VerifyIssuer(issuer, true) || claims.Subject == "" || claims.ExpiresAt == nil {
+		return "", ErrInvalidToken
+	}
+
+	return claims.Subject, nil
+}
+
+func (s *Service) Clean(ctx context.Context) error {
+	n, err := s.tokens.removeExpired(ctx, time.Now())
+
+	s.logger.Info("Cleaned refresh tokens", zap.Int64("count", n))
+	return err
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,124 @@
+package tokens
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+var (
+	keyOld = SigningKey{ID: "old", Secret: []byte(strings.Repeat("o", 32))}
+	keyNew = SigningKey{ID: "new", Secret: []byte(strings.Repeat("n", 32))}
+)
+
+func sign(t *testing.T, key SigningKey, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", nil, []string{}, false},
+		{"valid", []string{"a:" + strings.Repeat("x", 32), "b:" + strings.Repeat("y:", 16)}, []string{"a", "b"}, false},
+		{"no separator", []string{strings.Repeat("x", 32)}, nil, true},
+		{"empty id", []string{":" + strings.Repeat("x", 32)}, nil, true},
+		{"short secret", []string{"a:secret"}, nil, true},
+		{"placeholder", []string{"2025-08:change-me-to-a-long-random-secret-value"}, nil, true},
+		{"duplicate", []string{"a:" + strings.Repeat("x", 32), "a:" + strings.Repeat("y", 32)}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeys(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseKeys() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Errorf("ParseKeys()[%d].ID = %s, want %s", i, got[i].ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestServiceAuthorize(t *testing.T) {
+	svc, err := NewService(ServiceParams{
+		Config: Config{Keys: []SigningKey{keyNew, keyOld}, AccessTTL: time.Minute},
+		Logger: zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   "user",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Second))
+	noExpiry := valid
+	noExpiry.ExpiresAt = nil
+	foreign := valid
+	foreign.Issuer = "other"
+
+	unknown := SigningKey{ID: "unknown", Secret: keyNew.Secret}
+	wrongSecret := SigningKey{ID: keyNew.ID, Secret: keyOld.Secret}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"current key", sign(t, keyNew, valid), false},
+		{"rotated key", sign(t, keyOld, valid), false},
+		{"unknown key", sign(t, unknown, valid), true},
+		{"wrong secret", sign(t, wrongSecret, valid), true},
+		{"expired", sign(t, keyNew, expired), true},
+		{"no expiry", sign(t, keyNew, noExpiry), true},
+		{"foreign issuer", sign(t, keyNew, foreign), true},
+		{"malformed", "not-a-token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := svc.Authorize(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Authorize() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+			if userID != "user" {
+				t.Errorf("Authorize() = %s, want user", userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+const deliveryDialTimeout = 5 * time.Second
+
+var (
+	errRedirect         = errors.New("redirects are not followed")
+	errForbiddenAddress = errors.New("address is not allowed")
+
+	// carrier-grade NAT range, not covered by netip.Addr.IsPrivate
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// newDeliveryClient returns the client for webhooks delivered by the server.
+// URLs are supplied by users, so redirects are not followed and connections
+// to loopback, private, link-local and other non-public addresses are refused
+// after name resolution.
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: deliveryDialTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        16,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errRedirect
+		},
+	}
+}
+
+// checkAddress allows only public unicast addresses.
+func checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+
+	if !ip.IsGlobalUnicast() ||
+		ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", errForbiddenAddress, ip)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:443", false},
+		{"[::1]:443", false},
+		{"10.0.0.1:443", false},
+		{"172.16.0.1:443", false},
+		{"192.168.1.1:443", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:443", false},
+		{"0.0.0.0:443", false},
+		{"[fd00::1]:443", false},
+		{"[fe80::1]:443", false},
+		{"[::ffff:127.0.0.1]:443", false},
+		{"224.0.0.1:443", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkAddress(tt.address)
+			if (err == nil) != tt.allowed {
+				t.Errorf("checkAddress(%s) error = %v, allowed %v", tt.address, err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestDeliveryClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := newDeliveryClient()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req); !errors.Is(err, errForbiddenAddress) {
+		t.Errorf("Do() to loopback error = %v, want %v", err, errForbiddenAddress)
+	}
+
+	if err := client.CheckRedirect(req, nil); !errors.Is(err, errRedirect) {
+		t.Errorf("CheckRedirect() error = %v, want %v", err, errRedirect)
+	}
+}
@@ -0,0 +1,190 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/models"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+	"github.com/capcom6/go-helpers/anys"
+	"go.uber.org/zap"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+	// maxDeliveries limits events delivered at once, events over the limit
+	// are dropped.
+	maxDeliveries = 64
+)
+
+// event is the webhook request body, same as sent by devices.
+type event struct {
+	ID        string                  `json:"id"`
+	WebhookID string                  `json:"webhookId"`
+	DeviceID  string                  `json:"deviceId"`
+	Event     smsgateway.WebhookEvent `json:"event"`
+	Payload   any                     `json:"payload"`
+}
+
+type deviceStatusPayload struct {
+	DeviceID string    `json:"deviceId"`
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+type messageStatePayload struct {
+	MessageID  string                      `json:"messageId"`
+	State      smsgateway.ProcessingState  `json:"state"`
+	Recipients []smsgateway.RecipientState `json:"recipients"`
+	States     map[string]time.Time        `json:"states"`
+	Metadata   map[string]string           `json:"metadata,omitempty"`
+	Tags       []string                    `json:"tags,omitempty"`
+}
+
+// HandleStatus delivers device status changes to the user's webhooks.
+func (s *Service) HandleStatus(ctx context.Context, e devices.StatusEvent) {
+	webhookEvent := EventDeviceOffline
+	if e.Status == devices.StatusOnline {
+		webhookEvent = EventDeviceOnline
+	}
+
+	s.dispatch(ctx, e.Device, webhookEvent, deviceStatusPayload{
+		DeviceID: e.Device.ID,
+		Name:     anys.OrDefault(e.Device.Name, ""),
+		Status:   string(e.Status),
+		LastSeen: e.Device.LastSeen,
+	})
+}
+
+// HandleMessageState delivers message states reported by devices to the
+// user's webhooks along with the message metadata and tags.
+func (s *Service) HandleMessageState(ctx context.Context, e messages.StateEvent) {
+	s.dispatch(ctx, e.Device, EventMessageState, newMessageStatePayload(e.State))
+}
+
+func newMessageStatePayload(state messages.MessageStateOut) messageStatePayload {
+	recipients := make([]smsgateway.RecipientState, len(state.Recipients))
+	for i, r := range state.Recipients {
+		recipients[i] = r.RecipientState
+	}
+
+	return messageStatePayload{
+		MessageID:  state.ID,
+		State:      state.State,
+		Recipients: recipients,
+		States:     state.States,
+		Metadata:   state.Metadata,
+		Tags:       state.Tags,
+	}
+}
+
+// dispatch delivers the event of the device to the matching webhooks of its
+// user in the background. Webhooks of the event are delivered one by one in a
+// single goroutine.
+func (s *Service) dispatch(ctx context.Context, device models.Device, webhookEvent smsgateway.WebhookEvent, payload any) {
+	items, err := s.webhooks.Select(
+		WithUserID(device.UserID),
+		WithDeviceID(device.ID, false),
+		WithEvent(webhookEvent),
+	)
+	if err != nil {
+		s.logger.Error("can't select webhooks", zap.Error(err))
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	signingKey, err := s.settingsSvc.GetWebhooksSigningKey(device.UserID)
+	if err != nil {
+		s.logger.Error("can't get signing key", zap.Error(err))
+	}
+
+	type delivery struct {
+		url  string
+		body []byte
+	}
+
+	deliveries := make([]delivery, 0, len(items))
+	for _, item := range items {
+		body, err := json.Marshal(event{
+			ID:        s.idgen(),
+			WebhookID: item.ExtID,
+			DeviceID:  device.ID,
+			Event:     webhookEvent,
+			Payload:   payload,
+		})
+		if err != nil {
+			s.logger.Error("can't marshal webhook event", zap.Error(err))
+			return
+		}
+
+		deliveries = append(deliveries, delivery{url: item.URL, body: body})
+	}
+
+	select {
+	case s.deliveries <- struct{}{}:
+	default:
+		s.logger.Error("too many webhooks are being delivered, event is dropped",
+			zap.String("device_id", device.ID),
+			zap.String("event", string(webhookEvent)),
+		)
+		return
+	}
+
+	go func() {
+		defer func() { <-s.deliveries }()
+
+		for _, d := range deliveries {
+			if err := s.deliver(ctx, d.url, d.body, signingKey); err != nil {
+				s.logger.Error("can't deliver webhook", zap.String("url", d.url), zap.Error(err))
+			}
+		}
+	}()
+}
+
+// deliver posts the body to the URL. The body is signed the same way as by
+// devices if the signing key is set.
+func (s *Service) deliver(ctx context.Context, url string, body []byte, signingKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if signingKey != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(signingKey))
+		mac.Write(body)
+		mac.Write([]byte(timestamp))
+
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
+)
+
+func TestNewMessageStatePayload(t *testing.T) {
+	state := messages.MessageStateOut{
+		MessageState: smsgateway.MessageState{
+			ID:    "order-1",
+			State: smsgateway.ProcessingStateSent,
+		},
+		Recipients: []messages.RecipientStateOut{
+			{
+				RecipientState: smsgateway.RecipientState{PhoneNumber: "+79999999999", State: smsgateway.ProcessingStateSent},
+				States:         []messages.RecipientStateHistoryItem{{State: smsgateway.ProcessingStateSent}},
+			},
+		},
+		Metadata: map[string]string{"orderId": "12345"},
+		Tags:     []string{"orders"},
+	}
+
+	body, err := json.Marshal(newMessageStatePayload(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["messageId"] != "order-1" || got["state"] != "Sent" {
+		t.Errorf("unexpected message state: %s", body)
+	}
+	if metadata, ok := got["metadata"].(map[string]any); !ok || metadata["orderId"] != "12345" {
+		t.Errorf("expected metadata, got %s", body)
+	}
+	if tags, ok := got["tags"].([]any); !ok || len(tags) != 1 || tags[0] != "orders" {
+		t.Errorf("expected tags, got %s", body)
+	}
+
+	recipients, ok := got["recipients"].([]any)
+	if !ok || len(recipients) != 1 {
+		t.Fatalf("expected 1 recipient, got %s", body)
+	}
+	if _, ok := recipients[0].(map[string]any)["states"]; ok {
+		t.Errorf("expected recipients without history, got %s", body)
+	}
+}
@@ -0,0 +1,26 @@
+package webhooks
+
+import (
+	"slices"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// Events delivered by the server instead of devices.
+const (
+	EventDeviceOffline smsgateway.WebhookEvent = "device:offline"
+	EventDeviceOnline  smsgateway.WebhookEvent = "device:online"
+	EventMessageState  smsgateway.WebhookEvent = "message:state"
+)
+
+var serverEvents = []smsgateway.WebhookEvent{
+	EventDeviceOffline,
+	EventDeviceOnline,
+	EventMessageState,
+}
+
+// IsValidEvent checks if the event is delivered either by devices or by the
+// server.
+func IsValidEvent(e smsgateway.WebhookEvent) bool {
+	return smsgateway.IsValidWebhookEvent(e) || slices.Contains(serverEvents, e)
+}
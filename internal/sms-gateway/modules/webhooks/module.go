@@ -1,6 +1,8 @@
 package webhooks
 
 import (
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/messages"
 	"github.com/capcom6/go-infra-fx/db"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -14,6 +16,8 @@ var Module = fx.Module(
 	fx.Provide(NewRepository, fx.Private),
 	fx.Provide(
 		NewService,
+		devices.AsStatusHandler(func(s *Service) *Service { return s }),
+		messages.AsStateHandler(func(s *Service) *Service { return s }),
 	),
 )
 
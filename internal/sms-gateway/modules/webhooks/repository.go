@@ -28,6 +28,23 @@ func (r *Repository) Delete(filters ...SelectFilter) error {
 	return newFilter(filters...).apply(r.db).Delete(&Webhook{}).Error
 }
 
+// ExtIDExists checks if the user has a webhook with the ID, including deleted
+// ones.
+func (r *Repository) ExtIDExists(userID, extID string) (bool, error) {
+	count := int64(0)
+	err := r.db.Unscoped().Model(&Webhook{}).Where("user_id = ? AND ext_id = ?", userID, extID).Count(&count).Error
+	return count > 0, err
+}
+
+// Move assigns the webhook to another user.
+func (r *Repository) Move(webhook *Webhook, userID, extID string) error {
+	return r.db.
+		Model(&Webhook{}).
+		Where("id = ?", webhook.ID).
+		Updates(map[string]any{"user_id": userID, "ext_id": extID}).
+		Error
+}
+
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
 		db: db,
@@ -1,6 +1,9 @@
 package webhooks
 
-import "gorm.io/gorm"
+import (
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"gorm.io/gorm"
+)
 
 type SelectFilter func(*selectFilter)
 
@@ -16,11 +19,28 @@ func WithUserID(userID string) SelectFilter {
 	}
 }
 
+// WithEvent selects webhooks of the event.
+func WithEvent(event smsgateway.WebhookEvent) SelectFilter {
+	return func(f *selectFilter) {
+		f.event = &event
+	}
+}
+
+// WithDeviceEvents selects webhooks delivered by devices, excluding events
+// delivered by the server.
+func WithDeviceEvents() SelectFilter {
+	return func(f *selectFilter) {
+		f.deviceEvents = true
+	}
+}
+
 type selectFilter struct {
 	userID        string
 	extID         *string
 	deviceID      *string
 	deviceIDExact bool
+	event         *smsgateway.WebhookEvent
+	deviceEvents  bool
 }
 
 func newFilter(filters ...SelectFilter) *selectFilter {
@@ -57,5 +77,11 @@ func (f *selectFilter) apply(query *gorm.DB) *gorm.DB {
 			query = query.Where("device_id = ? OR device_id IS NULL", *f.deviceID)
 		}
 	}
+	if f.event != nil {
+		query = query.Where("event = ?", *f.event)
+	}
+	if f.deviceEvents {
+		query = query.Where("event NOT IN ?", serverEvents)
+	}
 	return query
 }
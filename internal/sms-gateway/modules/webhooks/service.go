@@ -2,14 +2,17 @@ package webhooks
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/db"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/devices"
 	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/push"
+	"github.com/android-sms-gateway/server/internal/sms-gateway/modules/settings"
 	"github.com/capcom6/go-helpers/slices"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type ServiceParams struct {
@@ -19,8 +22,9 @@ type ServiceParams struct {
 
 	Webhooks *Repository
 
-	DevicesSvc *devices.Service
-	PushSvc    *push.Service
+	DevicesSvc  *devices.Service
+	PushSvc     *push.Service
+	SettingsSvc *settings.Service
 
 	Logger *zap.Logger
 }
@@ -30,8 +34,12 @@ type Service struct {
 
 	webhooks *Repository
 
-	devicesSvc *devices.Service
-	pushSvc    *push.Service
+	devicesSvc  *devices.Service
+	pushSvc     *push.Service
+	settingsSvc *settings.Service
+
+	client     *http.Client
+	deliveries chan struct{}
 
 	logger *zap.Logger
 }
@@ -42,7 +50,12 @@ func NewService(params ServiceParams) *Service {
 		webhooks:   params.Webhooks,
 		devicesSvc: params.DevicesSvc,
 		pushSvc:    params.PushSvc,
-		logger:     params.Logger,
+
+		settingsSvc: params.SettingsSvc,
+		client:      newDeliveryClient(),
+		deliveries:  make(chan struct{}, maxDeliveries),
+
+		logger: params.Logger,
 	}
 }
 
@@ -67,7 +80,7 @@ func (s *Service) Select(userID string, filters ...SelectFilter) ([]smsgateway.W
 // Replace creates or updates a webhook for a given user. After replacing the webhook,
 // it asynchronously notifies all the user's devices. Returns an error if the operation fails.
 func (s *Service) Replace(userID string, webhook smsgateway.Webhook) error {
-	if !smsgateway.IsValidWebhookEvent(webhook.Event) {
+	if !IsValidEvent(webhook.Event) {
 		return newValidationError("event", string(webhook.Event), fmt.Errorf("enum value expected"))
 	}
 
@@ -116,6 +129,44 @@ func (s *Service) Delete(userID string, filters ...SelectFilter) error {
 	return nil
 }
 
+// TransferDevice moves webhooks of the device to the target user or removes
+// them if detach is true. It runs in the transaction of the device transfer.
+// Webhooks of all the user's devices stay with the user. Moved webhooks get new
+// IDs if the target user has webhooks with the same IDs. The device is not
+// notified.
+func (s *Service) TransferDevice(tx *gorm.DB, deviceID, userID, targetUserID string, detach bool) error {
+	webhooks := NewRepository(tx)
+
+	if detach {
+		if err := webhooks.Delete(WithUserID(userID), WithDeviceID(deviceID, true)); err != nil {
+			return fmt.Errorf("can't delete webhooks: %w", err)
+		}
+		return nil
+	}
+
+	items, err := webhooks.Select(WithUserID(userID), WithDeviceID(deviceID, true))
+	if err != nil {
+		return fmt.Errorf("can't select webhooks: %w", err)
+	}
+
+	for _, item := range items {
+		extID := item.ExtID
+		exists, err := webhooks.ExtIDExists(targetUserID, extID)
+		if err != nil {
+			return fmt.Errorf("can't check webhook ID: %w", err)
+		}
+		if exists {
+			extID = s.idgen()
+		}
+
+		if err := webhooks.Move(item, targetUserID, extID); err != nil {
+			return fmt.Errorf("can't move webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // notifyDevices asynchronously notifies all the user's devices.
 func (s *Service) notifyDevices(userID string, deviceID *string) {
 	go func(userID string, deviceID *string) {
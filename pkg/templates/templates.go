@@ -0,0 +1,33 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// HasPlaceholders reports whether the template contains at least one
+// `{{ variable }}` placeholder.
+func HasPlaceholders(template string) bool {
+	return placeholderRegexp.MatchString(template)
+}
+
+// Render replaces `{{ variable }}` placeholders in the template with values
+// from vars. Variable names are case-insensitive, unknown variables are
+// replaced with an empty string.
+func Render(template string, vars map[string]string) string {
+	if !strings.Contains(template, "{{") {
+		return template
+	}
+
+	normalized := make(map[string]string, len(vars))
+	for k, v := range vars {
+		normalized[strings.ToLower(k)] = v
+	}
+
+	return placeholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholderRegexp.FindStringSubmatch(placeholder)[1]
+		return normalized[strings.ToLower(name)]
+	})
+}
@@ -0,0 +1,81 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/server/pkg/templates"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]string
+		expected string
+	}{
+		{
+			name:     "No placeholders",
+			template: "Hello World!",
+			vars:     map[string]string{"name": "John"},
+			expected: "Hello World!",
+		},
+		{
+			name:     "Single placeholder",
+			template: "Hello {{name}}!",
+			vars:     map[string]string{"name": "John"},
+			expected: "Hello John!",
+		},
+		{
+			name:     "Placeholder with spaces",
+			template: "Hello {{ name }}, your code is {{ code }}",
+			vars:     map[string]string{"name": "John", "code": "1234"},
+			expected: "Hello John, your code is 1234",
+		},
+		{
+			name:     "Case-insensitive names",
+			template: "Hello {{ Name }}!",
+			vars:     map[string]string{"NAME": "John"},
+			expected: "Hello John!",
+		},
+		{
+			name:     "Unknown placeholder",
+			template: "Hello {{name}}!",
+			vars:     map[string]string{},
+			expected: "Hello !",
+		},
+		{
+			name:     "Unclosed placeholder",
+			template: "Hello {{name!",
+			vars:     map[string]string{"name": "John"},
+			expected: "Hello {{name!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := templates.Render(tt.template, tt.vars); actual != tt.expected {
+				t.Errorf("Render() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasPlaceholders(t *testing.T) {
+	tests := []struct {
+		template string
+		expected bool
+	}{
+		{template: "Hello World!", expected: false},
+		{template: "Hello {{name}}!", expected: true},
+		{template: "Hello {{ name }}!", expected: true},
+		{template: "Hello {{}}!", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if actual := templates.HasPlaceholders(tt.template); actual != tt.expected {
+				t.Errorf("HasPlaceholders() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package e2e
+
+import (
+	"testing"
+)
+
+func TestAdmin(t *testing.T) {
+	type credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		res, err := privateAdminClient.R().
+			SetAuthToken("invalid").
+			Get("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 401 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	var user credentials
+	res, err := privateAdminClient.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{}).
+		SetResult(&user).
+		Post("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 || user.Login == "" || user.Password == "" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	devices := func(c credentials) int {
+		res, err := privateUserClient.R().
+			SetBasicAuth(c.Login, c.Password).
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode()
+	}
+
+	if status := devices(user); status != 200 {
+		t.Fatalf("expected 200 for the new user, got %d", status)
+	}
+
+	t.Run("duplicate", func(t *testing.T) {
+		res, err := privateAdminClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"login": user.Login}).
+			Post("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 409 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		var users []struct {
+			ID string `json:"id"`
+		}
+		res, err := privateAdminClient.R().
+			SetQueryParam("query", user.Login).
+			SetResult(&users).
+			Get("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || len(users) != 1 || users[0].ID != user.Login {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		var pair struct {
+			AccessToken string `json:"accessToken"`
+		}
+		res, err := privateUserClient.R().
+			SetBasicAuth(user.Login, user.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"grantType": "password"}).
+			SetResult(&pair).
+			Post("auth/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || pair.AccessToken == "" {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		for _, c := range []struct {
+			disabled bool
+			expected int
+		}{{true, 401}, {false, 200}} {
+			res, err := privateAdminClient.R().
+				SetHeader("Content-Type", "application/json").
+				SetBody(map[string]any{"disabled": c.disabled}).
+				Patch("users/" + user.Login)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != 204 {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+			if status := devices(user); status != c.expected {
+				t.Fatalf("expected %d, got %d", c.expected, status)
+			}
+
+			// access tokens issued before are not accepted while the user is disabled
+			res, err = privateUserClient.R().
+				SetAuthToken(pair.AccessToken).
+				Get("devices")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != c.expected {
+				t.Fatalf("expected %d with access token, got %d", c.expected, res.StatusCode())
+			}
+		}
+	})
+
+	t.Run("reset password", func(t *testing.T) {
+		var reset credentials
+		res, err := privateAdminClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{}).
+			SetResult(&reset).
+			Post("users/" + user.Login + "/password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || reset.Password == "" || reset.Password == user.Password {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		if status := devices(user); status != 401 {
+			t.Fatalf("expected 401 with the old password, got %d", status)
+		}
+		if status := devices(reset); status != 200 {
+			t.Fatalf("expected 200 with the new password, got %d", status)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		var stats struct {
+			Messages struct {
+				Total int64 `json:"total"`
+			} `json:"messages"`
+		}
+		res, err := privateAdminClient.R().
+			SetResult(&stats).
+			Get("users/" + user.Login + "/stats")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || stats.Messages.Total != 0 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("devices", func(t *testing.T) {
+		res, err := privateAdminClient.R().
+			SetQueryParam("limit", "10").
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		for _, expected := range []int{204, 404} {
+			res, err := privateAdminClient.R().
+				Delete("users/" + user.Login)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != expected {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package e2e
+
+import (
+	"testing"
+)
+
+func TestAPIKeys(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	type key struct {
+		ID     string   `json:"id"`
+		Key    string   `json:"key"`
+		Prefix string   `json:"prefix"`
+		Scopes []string `json:"scopes"`
+	}
+
+	create := func(body map[string]any) key {
+		var k key
+		res, err := publicUserClient.R().
+			SetBasicAuth(credentials.Login, credentials.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			SetResult(&k).
+			Post("keys")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 || k.Key == "" || k.Prefix != k.Key[:len(k.Prefix)] {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+		return k
+	}
+
+	request := func(token, method, path string) int {
+		res, err := publicUserClient.R().
+			SetAuthToken(token).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"message": "test", "phoneNumbers": []string{"+79999999999"}}).
+			Execute(method, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode()
+	}
+
+	readOnly := create(map[string]any{"name": "read", "scopes": []string{"messages:read"}})
+	restricted := create(map[string]any{"name": "restricted", "scopes": []string{"messages:read"}, "allowedIps": []string{"192.0.2.0/24"}})
+
+	cases := []struct {
+		name     string
+		token    string
+		method   string
+		path     string
+		expected int
+	}{
+		{"read with scope", readOnly.Key, "GET", "messages", 200},
+		{"send without scope", readOnly.Key, "POST", "messages", 403},
+		{"devices without scope", readOnly.Key, "GET", "devices", 403},
+		{"keys management", readOnly.Key, "GET", "keys", 403},
+		{"not allowed IP", restricted.Key, "GET", "messages", 401},
+		{"invalid key", "sgk_invalid", "GET", "messages", 401},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if status := request(c.token, c.method, c.path); status != c.expected {
+				t.Fatalf("expected %d, got %d", c.expected, status)
+			}
+		})
+	}
+
+	var keys []key
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&keys).
+		Get("keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(keys) != 2 || keys[0].Key != "" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		Delete("keys/" + readOnly.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	if status := request(readOnly.Key, "GET", "messages"); status != 401 {
+		t.Fatal("revoked key:", status)
+	}
+}
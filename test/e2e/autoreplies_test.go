@@ -0,0 +1,205 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestAutoRepliesSuppression(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"keyword": "STOP",
+			"reply":   "You are unsubscribed",
+			"action":  "suppress",
+		}).
+		Post("autoreplies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	res, err = publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"id":          "incoming-1",
+			"phoneNumber": "+79990001234",
+			"message":     "stop",
+		}).
+		Post("inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var incoming struct {
+		RuleID  string `json:"ruleId"`
+		ReplyID string `json:"replyId"`
+	}
+	if err := json.Unmarshal(res.Body(), &incoming); err != nil {
+		t.Fatal(err)
+	}
+	if incoming.RuleID == "" || incoming.ReplyID == "" {
+		t.Fatalf("expected matched rule and reply, got %s", res.String())
+	}
+
+	found := false
+	for _, m := range selectPending(t, credentials.Token) {
+		if m.ID == incoming.ReplyID {
+			found = m.Message == "You are unsubscribed"
+		}
+	}
+	if !found {
+		t.Fatal("reply message not found")
+	}
+
+	// the number is suppressed now
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "promo",
+			"phoneNumbers": []string{"+79990001234"},
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var list []struct {
+		PhoneNumber string `json:"phoneNumber"`
+		Reason      string `json:"reason"`
+	}
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&list).
+		Get("suppressions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	if len(list) != 1 || list[0].PhoneNumber != "+79990001234" || list[0].Reason != "STOP" {
+		t.Fatalf("unexpected suppression list: %s", res.String())
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		Delete("suppressions/" + url.PathEscape("+79990001234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"message":      "promo",
+		"phoneNumbers": []string{"+79990001234"},
+	})
+}
+
+func TestAutoRepliesRepeatedReport(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	createAutoReply(t, credentials, map[string]any{"keyword": "INFO", "reply": "Call us"})
+
+	first := reportIncoming(t, credentials.Token, "incoming-1", "+79990001234", "info")
+	if first.RuleID == "" || first.ReplyID == "" {
+		t.Fatalf("expected matched rule and reply, got %+v", first)
+	}
+
+	second := reportIncoming(t, credentials.Token, "incoming-1", "+79990001234", "info")
+	if second != first {
+		t.Fatalf("expected result of the first report %+v, got %+v", first, second)
+	}
+
+	replies := 0
+	for _, m := range selectPending(t, credentials.Token) {
+		if m.Message == "Call us" {
+			replies++
+		}
+	}
+	if replies != 1 {
+		t.Fatalf("expected 1 reply, got %d", replies)
+	}
+}
+
+func TestAutoRepliesCooldown(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	createAutoReply(t, credentials, map[string]any{"keyword": "INFO", "reply": "Call us"})
+
+	first := reportIncoming(t, credentials.Token, "incoming-1", "+79990001234", "info")
+	if first.ReplyID == "" {
+		t.Fatalf("expected reply, got %+v", first)
+	}
+
+	// the sender is not replied to again during the cooldown
+	second := reportIncoming(t, credentials.Token, "incoming-2", "+79990001234", "info")
+	if second.RuleID != first.RuleID || second.ReplyID != "" {
+		t.Fatalf("expected matched rule without reply, got %+v", second)
+	}
+
+	// other senders are replied to
+	other := reportIncoming(t, credentials.Token, "incoming-3", "+79990005678", "info")
+	if other.ReplyID == "" {
+		t.Fatalf("expected reply, got %+v", other)
+	}
+}
+
+type incomingResult struct {
+	RuleID  string `json:"ruleId"`
+	ReplyID string `json:"replyId"`
+}
+
+func createAutoReply(t *testing.T, credentials mobileRegisterResponse, rule map[string]any) {
+	t.Helper()
+
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(rule).
+		Post("autoreplies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func reportIncoming(t *testing.T, token, id, phoneNumber, message string) incomingResult {
+	t.Helper()
+
+	var result incomingResult
+	res, err := publicMobileClient.R().
+		SetAuthToken(token).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"id":          id,
+			"phoneNumber": phoneNumber,
+			"message":     message,
+		}).
+		SetResult(&result).
+		Post("inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	return result
+}
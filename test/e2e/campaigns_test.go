@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type campaign struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Progress *struct {
+		Total      int            `json:"total"`
+		Recipients map[string]int `json:"recipients"`
+	} `json:"progress"`
+}
+
+func TestCampaigns(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	t.Run("create with inline recipients", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"id":            "campaign-inline",
+				"name":          "Inline",
+				"message":       "Hello {{name}}!",
+				"ratePerMinute": 60,
+				"recipients": []map[string]any{
+					{"phoneNumber": "+79999999998", "variables": map[string]string{"name": "John"}},
+					{"phoneNumber": "+79999999999", "variables": map[string]string{"name": "Jane"}},
+				},
+			}).
+			Post("campaigns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp campaign
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.State != "Active" || resp.Progress == nil || resp.Progress.Total != 2 {
+			t.Fatalf("unexpected campaign: %s", res.String())
+		}
+	})
+
+	t.Run("create with csv", func(t *testing.T) {
+		res, err := client.R().
+			SetMultipartFormData(map[string]string{
+				"id":            "campaign-csv",
+				"name":          "CSV",
+				"message":       "Hello {{name}}!",
+				"ratePerMinute": "1",
+			}).
+			SetMultipartField("file", "recipients.csv", "text/csv", strings.NewReader("phone,name\n+79999999998,John\n")).
+			Post("campaigns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("without recipients", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"name":          "Empty",
+				"message":       "Hello!",
+				"ratePerMinute": 60,
+			}).
+			Post("campaigns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 400 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	cases := []struct {
+		name               string
+		action             string
+		expectedStatusCode int
+	}{
+		{name: "pause", action: "pause", expectedStatusCode: 204},
+		{name: "pause paused", action: "pause", expectedStatusCode: 409},
+		{name: "resume", action: "resume", expectedStatusCode: 204},
+		{name: "cancel", action: "cancel", expectedStatusCode: 204},
+		{name: "resume cancelled", action: "resume", expectedStatusCode: 409},
+		{name: "unknown action", action: "restart", expectedStatusCode: 404},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := client.R().Post("campaigns/campaign-csv/" + c.action)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != c.expectedStatusCode {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+		})
+	}
+}
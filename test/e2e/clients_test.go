@@ -25,4 +25,9 @@ var (
 	privateUserClient = resty.New().
 				SetBaseURL(PrivateURL + "/3rdparty/v1").
 				SetTimeout(300 * time.Millisecond)
+
+	privateAdminClient = resty.New().
+				SetBaseURL(PrivateURL + "/admin/v1").
+				SetAuthToken("987654321").
+				SetTimeout(300 * time.Millisecond)
 )
@@ -0,0 +1,173 @@
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type contact struct {
+	ID          string            `json:"id"`
+	PhoneNumber string            `json:"phoneNumber"`
+	Name        string            `json:"name"`
+	Fields      map[string]string `json:"fields"`
+	GroupIDs    []string          `json:"groupIds"`
+}
+
+func TestContacts(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"id": "customers", "name": "Customers"}).
+		Post("contacts/groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	t.Run("create", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"id":          "john",
+				"phoneNumber": "+79999999998",
+				"name":        "John",
+				"fields":      map[string]string{"city": "London"},
+				"groupIds":    []string{"customers"},
+			}).
+			Post("contacts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp contact
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Name != "John" || resp.Fields["city"] != "London" || len(resp.GroupIDs) != 1 {
+			t.Fatalf("unexpected contact: %s", res.String())
+		}
+	})
+
+	t.Run("duplicate phone", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"phoneNumber": "+79999999998"}).
+			Post("contacts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 409 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"phoneNumber": "+79999999997", "groupIds": []string{"unknown"}}).
+			Post("contacts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 400 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		res, err := client.R().
+			SetMultipartFormData(map[string]string{"groupIds": "customers"}).
+			SetMultipartField("file", "contacts.csv", "text/csv", strings.NewReader("phone,name,city\n+79999999998,Johnny,Paris\n+79999999999,Jane,Berlin\n")).
+			Post("contacts/import")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		res, err = client.R().
+			SetQueryParam("groupId", "customers").
+			Get("contacts")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp []contact
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp) != 2 || resp[0].ID != "john" || resp[0].Name != "Johnny" {
+			t.Fatalf("unexpected contacts: %s", res.String())
+		}
+	})
+
+	t.Run("send to group", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"message":  "Hello {{name}} from {{city}}!",
+				"groupIds": []string{"customers"},
+			}).
+			Post("messages")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 202 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var resp []messageState
+		if err := json.Unmarshal(res.Body(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp) != 2 {
+			t.Fatalf("expected 2 messages, got %s", res.String())
+		}
+	})
+
+	t.Run("send without recipients", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"message":      "Hello!",
+				"phoneNumbers": []string{},
+			}).
+			Post("messages")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 400 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("delete group", func(t *testing.T) {
+		res, err := client.R().Delete("contacts/groups/customers")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 204 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		res, err = client.R().Get("contacts/john")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+}
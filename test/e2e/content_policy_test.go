@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentPolicy(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"denyPatterns": []string{"("}}).
+		Put("content-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	res, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"maxLength":    20,
+			"denyKeywords": []string{"casino"},
+			"restrictUrls": true,
+			"urlAllowlist": []string{"example.com"},
+			// encrypted messages are rejected by default
+		}).
+		Put("content-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	cases := []struct {
+		name string
+		body map[string]any
+		rule string
+	}{
+		{
+			name: "allowed",
+			body: map[string]any{"message": "See example.com"},
+		},
+		{
+			name: "max length",
+			body: map[string]any{"message": "This message is too long for the policy"},
+			rule: "maxLength",
+		},
+		{
+			name: "keyword",
+			body: map[string]any{"message": "Casino night"},
+			rule: "denyKeywords",
+		},
+		{
+			name: "url",
+			body: map[string]any{"message": "See evil.org"},
+			rule: "urlAllowlist",
+		},
+		{
+			name: "encrypted",
+			body: map[string]any{"message": "ZW5jcnlwdGVk", "isEncrypted": true},
+			rule: "encrypted",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.body["phoneNumbers"] = []string{"+79999999999"}
+
+			res, err := client.R().
+				SetHeader("Content-Type", "application/json").
+				SetBody(c.body).
+				Post("messages")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.rule == "" {
+				if res.StatusCode() != 202 {
+					t.Fatal(res.StatusCode(), res.String())
+				}
+				return
+			}
+
+			if res.StatusCode() != 400 {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+			if !strings.Contains(res.String(), c.rule) {
+				t.Fatalf("rule %s is not mentioned: %s", c.rule, res.String())
+			}
+		})
+	}
+
+	res, err = client.R().Delete("content-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"message":      "Casino night",
+		"phoneNumbers": []string{"+79999999999"},
+	})
+}
@@ -0,0 +1,686 @@
+package e2e
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type device struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+	Status  string   `json:"status"`
+	Enabled bool     `json:"enabled"`
+	Weight  int      `json:"weight"`
+	SIMs    []struct {
+		Slot    int    `json:"slot"`
+		Carrier string `json:"carrier"`
+		MSISDN  string `json:"msisdn"`
+		Country string `json:"country"`
+	} `json:"sims"`
+	Telemetry *telemetry `json:"telemetry"`
+}
+
+type telemetry struct {
+	BatteryLevel   *int   `json:"batteryLevel"`
+	IsCharging     *bool  `json:"isCharging"`
+	SignalStrength *int   `json:"signalStrength"`
+	NetworkType    string `json:"networkType"`
+	AppVersion     string `json:"appVersion"`
+}
+
+func TestDeviceTags(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	putTags := func(id string, tags []string) (int, device) {
+		out := device{}
+		res, err := publicUserClient.R().
+			SetBasicAuth(credentials.Login, credentials.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"tags": tags}).
+			SetResult(&out).
+			Put("devices/" + id + "/tags")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res.StatusCode(), out
+	}
+
+	if code, _ := putTags("unknown", []string{"eu"}); code != 404 {
+		t.Fatalf("expected 404, got %d", code)
+	}
+
+	code, updated := putTags(devices[0].ID, []string{"eu", "eu", "mts"})
+	if code != 200 {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(updated.Tags) != 2 || updated.Tags[0] != "eu" || updated.Tags[1] != "mts" {
+		t.Fatalf("unexpected tags: %v", updated.Tags)
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999999"},
+		"deviceTag":    "mts",
+	})
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+			"deviceTag":    "us",
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestDeviceStatus(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	if devices[0].Status != "online" {
+		t.Fatalf("expected online status, got %s", devices[0].Status)
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"url":   "https://example.com/webhook",
+			"event": "device:offline",
+		}).
+		Post("webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	// server events are not delivered by devices
+	var webhooks []map[string]any
+	res, err = publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetResult(&webhooks).
+		Get("webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	if len(webhooks) != 0 {
+		t.Fatalf("expected no webhooks for device, got %s", res.String())
+	}
+}
+
+func TestDeviceSIMs(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	res, err := publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"sims": []map[string]any{
+				{"slot": 1, "carrier": "MTS", "msisdn": "8 (999) 000-12-34", "country": "ru"},
+				{"slot": 2, "carrier": "Beeline"},
+			},
+		}).
+		Put("device/sims")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var devices []device
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	if sims := devices[0].SIMs; len(sims) != 2 || sims[0].MSISDN != "+79990001234" || sims[0].Country != "RU" {
+		t.Fatalf("unexpected SIMs: %s", res.String())
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "sender-number",
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999999"},
+		"senderNumber": "+79990001234",
+	})
+
+	found := false
+	for _, m := range selectPending(t, credentials.Token) {
+		if m.ID == "sender-number" {
+			found = m.SimNumber != nil && *m.SimNumber == 1
+		}
+	}
+	if !found {
+		t.Fatal("message with SIM number 1 not found")
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+			"senderNumber": "+79990009999",
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestDevicePatch(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	if !devices[0].Enabled || devices[0].Weight != 1 {
+		t.Fatalf("unexpected defaults: %s", res.String())
+	}
+
+	patch := func(body map[string]any) (int, device) {
+		out := device{}
+		res, err := publicUserClient.R().
+			SetBasicAuth(credentials.Login, credentials.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			SetResult(&out).
+			Patch("devices/" + devices[0].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res.StatusCode(), out
+	}
+
+	for _, weight := range []int{0, 101} {
+		if code, _ := patch(map[string]any{"weight": weight}); code != 400 {
+			t.Fatalf("expected 400 for weight %d, got %d", weight, code)
+		}
+	}
+
+	code, updated := patch(map[string]any{"name": "Office", "enabled": false, "weight": 5})
+	if code != 200 {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if updated.Name != "Office" || updated.Enabled || updated.Weight != 5 {
+		t.Fatalf("unexpected device: %+v", updated)
+	}
+
+	// disabled devices are not used for routing but can connect
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	selectPending(t, credentials.Token)
+
+	if code, _ := patch(map[string]any{"enabled": true}); code != 200 {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999999"},
+	})
+}
+
+func TestDeviceTokenRotation(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	type tokenResponse struct {
+		Token               string  `json:"token"`
+		PrevTokenValidUntil *string `json:"prevTokenValidUntil"`
+	}
+
+	checkToken := func(token string) int {
+		res, err := publicMobileClient.R().
+			SetAuthToken(token).
+			Get("message")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return res.StatusCode()
+	}
+
+	rotated := tokenResponse{}
+	res, err := publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"gracePeriod": 60}).
+		SetResult(&rotated).
+		Post("device/token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || rotated.Token == "" || rotated.PrevTokenValidUntil == nil {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	// both tokens are valid during the grace period
+	if code := checkToken(credentials.Token); code != 200 {
+		t.Fatalf("expected 200 for previous token, got %d", code)
+	}
+	if code := checkToken(rotated.Token); code != 200 {
+		t.Fatalf("expected 200 for new token, got %d", code)
+	}
+
+	var devices []device
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	forced := tokenResponse{}
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{}).
+		SetResult(&forced).
+		Post("devices/" + devices[0].ID + "/token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || forced.PrevTokenValidUntil != nil {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	if code := checkToken(credentials.Token); code != 401 {
+		t.Fatalf("expected 401 for revoked token, got %d", code)
+	}
+	if code := checkToken(rotated.Token); code != 401 {
+		t.Fatalf("expected 401 for revoked token, got %d", code)
+	}
+	if code := checkToken(forced.Token); code != 200 {
+		t.Fatalf("expected 200 for new token, got %d", code)
+	}
+}
+
+func TestDeviceTelemetry(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	reports := []map[string]any{
+		{"batteryLevel": 90, "isCharging": false, "signalStrength": -80, "networkType": "wifi", "appVersion": "1.30.0"},
+		{"batteryLevel": 85, "isCharging": true, "networkType": "cellular", "appVersion": "1.30.0"},
+	}
+	for _, report := range reports {
+		res, err := publicMobileClient.R().
+			SetAuthToken(credentials.Token).
+			SetHeader("Content-Type", "application/json").
+			SetBody(report).
+			Post("device/telemetry")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 204 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	}
+
+	res, err := publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"batteryLevel": 101}).
+		Post("device/telemetry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var devices []device
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	latest := devices[0].Telemetry
+	if latest == nil || latest.BatteryLevel == nil || *latest.BatteryLevel != 85 || latest.SignalStrength != nil || latest.NetworkType != "cellular" {
+		t.Fatalf("unexpected telemetry: %s", res.String())
+	}
+
+	var history []telemetry
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&history).
+		Get("devices/" + devices[0].ID + "/telemetry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(history) != 2 || history[1].NetworkType != "wifi" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestDeviceCommands(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	type command struct {
+		ID    string  `json:"id"`
+		Type  string  `json:"type"`
+		State string  `json:"state"`
+		Error *string `json:"error"`
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"type": "reboot"}).
+		Post("devices/" + devices[0].ID + "/commands")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 400 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var cmd command
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"type": "ping"}).
+		SetResult(&cmd).
+		Post("devices/" + devices[0].ID + "/commands")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 || cmd.ID == "" || cmd.State != "Pending" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	for _, expected := range []int{204, 409} {
+		res, err = publicMobileClient.R().
+			SetAuthToken(credentials.Token).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{}).
+			Post("device/commands/" + cmd.ID + "/ack")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != expected {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&cmd).
+		Get("devices/" + devices[0].ID + "/commands/" + cmd.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || cmd.State != "Acknowledged" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestDeviceTransfer(t *testing.T) {
+	source := mobileDeviceRegister(t, publicMobileClient)
+	target := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(source.Login, source.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	deviceID := devices[0].ID
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(source.Login, source.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"url":       "https://example.com/webhook",
+			"event":     "sms:received",
+			"device_id": deviceID,
+		}).
+		Post("webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 201 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var code struct {
+		Code string `json:"code"`
+	}
+	res, err = publicMobileClient.R().
+		SetBasicAuth(target.Login, target.Password).
+		SetResult(&code).
+		Get("user/code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	transfer := func(code string) int {
+		res, err := publicUserClient.R().
+			SetBasicAuth(source.Login, source.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"code": code}).
+			Post("devices/" + deviceID + "/transfer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode()
+	}
+
+	if code.Code != "000000" {
+		if status := transfer("000000"); status != 403 {
+			t.Fatal("transfer with invalid code:", status)
+		}
+	}
+	if status := transfer(code.Code); status != 204 {
+		t.Fatal("transfer:", status)
+	}
+	if status := transfer(code.Code); status != 404 {
+		t.Fatal("repeated transfer:", status)
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(target.Login, target.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 2 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var webhooks []map[string]any
+	res, err = publicUserClient.R().
+		SetBasicAuth(target.Login, target.Password).
+		SetResult(&webhooks).
+		Get("webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(webhooks) != 1 || webhooks[0]["device_id"] != deviceID {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	// the device keeps its token
+	res, err = publicMobileClient.R().
+		SetAuthToken(source.Token).
+		Get("message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestDeviceTransferLockout(t *testing.T) {
+	// see lockout.code in data/config.yml
+	const maxAttempts = 5
+
+	source := mobileDeviceRegister(t, publicMobileClient)
+	target := mobileDeviceRegister(t, publicMobileClient)
+
+	var devices []device
+	res, err := publicUserClient.R().
+		SetBasicAuth(source.Login, source.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var code struct {
+		Code string `json:"code"`
+	}
+	res, err = publicMobileClient.R().
+		SetBasicAuth(target.Login, target.Password).
+		SetResult(&code).
+		Get("user/code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	invalid := "000000"
+	if code.Code == invalid {
+		invalid = "000001"
+	}
+
+	transfer := func(code string) *resty.Response {
+		res, err := publicUserClient.R().
+			SetBasicAuth(source.Login, source.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"code": code}).
+			Post("devices/" + devices[0].ID + "/transfer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// failures of other tests within the window may lock out earlier
+	for i := 0; i < maxAttempts; i++ {
+		if res := transfer(invalid); res.StatusCode() != 403 && res.StatusCode() != 429 {
+			t.Fatalf("attempt %d: %d %s", i, res.StatusCode(), res.String())
+		}
+	}
+
+	// the valid code is rejected too, but it is not consumed
+	res = transfer(code.Code)
+	if res.StatusCode() != 429 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+	retryAfter, err := strconv.Atoi(res.Header().Get("Retry-After"))
+	if err != nil || retryAfter <= 0 {
+		t.Fatalf("invalid Retry-After: %q", res.Header().Get("Retry-After"))
+	}
+
+	time.Sleep(time.Duration(retryAfter)*time.Second + 100*time.Millisecond)
+
+	if res := transfer(code.Code); res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
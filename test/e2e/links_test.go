@@ -0,0 +1,117 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type linkMessageState struct {
+	ID         string `json:"id"`
+	Recipients []struct {
+		PhoneNumber string `json:"phoneNumber"`
+		Clicks      uint64 `json:"clicks"`
+	} `json:"recipients"`
+	Links []struct {
+		URL      string `json:"url"`
+		ShortURL string `json:"shortUrl"`
+		Clicks   uint64 `json:"clicks"`
+	} `json:"links"`
+}
+
+var shortLinkRegexp = regexp.MustCompile(`http://localhost:3000/l/[0-9a-z]{10}`)
+
+func TestShortLinks(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "Offer: https://example.com/offer?id=1.",
+			"phoneNumbers": []string{"+79999999998", "+79999999999"},
+			"shortenLinks": true,
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var states []linkMessageState
+	if err := json.Unmarshal(res.Body(), &states); err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected message per recipient, got %d", len(states))
+	}
+
+	links := map[string]string{}
+	for _, m := range selectPending(t, credentials.Token) {
+		link := shortLinkRegexp.FindString(m.Message)
+		if link == "" {
+			t.Fatalf("short link not found: %s", m.Message)
+		}
+		links[m.ID] = link
+	}
+
+	redirectClient := resty.New().
+		SetTimeout(300 * time.Millisecond).
+		SetRedirectPolicy(resty.RedirectPolicyFunc(func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}))
+
+	clicked := states[0].ID
+	for range 2 {
+		res, err := redirectClient.R().Get(links[clicked])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 302 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+		if location := res.Header().Get("Location"); location != "https://example.com/offer?id=1" {
+			t.Fatalf("unexpected location: %s", location)
+		}
+	}
+
+	for _, s := range states {
+		res, err := client.R().Get("messages/" + s.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var state linkMessageState
+		if err := json.Unmarshal(res.Body(), &state); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := uint64(0)
+		if s.ID == clicked {
+			expected = 2
+		}
+		if len(state.Links) != 1 || state.Links[0].ShortURL != links[s.ID] || state.Links[0].Clicks != expected {
+			t.Fatalf("unexpected links: %+v", state.Links)
+		}
+		if state.Recipients[0].Clicks != expected {
+			t.Fatalf("unexpected recipient clicks: %+v", state.Recipients)
+		}
+	}
+
+	res, err = redirectClient.R().Get("http://localhost:3000/l/0000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 404 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
@@ -0,0 +1,168 @@
+package e2e
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessageMetadata(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "metadata-order",
+		"message":      "Your order is ready",
+		"phoneNumbers": []string{"+79999999999"},
+		"metadata":     map[string]string{"orderId": "12345", "ticketId": "T-1"},
+		"tags":         []string{"orders", "notifications"},
+	})
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "metadata-other",
+		"message":      "Hello",
+		"phoneNumbers": []string{"+79999999999"},
+		"tags":         []string{"notifications"},
+	})
+
+	t.Run("state", func(t *testing.T) {
+		res, err := client.R().Get("messages/metadata-order")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var state messageState
+		if err := json.Unmarshal(res.Body(), &state); err != nil {
+			t.Fatal(err)
+		}
+		if state.Metadata["orderId"] != "12345" || len(state.Tags) != 2 {
+			t.Fatalf("unexpected state: %s", res.String())
+		}
+	})
+
+	t.Run("mobile", func(t *testing.T) {
+		for _, m := range selectPending(t, credentials.Token) {
+			if m.ID == "metadata-order" && m.Metadata["ticketId"] == "T-1" {
+				return
+			}
+		}
+		t.Fatal("message with metadata not found")
+	})
+
+	t.Run("state webhook", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"url":   "https://example.com/webhook",
+				"event": "message:state",
+			}).
+			Post("webhooks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		// the event is delivered by the server, not by devices
+		var webhooks []map[string]any
+		res, err = publicMobileClient.R().
+			SetAuthToken(credentials.Token).
+			SetResult(&webhooks).
+			Get("webhooks")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+		if len(webhooks) != 0 {
+			t.Fatalf("expected no webhooks for device, got %s", res.String())
+		}
+
+		updateMessageState(t, credentials.Token, map[string]any{
+			"id":    "metadata-order",
+			"state": "Sent",
+			"recipients": []map[string]any{
+				{"phoneNumber": "+79999999999", "state": "Sent"},
+			},
+		})
+
+		res, err = client.R().Get("messages/metadata-order")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var state messageState
+		if err := json.Unmarshal(res.Body(), &state); err != nil {
+			t.Fatal(err)
+		}
+		if state.State != "Sent" || state.Metadata["orderId"] != "12345" {
+			t.Fatalf("unexpected state: %s", res.String())
+		}
+	})
+
+	cases := []struct {
+		name     string
+		query    map[string][]string
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"metadata-other", "metadata-order"},
+		},
+		{
+			name:     "by tag",
+			query:    map[string][]string{"tag": {"orders"}},
+			expected: []string{"metadata-order"},
+		},
+		{
+			name:     "by all tags",
+			query:    map[string][]string{"tag": {"orders", "notifications"}},
+			expected: []string{"metadata-order"},
+		},
+		{
+			name:     "by metadata",
+			query:    map[string][]string{"metadata": {"orderId:12345"}},
+			expected: []string{"metadata-order"},
+		},
+		{
+			name:     "by metadata mismatch",
+			query:    map[string][]string{"metadata": {"orderId:54321"}},
+			expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := client.R().
+				SetQueryParamsFromValues(c.query).
+				Get("messages")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != 200 {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+
+			var states []messageState
+			if err := json.Unmarshal(res.Body(), &states); err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]string, len(states))
+			for i, s := range states {
+				ids[i] = s.ID
+			}
+			if len(ids) != len(c.expected) {
+				t.Fatalf("expected %v, got %v", c.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != c.expected[i] {
+					t.Fatalf("expected %v, got %v", c.expected, ids)
+				}
+			}
+		})
+	}
+}
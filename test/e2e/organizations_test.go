@@ -0,0 +1,233 @@
+package e2e
+
+import (
+	"testing"
+)
+
+func TestOrganizations(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	type member struct {
+		UserID   string `json:"userId"`
+		Role     string `json:"role"`
+		Password string `json:"password"`
+	}
+
+	addMember := func(role string) member {
+		var m member
+		res, err := publicUserClient.R().
+			SetBasicAuth(credentials.Login, credentials.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"role": role}).
+			SetResult(&m).
+			Post("organization/members")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 || m.UserID == "" || m.Password == "" || m.Role != role {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+		return m
+	}
+
+	request := func(m member, method, path string, body any) int {
+		req := publicUserClient.R().SetBasicAuth(m.UserID, m.Password)
+		if body != nil {
+			req.SetHeader("Content-Type", "application/json").SetBody(body)
+		}
+		res, err := req.Execute(method, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode()
+	}
+
+	userCode := func(login, password string) string {
+		var code struct {
+			Code string `json:"code"`
+		}
+		res, err := publicMobileClient.R().
+			SetBasicAuth(login, password).
+			SetResult(&code).
+			Get("user/code")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+		return code.Code
+	}
+
+	var org struct {
+		ID   string `json:"id"`
+		Role string `json:"role"`
+	}
+	res, err := publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&org).
+		Get("organization")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || org.ID != credentials.Login || org.Role != "owner" {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	viewer := addMember("viewer")
+	sender := addMember("sender")
+	admin := addMember("admin")
+
+	message := map[string]any{"message": "test", "phoneNumbers": []string{"+79999999999"}}
+
+	cases := []struct {
+		name     string
+		member   member
+		method   string
+		path     string
+		body     any
+		expected int
+	}{
+		{"viewer lists devices", viewer, "GET", "devices", nil, 200},
+		{"viewer sends message", viewer, "POST", "messages", message, 403},
+		{"sender sends message", sender, "POST", "messages", message, 202},
+		{"sender changes settings", sender, "PATCH", "settings", map[string]any{}, 403},
+		{"sender adds member", sender, "POST", "organization/members", map[string]any{"role": "viewer"}, 403},
+		{"admin changes settings", admin, "PATCH", "settings", map[string]any{}, 200},
+		{"admin adds owner", admin, "POST", "organization/members", map[string]any{"role": "owner"}, 403},
+		{"admin demotes owner", admin, "PATCH", "organization/members/" + credentials.Login, map[string]any{"role": "viewer"}, 403},
+		{"admin promotes viewer", admin, "PATCH", "organization/members/" + viewer.UserID, map[string]any{"role": "sender"}, 204},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if status := request(c.member, c.method, c.path, c.body); status != c.expected {
+				t.Fatalf("expected %d, got %d", c.expected, status)
+			}
+		})
+	}
+
+	t.Run("shared resources", func(t *testing.T) {
+		var devices []map[string]any
+		res, err := publicUserClient.R().
+			SetBasicAuth(sender.UserID, sender.Password).
+			SetResult(&devices).
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || len(devices) != 1 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		owner := member{UserID: credentials.Login, Password: credentials.Password}
+		if status := request(owner, "DELETE", "organization/members/"+credentials.Login, nil); status != 409 {
+			t.Fatalf("expected 409, got %d", status)
+		}
+	})
+
+	t.Run("removed member", func(t *testing.T) {
+		if status := request(admin, "DELETE", "organization/members/"+sender.UserID, nil); status != 204 {
+			t.Fatalf("expected 204, got %d", status)
+		}
+		if status := request(sender, "GET", "devices", nil); status != 403 {
+			t.Fatalf("expected 403, got %d", status)
+		}
+	})
+
+	t.Run("foreign organization", func(t *testing.T) {
+		res, err := publicUserClient.R().
+			SetBasicAuth(viewer.UserID, viewer.Password).
+			SetHeader("X-Organization-ID", "UNKNOWN").
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 403 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("existing user", func(t *testing.T) {
+		other := mobileDeviceRegister(t, publicMobileClient)
+		existing := member{UserID: other.Login, Password: other.Password}
+
+		add := func(code string) (member, int) {
+			var m member
+			res, err := publicUserClient.R().
+				SetBasicAuth(credentials.Login, credentials.Password).
+				SetHeader("Content-Type", "application/json").
+				SetBody(map[string]any{"role": "viewer", "code": code}).
+				SetResult(&m).
+				Post("organization/members")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return m, res.StatusCode()
+		}
+
+		if _, status := add("000000"); status != 403 {
+			t.Fatalf("expected 403 with invalid code, got %d", status)
+		}
+
+		m, status := add(userCode(other.Login, other.Password))
+		if status != 201 || m.UserID != other.Login || m.Password != "" {
+			t.Fatalf("unexpected response: %d %+v", status, m)
+		}
+		if _, status := add(userCode(other.Login, other.Password)); status != 409 {
+			t.Fatalf("expected 409 for a member, got %d", status)
+		}
+
+		// the user keeps its own organization and joins the other one
+		res, err := publicUserClient.R().
+			SetBasicAuth(existing.UserID, existing.Password).
+			SetHeader("X-Organization-ID", org.ID).
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("account without membership", func(t *testing.T) {
+		if status := request(admin, "PATCH", "organization/members/"+admin.UserID, map[string]any{"role": "owner"}); status != 403 {
+			t.Fatalf("expected 403, got %d", status)
+		}
+		owner := member{UserID: credentials.Login, Password: credentials.Password}
+		if status := request(owner, "PATCH", "organization/members/"+admin.UserID, map[string]any{"role": "owner"}); status != 204 {
+			t.Fatalf("expected 204, got %d", status)
+		}
+		if status := request(admin, "DELETE", "organization/members/"+credentials.Login, nil); status != 204 {
+			t.Fatalf("expected 204, got %d", status)
+		}
+
+		// account credentials still register devices in the organization
+		res, err := publicMobileClient.R().
+			SetBasicAuth(credentials.Login, credentials.Password).
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"name": "Second Device"}).
+			Post("device")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 201 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var devices []map[string]any
+		res, err = publicUserClient.R().
+			SetBasicAuth(admin.UserID, admin.Password).
+			SetResult(&devices).
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 || len(devices) != 2 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+}
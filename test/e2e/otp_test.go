@@ -0,0 +1,136 @@
+package e2e
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+type otpVerifyResponse struct {
+	Verified     bool `json:"verified"`
+	AttemptsLeft int  `json:"attemptsLeft"`
+}
+
+func TestOTP(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"phoneNumber": "+79999999999",
+			"template":    "Code: {{code}}",
+			"length":      4,
+		}).
+		Post("otp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var sent struct {
+		ID        string `json:"id"`
+		MessageID string `json:"messageId"`
+	}
+	if err := json.Unmarshal(res.Body(), &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	code := ""
+	for _, m := range selectPending(t, credentials.Token) {
+		if m.ID == sent.MessageID {
+			code = regexp.MustCompile(`^Code: (\d{4})$`).FindStringSubmatch(m.Message)[1]
+		}
+	}
+	if code == "" {
+		t.Fatal("message with code not found")
+	}
+
+	verify := func(code string) (int, otpVerifyResponse) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"id": sent.ID, "code": code}).
+			Post("otp/verify")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := otpVerifyResponse{}
+		if res.StatusCode() == 200 {
+			if err := json.Unmarshal(res.Body(), &out); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return res.StatusCode(), out
+	}
+
+	wrong := "0000"
+	if code == wrong {
+		wrong = "1111"
+	}
+
+	if status, out := verify(wrong); status != 200 || out.Verified || out.AttemptsLeft != 4 {
+		t.Fatalf("unexpected result for wrong code: %d %+v", status, out)
+	}
+	if status, out := verify(code); status != 200 || !out.Verified {
+		t.Fatalf("unexpected result for valid code: %d %+v", status, out)
+	}
+	if status, _ := verify(code); status != 404 {
+		t.Fatalf("used code is accepted: %d", status)
+	}
+}
+
+func TestOTPLockout(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	send := func() (int, string) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"phoneNumber": "+79999999998", "template": "{{code}}"}).
+			Post("otp")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sent struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(res.Body(), &sent)
+
+		return res.StatusCode(), sent.ID
+	}
+
+	status, id := send()
+	if status != 202 {
+		t.Fatal(status)
+	}
+
+	for i := range 5 {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"id": id, "code": "1234567"}).
+			Post("otp/verify")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := 200
+		if i == 4 {
+			expected = 429
+			if res.Header().Get("Retry-After") == "" {
+				t.Fatal("Retry-After is not set")
+			}
+		}
+		if res.StatusCode() != expected {
+			t.Fatal(i, res.StatusCode(), res.String())
+		}
+	}
+
+	if status, _ := send(); status != 429 {
+		t.Fatalf("locked out recipient got %d", status)
+	}
+}
@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"testing"
+)
+
+func TestSendingLimit(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "limit-first",
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999998", "+79999999999"},
+	})
+
+	res, err := publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody([]map[string]any{
+			{
+				"id":    "limit-first",
+				"state": "Sent",
+				"recipients": []map[string]any{
+					{"phoneNumber": "+79999999998", "state": "Sent"},
+					{"phoneNumber": "+79999999999", "state": "Sent"},
+				},
+			},
+		}).
+		Patch("message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var devices []device
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&devices).
+		Get("devices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || len(devices) != 1 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var usage struct {
+		Limit *struct {
+			Period string `json:"period"`
+			Value  int    `json:"value"`
+		} `json:"limit"`
+		Total struct {
+			PerDay int `json:"perDay"`
+		} `json:"total"`
+	}
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetResult(&usage).
+		Get("devices/" + devices[0].ID + "/usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 || usage.Limit != nil || usage.Total.PerDay != 2 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"messages": map[string]any{
+				"limit_period": "PerDay",
+				"limit_value":  2,
+			},
+		}).
+		Patch("settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	res, err = publicUserClient.R().
+		SetBasicAuth(credentials.Login, credentials.Password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+		}).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 429 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
@@ -0,0 +1,131 @@
+package e2e
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type recipientStatesResponse struct {
+	Recipients []struct {
+		PhoneNumber string  `json:"phoneNumber"`
+		State       string  `json:"state"`
+		Error       *string `json:"error"`
+		States      []struct {
+			State     string    `json:"state"`
+			Error     *string   `json:"error"`
+			UpdatedAt time.Time `json:"updatedAt"`
+		} `json:"states"`
+	} `json:"recipients"`
+}
+
+func updateMessageState(t *testing.T, token string, state map[string]any) {
+	res, err := publicMobileClient.R().
+		SetAuthToken(token).
+		SetHeader("Content-Type", "application/json").
+		SetBody([]map[string]any{state}).
+		Patch("message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func TestRecipientStatesHistory(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "recipient-states",
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999998", "+79999999999"},
+	})
+
+	processedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sentAt := processedAt.Add(time.Minute)
+	finishedAt := sentAt.Add(time.Minute)
+
+	// the device reports the accepted message as pending, it's stored as processed
+	updateMessageState(t, credentials.Token, map[string]any{
+		"id":     "recipient-states",
+		"state":  "Pending",
+		"states": map[string]any{"Processed": processedAt},
+		"recipients": []map[string]any{
+			{"phoneNumber": "+79999999998", "state": "Pending"},
+			{"phoneNumber": "+79999999999", "state": "Pending"},
+		},
+	})
+	updateMessageState(t, credentials.Token, map[string]any{
+		"id":     "recipient-states",
+		"state":  "Sent",
+		"states": map[string]any{"Processed": processedAt, "Sent": sentAt},
+		"recipients": []map[string]any{
+			{"phoneNumber": "+79999999998", "state": "Sent"},
+			{"phoneNumber": "+79999999999", "state": "Sent"},
+		},
+	})
+	updateMessageState(t, credentials.Token, map[string]any{
+		"id":     "recipient-states",
+		"state":  "Failed",
+		"states": map[string]any{"Processed": processedAt, "Sent": sentAt, "Delivered": finishedAt, "Failed": finishedAt},
+		"recipients": []map[string]any{
+			{"phoneNumber": "+79999999998", "state": "Delivered"},
+			{"phoneNumber": "+79999999999", "state": "Failed", "error": "timeout"},
+		},
+	})
+
+	res, err := client.R().Get("messages/recipient-states")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var state recipientStatesResponse
+	if err := json.Unmarshal(res.Body(), &state); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"+79999999998": {"Processed", "Sent", "Delivered"},
+		"+79999999999": {"Processed", "Sent", "Failed"},
+	}
+	expectedTimes := []time.Time{processedAt, sentAt, finishedAt}
+
+	if len(state.Recipients) != len(expected) {
+		t.Fatalf("expected %d recipients, got %d", len(expected), len(state.Recipients))
+	}
+
+	for _, r := range state.Recipients {
+		states, ok := expected[r.PhoneNumber]
+		if !ok {
+			t.Fatalf("unexpected recipient %s", r.PhoneNumber)
+		}
+
+		if len(r.States) != len(states) {
+			t.Fatalf("expected %d states of %s, got %+v", len(states), r.PhoneNumber, r.States)
+		}
+
+		for i, s := range r.States {
+			if s.State != states[i] {
+				t.Errorf("expected state %s of %s at position %d, got %s", states[i], r.PhoneNumber, i, s.State)
+			}
+			if !s.UpdatedAt.Equal(expectedTimes[i]) {
+				t.Errorf("expected %s of %s at %s, got %s", s.State, r.PhoneNumber, expectedTimes[i], s.UpdatedAt)
+			}
+		}
+
+		last := r.States[len(r.States)-1]
+		if last.State == "Failed" {
+			if last.Error == nil || *last.Error != "timeout" {
+				t.Errorf("expected error of %s to be timeout, got %v", r.PhoneNumber, last.Error)
+			}
+		} else if last.Error != nil {
+			t.Errorf("expected no error of %s, got %s", r.PhoneNumber, *last.Error)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package e2e
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type messageState struct {
+	ID         string `json:"id"`
+	State      string `json:"state"`
+	Recipients []struct {
+		PhoneNumber string `json:"phoneNumber"`
+		State       string `json:"state"`
+	} `json:"recipients"`
+	RetryOf  *string           `json:"retryOf"`
+	Retries  []string          `json:"retries"`
+	Metadata map[string]string `json:"metadata"`
+	Tags     []string          `json:"tags"`
+}
+
+func TestMessageRetry(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "retry-original",
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999998", "+79999999999"},
+	})
+
+	res, err := publicMobileClient.R().
+		SetAuthToken(credentials.Token).
+		SetHeader("Content-Type", "application/json").
+		SetBody([]map[string]any{
+			{
+				"id":    "retry-original",
+				"state": "Failed",
+				"recipients": []map[string]any{
+					{"phoneNumber": "+79999999998", "state": "Delivered"},
+					{"phoneNumber": "+79999999999", "state": "Failed", "error": "timeout"},
+				},
+			},
+		}).
+		Patch("message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 204 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	cases := []struct {
+		name               string
+		id                 string
+		body               string
+		expectedStatusCode int
+	}{
+		{
+			name:               "unknown message",
+			id:                 "retry-unknown",
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "unknown device",
+			id:                 "retry-original",
+			body:               `{"deviceId": "unknown"}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "failed recipients",
+			id:                 "retry-original",
+			expectedStatusCode: 202,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := client.R()
+			if c.body != "" {
+				req = req.SetHeader("Content-Type", "application/json").SetBody(c.body)
+			}
+
+			res, err := req.Post("messages/" + c.id + "/retry")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode() != c.expectedStatusCode {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+
+			if c.expectedStatusCode != 202 {
+				return
+			}
+
+			var retry messageState
+			if err := json.Unmarshal(res.Body(), &retry); err != nil {
+				t.Fatal(err)
+			}
+
+			if retry.RetryOf == nil || *retry.RetryOf != "retry-original" {
+				t.Errorf("expected retryOf to be retry-original, got %v", retry.RetryOf)
+			}
+			if len(retry.Recipients) != 1 || retry.Recipients[0].PhoneNumber != "+79999999999" {
+				t.Errorf("expected only the failed recipient, got %v", retry.Recipients)
+			}
+
+			res, err = client.R().Get("messages/retry-original")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var original messageState
+			if err := json.Unmarshal(res.Body(), &original); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(original.Retries) != 1 || original.Retries[0] != retry.ID {
+				t.Errorf("expected retries to be [%s], got %v", retry.ID, original.Retries)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type mobileMessage struct {
+	ID        string            `json:"id"`
+	Message   string            `json:"message"`
+	Priority  int               `json:"priority"`
+	SimNumber *uint8            `json:"simNumber"`
+	Metadata  map[string]string `json:"metadata"`
+	Tags      []string          `json:"tags"`
+}
+
+func enqueueMessage(t *testing.T, login, password string, req map[string]any) {
+	res, err := publicUserClient.R().
+		SetBasicAuth(login, password).
+		SetHeader("Content-Type", "application/json").
+		SetBody(req).
+		Post("messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode() != 202 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+}
+
+func selectPending(t *testing.T, token string) []mobileMessage {
+	res, err := publicMobileClient.R().
+		SetAuthToken(token).
+		Get("message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.IsSuccess() {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	var messages []mobileMessage
+	if err := json.Unmarshal(res.Body(), &messages); err != nil {
+		t.Fatal(err)
+	}
+
+	return messages
+}
+
+// The scheduling tests rely on the `aging` policy with 1 second interval set in
+// data/config.yml of the e2e servers, the default `strict` policy hands out the
+// newest messages first.
+func TestSchedulingFIFOWithinPriority(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	for i := range 3 {
+		enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+			"id":           fmt.Sprintf("fifo-%d", i),
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+		})
+	}
+
+	messages := selectPending(t, credentials.Token)
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+
+	for i, m := range messages {
+		if expected := fmt.Sprintf("fifo-%d", i); m.ID != expected {
+			t.Errorf("expected %s at position %d, got %s", expected, i, m.ID)
+		}
+	}
+}
+
+func TestSchedulingNoStarvation(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "low-priority",
+		"message":      "test",
+		"phoneNumbers": []string{"+79999999999"},
+		"priority":     0,
+	})
+
+	// let the low priority message age for more than one priority point
+	time.Sleep(3 * time.Second)
+
+	// fill the whole selection window with higher priority messages
+	for i := range 100 {
+		enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+			"id":           fmt.Sprintf("high-priority-%d", i),
+			"message":      "test",
+			"phoneNumbers": []string{"+79999999999"},
+			"priority":     1,
+		})
+	}
+
+	messages := selectPending(t, credentials.Token)
+	if len(messages) == 0 {
+		t.Fatal("expected pending messages, got none")
+	}
+
+	if messages[0].ID != "low-priority" {
+		t.Fatalf("expected aged low priority message to be selected first, got %s", messages[0].ID)
+	}
+}
@@ -0,0 +1,156 @@
+package e2e
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type heldMessageState struct {
+	ID         string     `json:"id"`
+	ScheduleAt *time.Time `json:"scheduleAt"`
+}
+
+func TestSendingWindow(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+	client := publicUserClient.SetBasicAuth(credentials.Login, credentials.Password)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// the window is closed for Moscow numbers for the next two hours
+	now := time.Now().In(moscow)
+	window := map[string]string{
+		"start": now.Add(2 * time.Hour).Format("15:04"),
+		"end":   now.Add(3 * time.Hour).Format("15:04"),
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":            "window-held",
+		"message":       "Sale!",
+		"phoneNumbers":  []string{"+79999999999"},
+		"sendingWindow": window,
+	})
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":              "window-transactional",
+		"message":         "Your code is 1234",
+		"phoneNumbers":    []string{"+79999999999"},
+		"isTransactional": true,
+	})
+
+	res, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{
+			"messages": map[string]any{
+				"sending_window_start": window["start"],
+				"sending_window_end":   window["end"],
+			},
+		}).
+		Patch("settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode() != 200 {
+		t.Fatal(res.StatusCode(), res.String())
+	}
+
+	enqueueMessage(t, credentials.Login, credentials.Password, map[string]any{
+		"id":           "window-default",
+		"message":      "Sale!",
+		"phoneNumbers": []string{"+79999999999"},
+	})
+
+	pending := map[string]bool{}
+	for _, m := range selectPending(t, credentials.Token) {
+		pending[m.ID] = true
+	}
+	if pending["window-held"] || pending["window-default"] {
+		t.Fatalf("held messages returned: %v", pending)
+	}
+	if !pending["window-transactional"] {
+		t.Fatalf("transactional message not returned: %v", pending)
+	}
+
+	for _, id := range []string{"window-held", "window-default"} {
+		res, err := client.R().Get("messages/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 200 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		var state heldMessageState
+		if err := json.Unmarshal(res.Body(), &state); err != nil {
+			t.Fatal(err)
+		}
+		if state.ScheduleAt == nil || time.Until(*state.ScheduleAt) < time.Hour {
+			t.Fatalf("unexpected schedule time: %s", res.String())
+		}
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		for _, id := range []string{"window-default", "window-transactional"} {
+			updateMessageState(t, credentials.Token, map[string]any{
+				"id":    id,
+				"state": "Failed",
+				"recipients": []map[string]any{
+					{"phoneNumber": "+79999999999", "state": "Failed", "error": "timeout"},
+				},
+			})
+
+			res, err := client.R().Post("messages/" + id + "/retry")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode() != 202 {
+				t.Fatal(res.StatusCode(), res.String())
+			}
+
+			var state heldMessageState
+			if err := json.Unmarshal(res.Body(), &state); err != nil {
+				t.Fatal(err)
+			}
+
+			held := state.ScheduleAt != nil && time.Until(*state.ScheduleAt) >= time.Hour
+			if expected := id == "window-default"; held != expected {
+				t.Fatalf("expected retry of %s to be held: %t, got %s", id, expected, res.String())
+			}
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"messages": map[string]any{"sending_window_start": "9am"},
+			}).
+			Patch("settings")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 400 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+
+	t.Run("transactional with window", func(t *testing.T) {
+		res, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{
+				"message":         "test",
+				"phoneNumbers":    []string{"+79999999999"},
+				"isTransactional": true,
+				"sendingWindow":   window,
+			}).
+			Post("messages")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 400 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"testing"
+)
+
+func TestAuthTokens(t *testing.T) {
+	credentials := mobileDeviceRegister(t, publicMobileClient)
+
+	type tokenPair struct {
+		AccessToken  string `json:"accessToken"`
+		TokenType    string `json:"tokenType"`
+		ExpiresIn    int    `json:"expiresIn"`
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	token := func(body map[string]any, basic bool) (tokenPair, int) {
+		var pair tokenPair
+		req := publicUserClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			SetResult(&pair)
+		if basic {
+			req.SetBasicAuth(credentials.Login, credentials.Password)
+		}
+
+		res, err := req.Post("auth/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pair, res.StatusCode()
+	}
+
+	get := func(accessToken string) int {
+		res, err := publicUserClient.R().
+			SetAuthToken(accessToken).
+			Get("devices")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode()
+	}
+
+	if _, status := token(map[string]any{"grantType": "password"}, false); status != 401 {
+		t.Fatalf("expected 401 without credentials, got %d", status)
+	}
+	if _, status := token(map[string]any{"grantType": "refresh_token"}, false); status != 400 {
+		t.Fatalf("expected 400 without refresh token, got %d", status)
+	}
+
+	first, status := token(map[string]any{"grantType": "password"}, true)
+	if status != 200 || first.AccessToken == "" || first.RefreshToken == "" || first.TokenType != "Bearer" || first.ExpiresIn <= 0 {
+		t.Fatalf("unexpected response: %d %+v", status, first)
+	}
+
+	if status := get(first.AccessToken); status != 200 {
+		t.Fatalf("expected 200 with access token, got %d", status)
+	}
+	if status := get(first.AccessToken + "x"); status != 401 {
+		t.Fatalf("expected 401 with tampered token, got %d", status)
+	}
+
+	second, status := token(map[string]any{"grantType": "refresh_token", "refreshToken": first.RefreshToken}, false)
+	if status != 200 || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("unexpected refresh response: %d %+v", status, second)
+	}
+	if status := get(second.AccessToken); status != 200 {
+		t.Fatalf("expected 200 with refreshed token, got %d", status)
+	}
+
+	// reuse of the rotated token revokes the whole family
+	if _, status := token(map[string]any{"grantType": "refresh_token", "refreshToken": first.RefreshToken}, false); status != 401 {
+		t.Fatalf("expected 401 on refresh token reuse, got %d", status)
+	}
+	if _, status := token(map[string]any{"grantType": "refresh_token", "refreshToken": second.RefreshToken}, false); status != 401 {
+		t.Fatalf("expected 401 after reuse detection, got %d", status)
+	}
+
+	t.Run("revoke", func(t *testing.T) {
+		pair, status := token(map[string]any{"grantType": "password"}, true)
+		if status != 200 {
+			t.Fatalf("expected 200, got %d", status)
+		}
+
+		res, err := publicUserClient.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(map[string]any{"refreshToken": pair.RefreshToken}).
+			Post("auth/revoke")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode() != 204 {
+			t.Fatal(res.StatusCode(), res.String())
+		}
+
+		if _, status := token(map[string]any{"grantType": "refresh_token", "refreshToken": pair.RefreshToken}, false); status != 401 {
+			t.Fatalf("expected 401 with revoked token, got %d", status)
+		}
+	})
+}